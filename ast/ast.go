@@ -14,6 +14,12 @@ type Type interface {
 	_type()
 }
 
+// Pattern 表示绑定变量的模式，出现在变量声明、函数参数和 foreach 语句的头部。
+// 最简单的模式是一个变量名，列表模式和对象模式则从值中解构出多个变量。
+type Pattern interface {
+	pattern()
+}
+
 // ExpectExpr 函数接受一个表达式，并期望该表达式是特定类型 T。
 // 该函数使用了泛型，允许在调用时指定具体的表达式类型。
 //
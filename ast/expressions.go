@@ -20,6 +20,34 @@ type StringExpr struct {
 
 func (n StringExpr) expr() {}
 
+// TemplateExpr 表示一个模板字符串，例如 `Hello ${user.name}`。
+// Parts 按出现顺序保存各个片段：字面量片段为 StringExpr，插值片段为任意表达式。
+type TemplateExpr struct {
+	Parts []Expr
+}
+
+func (n TemplateExpr) expr() {}
+
+// RegexExpr 表示一个正则表达式字面量，例如 /(?P<year>\d{4})-\d{2}/i。
+//   - Pattern: 两个 "/" 之间的正则表达式（RE2 语法），其中的 "\/" 保持原样。
+//   - Flags: 结尾的标志，见 lexer.RegexFlags，没有时为空字符串。
+type RegexExpr struct {
+	Pattern string
+	Flags   string
+}
+
+func (n RegexExpr) expr() {}
+
+type BooleanExpr struct {
+	Value bool
+}
+
+func (n BooleanExpr) expr() {}
+
+type NullExpr struct{}
+
+func (n NullExpr) expr() {}
+
 type SymbolExpr struct {
 	Value string
 }
@@ -38,8 +66,11 @@ type BinaryExpr struct {
 
 func (n BinaryExpr) expr() {}
 
+// AssignmentExpr 表示赋值表达式。Operator 为赋值运算符，
+// 可以是 = 或 +=、-=、*=、/=、%= 等复合赋值运算符。
 type AssignmentExpr struct {
 	Assigne       Expr
+	Operator      lexer.Token
 	AssignedValue Expr
 }
 
@@ -52,6 +83,18 @@ type PrefixExpr struct {
 
 func (n PrefixExpr) expr() {}
 
+// TypeTestExpr 表示运行时的类型检查，例如 shape is Circle、value is string | null 或 x instanceof Printable。
+//   - Value: 被检查的值。
+//   - Operator: is 或 instanceof。is 可以检查任意类型，instanceof 只能检查类和接口。
+//   - Type: 检查的类型。
+type TypeTestExpr struct {
+	Value    Expr
+	Operator lexer.Token
+	Type     Type
+}
+
+func (n TypeTestExpr) expr() {}
+
 type MemberExpr struct {
 	Member   Expr
 	Property string
@@ -59,13 +102,34 @@ type MemberExpr struct {
 
 func (n MemberExpr) expr() {}
 
+// CallExpr 表示函数调用，例如 resize(img, ...sizes, width: 3)。
+//
+// 字段:
+// - TypeArguments: 显式给出的类型实参，例如 id<string>("a") 中的 string，省略时为 nil。
+// - Arguments: 按位置传递的实参，其中可以包含展开实参 SpreadExpr。
+// - NamedArguments: 按参数名传递的实参，写在全部位置实参之后。
 type CallExpr struct {
-	Method    Expr
-	Arguments []Expr
+	Method         Expr
+	TypeArguments  []Type
+	Arguments      []Expr
+	NamedArguments []NamedArgument
 }
 
 func (n CallExpr) expr() {}
 
+// NamedArgument 表示按参数名传递的实参，例如 f(width: 3) 中的 width: 3。
+type NamedArgument struct {
+	Name  string
+	Value Expr
+}
+
+// SpreadExpr 表示调用中的展开实参 ...xs，把列表或元组的元素依次作为位置实参传入。
+type SpreadExpr struct {
+	Argument Expr
+}
+
+func (n SpreadExpr) expr() {}
+
 type ComputedExpr struct {
 	Member   Expr
 	Property Expr
@@ -80,11 +144,11 @@ type RangeExpr struct {
 
 func (n RangeExpr) expr() {}
 
-// FunctionExpr 表示一个函数表达式。
+// FunctionExpr 表示一个函数表达式，fn (x: number) { ... } 和箭头函数 (x, y) -> x + y 都会解析为 FunctionExpr。
 //
 // 字段:
-// - Parameters: 函数的参数列表，包含多个 Parameter 类型的元素。
-// - Body: 函数的主体，由多个 Stmt 类型的元素组成，表示函数的执行语句。
+// - Parameters: 函数的参数列表，包含多个 Parameter 类型的元素。箭头函数的参数可以省略类型，此时 Type 为 nil。
+// - Body: 函数的主体，由多个 Stmt 类型的元素组成，表示函数的执行语句。函数体为表达式的箭头函数只包含一条 ReturnStmt。
 // - ReturnType: 函数的返回类型，表示函数执行后返回的值的类型。
 type FunctionExpr struct {
 	Parameters []Parameter
@@ -100,8 +164,49 @@ type ArrayLiteral struct {
 
 func (n ArrayLiteral) expr() {}
 
+// MapEntry 表示字典字面量中的一个键值对。
+// 标识符键和带引号的键都会被解析为 StringExpr，计算键 [expr] 保存为其内部的表达式。
+type MapEntry struct {
+	Key   Expr
+	Value Expr
+}
+
+// MapLiteral 表示一个字典字面量，例如 { key: value, "quoted": v, [computed]: v }。
+type MapLiteral struct {
+	Entries []MapEntry
+}
+
+func (n MapLiteral) expr() {}
+
+// ChannelExpr 表示创建通道，例如 chan<number>() 或带缓冲区的 chan<string>(10)。
+// Capacity 为缓冲区的容量，省略时为 nil，表示无缓冲通道。
+type ChannelExpr struct {
+	Element  Type
+	Capacity Expr
+}
+
+func (n ChannelExpr) expr() {}
+
+// ReceiveExpr 表示从通道接收一个值，例如 <-ch。
+type ReceiveExpr struct {
+	Channel Expr
+}
+
+func (n ReceiveExpr) expr() {}
+
+// SendExpr 表示向通道发送一个值，例如 ch <- value。
+type SendExpr struct {
+	Channel Expr
+	Value   Expr
+}
+
+func (n SendExpr) expr() {}
+
+// NewExpr 表示类的实例化，例如 new Box<number>(1)。
+// TypeArguments 为显式给出的类型实参，省略时由类型检查器根据构造函数的参数推断。
 type NewExpr struct {
 	Instantiation CallExpr
+	TypeArguments []Type
 }
 
 func (n NewExpr) expr() {}
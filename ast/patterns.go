@@ -0,0 +1,34 @@
+package ast
+
+// IdentifierPattern 把整个值绑定到变量 Name，例如 let x = 1; 中的 x。
+type IdentifierPattern struct {
+	Name string
+}
+
+func (n IdentifierPattern) pattern() {}
+
+// ArrayPattern 按位置解构列表或元组，例如 let [a, b, ...rest] = arr;。
+//
+// 字段:
+// - Elements: 依次绑定第 0、1、... 个元素的模式。
+// - Rest: 绑定剩余元素组成的列表的模式；没有 ...rest 时为 nil。
+type ArrayPattern struct {
+	Elements []Pattern
+	Rest     Pattern
+}
+
+func (n ArrayPattern) pattern() {}
+
+// PropertyPattern 表示对象模式中的一项，把成员 Key 的值绑定到 Value。
+// 简写 {x} 等价于 {x: x}。
+type PropertyPattern struct {
+	Key   string
+	Value Pattern
+}
+
+// ObjectPattern 按成员名解构实例或字典，例如 let {x, y: alias} = obj;。
+type ObjectPattern struct {
+	Properties []PropertyPattern
+}
+
+func (n ObjectPattern) pattern() {}
@@ -9,12 +9,12 @@ func (b BlockStmt) stmt() {}
 // VarDeclarationStmt 表示一个变量声明语句。
 //
 // 字段:
-// - Identifier: 变量的标识符名称。
+// - Pattern: 被声明的变量。let x 中为 IdentifierPattern，let [a, b] 和 let {x, y} 中为解构模式。
 // - Constant: 一个布尔值，指示变量是否为常量。如果为 true，则表示该变量为常量。
 // - AssignedValue: 变量的初始赋值表达式。
 // - ExplicitType: 变量的显式类型。如果未指定类型，则可能为 nil。
 type VarDeclarationStmt struct {
-	Identifier    string
+	Pattern       Pattern
 	Constant      bool
 	AssignedValue Expr
 	ExplicitType  Type
@@ -28,16 +28,31 @@ type ExpressionStmt struct {
 
 func (n ExpressionStmt) stmt() {}
 
+// Parameter 表示函数的一个参数。参数也可以是解构模式，例如 fn f([x, y]: (number, number))。
+//
+// 字段:
+// - Default: 参数的默认值，调用时没有传入该参数才会求值；没有默认值时为 nil。
+// - Rest: 是否为剩余参数 ...args: []T。剩余参数只能是最后一个参数，它接收多出的全部位置实参。
 type Parameter struct {
-	Name string
-	Type Type
+	Pattern Pattern
+	Type    Type
+	Default Expr
+	Rest    bool
 }
 
-type FunctionDeclarationStmt struct {
-	Parameters []Parameter
+// TypeParameter 表示泛型声明中的一个类型参数，例如 <T, U: Comparable> 中的 T 和 U。
+// Constraint 为类型参数的约束，未指定时为 nil。
+type TypeParameter struct {
 	Name       string
-	Body       []Stmt
-	ReturnType Type
+	Constraint Type
+}
+
+type FunctionDeclarationStmt struct {
+	Parameters     []Parameter
+	TypeParameters []TypeParameter
+	Name           string
+	Body           []Stmt
+	ReturnType     Type
 }
 
 func (n FunctionDeclarationStmt) stmt() {}
@@ -50,6 +65,88 @@ type IfStmt struct {
 
 func (n IfStmt) stmt() {}
 
+// ReturnStmt 表示 return 语句。Value 为返回值，省略时为 nil。
+type ReturnStmt struct {
+	Value Expr
+}
+
+func (n ReturnStmt) stmt() {}
+
+// ThrowStmt 表示 throw 语句，Value 为被抛出的值。
+type ThrowStmt struct {
+	Value Expr
+}
+
+func (n ThrowStmt) stmt() {}
+
+// CatchClause 表示 try 语句中的 catch 子句，例如 catch (e: Error) { ... }。
+// Name 为捕获到的错误绑定的变量名，省略绑定时为空字符串；Type 为可选的类型注解。
+type CatchClause struct {
+	Name string
+	Type Type
+	Body []Stmt
+}
+
+// TryStmt 表示 try/catch/finally 语句。Catch 和 Finally 至少有一个不为 nil。
+type TryStmt struct {
+	Body    []Stmt
+	Catch   *CatchClause
+	Finally []Stmt
+}
+
+func (n TryStmt) stmt() {}
+
+// SpawnStmt 表示 spawn 语句，例如 spawn worker(ch, 1)。
+// 被调用的函数和实参在当前 goroutine 中求值，调用本身在新的 goroutine 中执行。
+type SpawnStmt struct {
+	Call CallExpr
+}
+
+func (n SpawnStmt) stmt() {}
+
+// SelectCase 表示 select 语句中的一个 case 分支。
+//
+// 字段:
+// - Operation: 分支等待的通道操作，为接收 <-ch（ReceiveExpr）或发送 ch <- v（SendExpr）。
+// - Binding: case let v = <-ch 中接收到的值绑定的模式，不绑定时为 nil。
+// - Body: 该分支被选中后执行的语句。
+type SelectCase struct {
+	Operation Expr
+	Binding   Pattern
+	Body      []Stmt
+}
+
+// SelectStmt 表示 select 语句，它等待多个通道操作中的任意一个可以进行。
+// Default 为 default 分支的语句，没有 default 分支时为 nil；有 default 分支时 select 不会阻塞。
+type SelectStmt struct {
+	Cases   []SelectCase
+	Default []Stmt
+}
+
+func (n SelectStmt) stmt() {}
+
+// SwitchCase 表示 switch 语句中的一个 case 分支。
+//
+// 字段:
+// - Values: case 1, 2 中与被比较的值逐个用 == 比较的表达式；类型分支中为 nil。
+// - Type: case is T 中的类型 T，值分支中为 nil。
+// - Body: 该分支被选中后执行的语句。
+type SwitchCase struct {
+	Values []Expr
+	Type   Type
+	Body   []Stmt
+}
+
+// SwitchStmt 表示 switch 语句。Value 只求值一次，之后按顺序选中第一个匹配的分支，分支之间不会贯穿；
+// 没有分支匹配时执行 Default，没有 default 分支时为 nil。
+type SwitchStmt struct {
+	Value   Expr
+	Cases   []SwitchCase
+	Default []Stmt
+}
+
+func (n SwitchStmt) stmt() {}
+
 type ImportStmt struct {
 	Name string
 	From string
@@ -57,18 +154,71 @@ type ImportStmt struct {
 
 func (n ImportStmt) stmt() {}
 
+// ForeachStmt 表示 foreach 循环，例如 foreach v in xs 或 foreach i, v in xs。
+//
+// 字段:
+// - Index: 下标变量的名称（遍历字典时为键）；只写了一个变量时为空字符串。
+// - Value: 绑定每个元素的模式，可以是解构模式，例如 foreach [k, v] in pairs。
 type ForeachStmt struct {
-	Value    string
-	Index    bool
+	Index    string
+	Value    Pattern
 	Iterable Expr
 	Body     []Stmt
 }
 
 func (n ForeachStmt) stmt() {}
 
-type ClassDeclarationStmt struct {
+// TypeAliasStmt 表示一个类型别名声明，例如 type Id = number;。
+type TypeAliasStmt struct {
 	Name string
-	Body []Stmt
+	Type Type
+}
+
+func (n TypeAliasStmt) stmt() {}
+
+// EnumMember 表示枚举中的一个成员。Value 为显式给出的值，省略时为 nil，
+// 此时成员的值为前一个数字成员的值加一（第一个成员为 0）。
+type EnumMember struct {
+	Name  string
+	Value Expr
+}
+
+// EnumDeclarationStmt 表示一个枚举声明，例如 enum Color { Red, Green = 2, Blue }。
+type EnumDeclarationStmt struct {
+	Name    string
+	Members []EnumMember
+}
+
+func (n EnumDeclarationStmt) stmt() {}
+
+// InterfaceField 表示接口中声明的一个字段，例如 name: string。
+type InterfaceField struct {
+	Name string
+	Type Type
+}
+
+// InterfaceMethod 表示接口中声明的一个方法签名，例如 area(): number。
+type InterfaceMethod struct {
+	Name       string
+	Parameters []Parameter
+	ReturnType Type
+}
+
+// InterfaceDeclarationStmt 表示一个接口声明，例如 interface Shape { name: string; area(): number }。
+// 接口按结构匹配：任何拥有全部字段和方法的类都满足该接口，无需显式声明。
+type InterfaceDeclarationStmt struct {
+	Name           string
+	TypeParameters []TypeParameter
+	Fields         []InterfaceField
+	Methods        []InterfaceMethod
+}
+
+func (n InterfaceDeclarationStmt) stmt() {}
+
+type ClassDeclarationStmt struct {
+	Name           string
+	TypeParameters []TypeParameter
+	Body           []Stmt
 }
 
 func (n ClassDeclarationStmt) stmt() {}
@@ -13,3 +13,60 @@ type ListType struct {
 }
 
 func (t ListType) _type() {}
+
+// MapType 表示一个字典类型，可以写作 map[K]V 或 {K: V}。
+// Key 为键的类型，Value 为值的类型。
+type MapType struct {
+	Key   Type
+	Value Type
+}
+
+func (t MapType) _type() {}
+
+// FunctionType 表示一个函数类型，例如 fn(number, string): bool。
+// Rest 为剩余参数的类型，例如 fn(...[]number) 中的 []number，没有剩余参数时为 nil。
+// ReturnType 为 nil 时表示函数没有返回值。
+type FunctionType struct {
+	Parameters []Type
+	Rest       Type
+	ReturnType Type
+}
+
+func (t FunctionType) _type() {}
+
+// ChannelType 表示一个通道类型，例如 chan<number>。Element 为通道中传递的值的类型。
+type ChannelType struct {
+	Element Type
+}
+
+func (t ChannelType) _type() {}
+
+// UnionType 表示一个联合类型，例如 A | B | C。连续的 | 会被展开到同一个 Members 列表中。
+type UnionType struct {
+	Members []Type
+}
+
+func (t UnionType) _type() {}
+
+// OptionalType 表示一个可空类型，例如 T?，等价于 T | null。
+type OptionalType struct {
+	Underlying Type
+}
+
+func (t OptionalType) _type() {}
+
+// TupleType 表示一个元组类型，例如 (A, B)。
+type TupleType struct {
+	Members []Type
+}
+
+func (t TupleType) _type() {}
+
+// GenericType 表示对泛型类型的实例化，例如 Map<string, number>。
+// Name 为泛型类型的名称，Arguments 为按顺序给出的类型实参。
+type GenericType struct {
+	Name      string
+	Arguments []Type
+}
+
+func (t GenericType) _type() {}
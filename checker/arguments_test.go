@@ -0,0 +1,75 @@
+package checker_test
+
+import "testing"
+
+// argumentDecls 是参数测试共用的声明。
+const argumentDecls = `
+	fn sum(...xs: []number): number { let total = 0; foreach x in xs { total += x; } return total; }
+	fn box(width: number, height: number = 10, label: string = "box"): string { return label; }
+`
+
+func TestArguments(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "rest parameter",
+			source: argumentDecls + `let n: number = sum(); n = sum(1, 2, 3);`,
+		},
+		{
+			name:   "rest arguments are checked",
+			source: argumentDecls + `sum(1, "2");`,
+			err:    "expected number but got string",
+		},
+		{
+			name:   "rest parameter must be a list",
+			source: `fn f(...xs: number) {}`,
+			err:    "rest parameter xs must have a list type, not number",
+		},
+		{
+			name:   "default values",
+			source: argumentDecls + `box(1); box(1, 2); box(1, 2, "b");`,
+		},
+		{
+			name:   "missing required argument",
+			source: argumentDecls + `box();`,
+			err:    "wrong number of arguments in call to",
+		},
+		{
+			name:   "too many arguments",
+			source: argumentDecls + `box(1, 2, "b", 4);`,
+			err:    "wrong number of arguments in call to",
+		},
+		{
+			name:   "spread",
+			source: argumentDecls + `let xs: []number = [1, 2]; sum(...xs); sum(0, ...xs);`,
+		},
+		{
+			name:   "spread of the wrong element type",
+			source: argumentDecls + `let xs: []string = ["a"]; sum(...xs);`,
+			err:    "expected number but got string",
+		},
+		{
+			name:   "spread of a non-list",
+			source: argumentDecls + `sum(...1);`,
+			err:    "cannot spread number in call to",
+		},
+		{
+			name:   "named arguments",
+			source: argumentDecls + `box(1, label: "b"); box(height: 2, width: 1);`,
+		},
+		{
+			name:   "unknown named argument",
+			source: argumentDecls + `box(1, depth: 2);`,
+			err:    "unknown parameter depth in call to",
+		},
+		{
+			name:   "named argument given twice",
+			source: argumentDecls + `box(1, width: 2);`,
+			err:    "parameter width given more than once in call to",
+		},
+		{
+			name:   "named argument type is checked",
+			source: argumentDecls + `box(1, label: 2);`,
+			err:    "expected string but got number",
+		},
+	})
+}
@@ -0,0 +1,149 @@
+package checker
+
+import "dreamlang/ast"
+
+// assignments 记录一段代码中被赋值的变量名：all 为所有被赋值的变量，captured 为在嵌套的函数
+// （函数字面量、函数声明和方法）中被赋值的变量。这些函数可能在任何时候被调用，因此 captured 中的变量不能被收窄。
+// 只按名称记录，不区分同名的不同变量，因此结果偏保守。
+type assignments struct {
+	all      map[string]bool
+	captured map[string]bool
+}
+
+// collectAssignments 返回语句列表 body 中的赋值。
+func collectAssignments(body []ast.Stmt) assignments {
+	a := assignments{all: make(map[string]bool), captured: make(map[string]bool)}
+	a.stmts(body, false)
+
+	return a
+}
+
+func (a assignments) stmts(body []ast.Stmt, inFunction bool) {
+	for _, stmt := range body {
+		a.stmt(stmt, inFunction)
+	}
+}
+
+func (a assignments) stmt(stmt ast.Stmt, inFunction bool) {
+	switch stmt := stmt.(type) {
+	case ast.BlockStmt:
+		a.stmts(stmt.Body, inFunction)
+	case ast.ExpressionStmt:
+		a.expr(stmt.Expression, inFunction)
+	case ast.VarDeclarationStmt:
+		a.expr(stmt.AssignedValue, inFunction)
+	case ast.FunctionDeclarationStmt:
+		a.function(stmt.Parameters, stmt.Body)
+	case ast.ClassDeclarationStmt:
+		for _, member := range stmt.Body {
+			a.stmt(member, true)
+		}
+	case ast.IfStmt:
+		a.expr(stmt.Condition, inFunction)
+		a.stmt(stmt.Consequent, inFunction)
+		if stmt.Alternate != nil {
+			a.stmt(stmt.Alternate, inFunction)
+		}
+	case ast.SwitchStmt:
+		a.expr(stmt.Value, inFunction)
+		for _, switchCase := range stmt.Cases {
+			for _, value := range switchCase.Values {
+				a.expr(value, inFunction)
+			}
+			a.stmts(switchCase.Body, inFunction)
+		}
+		a.stmts(stmt.Default, inFunction)
+	case ast.ForeachStmt:
+		a.expr(stmt.Iterable, inFunction)
+		a.stmts(stmt.Body, inFunction)
+	case ast.ReturnStmt:
+		a.expr(stmt.Value, inFunction)
+	case ast.ThrowStmt:
+		a.expr(stmt.Value, inFunction)
+	case ast.TryStmt:
+		a.stmts(stmt.Body, inFunction)
+		if stmt.Catch != nil {
+			a.stmts(stmt.Catch.Body, inFunction)
+		}
+		a.stmts(stmt.Finally, inFunction)
+	case ast.SpawnStmt:
+		a.expr(stmt.Call, inFunction)
+	case ast.SelectStmt:
+		for _, selectCase := range stmt.Cases {
+			a.expr(selectCase.Operation, inFunction)
+			a.stmts(selectCase.Body, inFunction)
+		}
+		a.stmts(stmt.Default, inFunction)
+	}
+}
+
+func (a assignments) function(params []ast.Parameter, body []ast.Stmt) {
+	for _, param := range params {
+		a.expr(param.Default, true)
+	}
+	a.stmts(body, true)
+}
+
+func (a assignments) expr(expr ast.Expr, inFunction bool) {
+	switch expr := expr.(type) {
+	case ast.AssignmentExpr:
+		if symbol, ok := expr.Assigne.(ast.SymbolExpr); ok {
+			a.all[symbol.Value] = true
+			if inFunction {
+				a.captured[symbol.Value] = true
+			}
+		} else {
+			a.expr(expr.Assigne, inFunction)
+		}
+		a.expr(expr.AssignedValue, inFunction)
+	case ast.FunctionExpr:
+		a.function(expr.Parameters, expr.Body)
+	case ast.TemplateExpr:
+		for _, part := range expr.Parts {
+			a.expr(part, inFunction)
+		}
+	case ast.BinaryExpr:
+		a.expr(expr.Left, inFunction)
+		a.expr(expr.Right, inFunction)
+	case ast.PrefixExpr:
+		a.expr(expr.Right, inFunction)
+	case ast.TypeTestExpr:
+		a.expr(expr.Value, inFunction)
+	case ast.MemberExpr:
+		a.expr(expr.Member, inFunction)
+	case ast.ComputedExpr:
+		a.expr(expr.Member, inFunction)
+		a.expr(expr.Property, inFunction)
+	case ast.CallExpr:
+		a.expr(expr.Method, inFunction)
+		for _, argument := range expr.Arguments {
+			a.expr(argument, inFunction)
+		}
+		for _, argument := range expr.NamedArguments {
+			a.expr(argument.Value, inFunction)
+		}
+	case ast.NewExpr:
+		a.expr(expr.Instantiation, inFunction)
+	case ast.SpreadExpr:
+		a.expr(expr.Argument, inFunction)
+	case ast.RangeExpr:
+		a.expr(expr.Lower, inFunction)
+		a.expr(expr.Upper, inFunction)
+	case ast.ArrayLiteral:
+		for _, element := range expr.Contents {
+			a.expr(element, inFunction)
+		}
+	case ast.MapLiteral:
+		for _, entry := range expr.Entries {
+			a.expr(entry.Key, inFunction)
+			a.expr(entry.Value, inFunction)
+		}
+	case ast.ChannelExpr:
+		a.expr(expr.Capacity, inFunction)
+	case ast.ReceiveExpr:
+		a.expr(expr.Channel, inFunction)
+	case ast.SendExpr:
+		a.expr(expr.Channel, inFunction)
+		a.expr(expr.Value, inFunction)
+	}
+}
@@ -0,0 +1,61 @@
+package checker
+
+import "dreamlang/ast"
+
+// channelElement 返回通道类型 t 的元素类型。t 不是通道时报告错误并返回 Any，what 描述进行的操作。
+func (c *Checker) channelElement(t Type, what string) Type {
+	switch t := t.(type) {
+	case *ChannelType:
+		return t.Element
+	default:
+		if t != Any {
+			c.errorf("cannot %s non-channel %s", what, t)
+		}
+		return Any
+	}
+}
+
+func (c *Checker) checkChannelExpr(expr ast.ChannelExpr) Type {
+	if expr.Capacity != nil {
+		c.expectType(Number, c.checkExpr(expr.Capacity, Number), "channel capacity")
+	}
+
+	return &ChannelType{Element: c.resolveType(expr.Element)}
+}
+
+func (c *Checker) checkReceiveExpr(expr ast.ReceiveExpr) Type {
+	return c.channelElement(c.checkExpr(expr.Channel, nil), "receive from")
+}
+
+// checkSendExpr 检查 ch <- v。发送没有结果，因此它只能作为语句或 select 的分支使用。
+func (c *Checker) checkSendExpr(expr ast.SendExpr) Type {
+	element := c.channelElement(c.checkExpr(expr.Channel, nil), "send to")
+	c.expectType(element, c.checkExpr(expr.Value, element), "value sent to channel")
+
+	return Void
+}
+
+// checkSpawn 检查 spawn 语句中的调用。被调用的函数的返回值会被丢弃。
+func (c *Checker) checkSpawn(stmt ast.SpawnStmt) {
+	c.checkCallExpr(stmt.Call, nil)
+}
+
+// checkSelect 检查 select 语句。每个分支有自己的作用域，接收分支绑定的值的类型为通道的元素类型。
+func (c *Checker) checkSelect(stmt ast.SelectStmt) {
+	for _, selectCase := range stmt.Cases {
+		operationType := c.checkExpr(selectCase.Operation, nil)
+
+		c.pushScope()
+		if selectCase.Binding != nil {
+			c.declarePattern(selectCase.Binding, operationType, false)
+		}
+		c.checkStatements(selectCase.Body)
+		c.popScope()
+	}
+
+	if stmt.Default != nil {
+		c.pushScope()
+		c.checkStatements(stmt.Default)
+		c.popScope()
+	}
+}
@@ -0,0 +1,251 @@
+package checker
+
+import (
+	"fmt"
+
+	"dreamlang/ast"
+)
+
+// TypeError 表示类型检查发现的一个错误。
+type TypeError struct {
+	Message string
+}
+
+func (e *TypeError) Error() string {
+	return "type error: " + e.Message
+}
+
+// functionContext 记录正在检查的函数体的返回信息。
+// inferReturn 为 true 时函数没有声明返回类型，返回类型由 return 语句推断，推断结果收集在 returns 中。
+type functionContext struct {
+	name        string
+	returnType  Type
+	inferReturn bool
+	returns     []Type
+}
+
+// Checker 保存类型检查过程中的状态：当前作用域、正在检查的函数、已收集的错误、
+// 正在进行的结构匹配（用于在递归接口之间比较时避免无限递归），以及可以导入的模块。
+type Checker struct {
+	scope    *Scope
+	function *functionContext
+	errors   []error
+	assuming map[string]bool
+	modules  map[string]*ModuleType
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		scope:    newUniverseScope(),
+		assuming: make(map[string]bool),
+		modules:  make(map[string]*ModuleType),
+	}
+}
+
+// Check 对解析得到的整个程序进行类型检查。
+//
+// 参数:
+//   - program: parser.Parse 返回的顶层代码块。
+//
+// 返回值:
+//   - []error: 按发现顺序排列的全部 *TypeError；程序类型正确时返回 nil。
+//
+// 与解析器不同，类型检查器不会在第一个错误处停止，而是报告错误后以 Any 继续检查，
+// 这样一次运行就能得到尽可能多的诊断信息。
+func Check(program ast.BlockStmt) []error {
+	c := NewChecker()
+	c.checkStatements(program.Body)
+
+	return c.errors
+}
+
+// Declare 在顶层作用域中声明一个常量，例如宿主程序通过嵌入接口注册的函数和全局变量。同名的声明会被覆盖。
+func (c *Checker) Declare(name string, t Type) {
+	c.scope.values[name] = &Symbol{Name: name, Type: t, Constant: true}
+}
+
+// DeclareModule 声明一个可以通过 import 语句导入的模块。同名的模块会被覆盖。
+func (c *Checker) DeclareModule(m *ModuleType) {
+	c.modules[m.Name] = m
+}
+
+// Check 检查 program，返回本次检查发现的错误。与包级函数 Check 不同，同一个 Checker 可以依次检查多个程序：
+// 检查通过时，program 的顶层声明会保留在顶层作用域中，之后的程序可以使用它们，也可以重新声明同名的变量和类型；
+// 检查失败时，本次的声明全部被丢弃。
+func (c *Checker) Check(program ast.BlockStmt) []error {
+	c.errors = nil
+	c.pushScope()
+	c.checkStatements(program.Body)
+	declared := c.scope
+	c.popScope()
+
+	if len(c.errors) > 0 {
+		return c.errors
+	}
+
+	for name, symbol := range declared.values {
+		c.scope.values[name] = symbol
+	}
+	for name, t := range declared.types {
+		c.scope.types[name] = t
+	}
+
+	return nil
+}
+
+func (c *Checker) errorf(format string, args ...any) {
+	c.errors = append(c.errors, &TypeError{Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *Checker) pushScope() {
+	c.scope = newScope(c.scope)
+}
+
+func (c *Checker) popScope() {
+	c.scope = c.scope.parent
+}
+
+func (c *Checker) declareValue(name string, t Type, constant bool) {
+	original, narrowed := c.scope.narrowed[name]
+	if _, exists := c.scope.values[name]; exists && !(narrowed && original == nil) {
+		c.errorf("%s redeclared in this scope", name)
+	}
+	delete(c.scope.narrowed, name)
+
+	c.scope.values[name] = &Symbol{Name: name, Type: t, Constant: constant}
+}
+
+func (c *Checker) declareType(name string, t Type) {
+	if _, exists := c.scope.types[name]; exists {
+		c.errorf("type %s redeclared in this scope", name)
+	}
+
+	c.scope.types[name] = t
+}
+
+// declareTypeParameters 为泛型声明创建类型参数并在当前作用域中声明它们。
+// 约束在全部类型参数声明之后才解析，因此约束中可以引用同一列表中的其它类型参数。
+func (c *Checker) declareTypeParameters(params []ast.TypeParameter) []*TypeParameter {
+	typeParams := make([]*TypeParameter, len(params))
+
+	for i, param := range params {
+		typeParams[i] = &TypeParameter{Name: param.Name}
+		c.declareType(param.Name, typeParams[i])
+	}
+
+	for i, param := range params {
+		if param.Constraint != nil {
+			typeParams[i].Constraint = c.resolveType(param.Constraint)
+		}
+	}
+
+	return typeParams
+}
+
+// resolveType 把源代码中的类型语法 ast.Type 解析为语义类型。
+// 未知的类型名会报告错误并解析为 Any。
+func (c *Checker) resolveType(t ast.Type) Type {
+	switch t := t.(type) {
+	case nil:
+		return Any
+	case ast.SymbolType:
+		resolved, exists := c.scope.lookupType(t.Value)
+		if !exists {
+			c.errorf("undefined type %s", t.Value)
+			return Any
+		}
+
+		if class, ok := resolved.(*ClassType); ok {
+			if len(class.TypeParameters) > 0 {
+				c.errorf("generic %s requires %d type argument(s)", class, len(class.TypeParameters))
+				return c.instantiateClass(class, nil)
+			}
+			return &InstanceType{Class: class}
+		}
+
+		return resolved
+	case ast.GenericType:
+		resolved, exists := c.scope.lookupType(t.Name)
+		if !exists {
+			c.errorf("undefined type %s", t.Name)
+			return Any
+		}
+
+		class, ok := resolved.(*ClassType)
+		if !ok || len(class.TypeParameters) == 0 {
+			c.errorf("type %s does not take type arguments", t.Name)
+			return resolved
+		}
+
+		typeArgs := make([]Type, len(t.Arguments))
+		for i, arg := range t.Arguments {
+			typeArgs[i] = c.resolveType(arg)
+		}
+
+		if len(typeArgs) != len(class.TypeParameters) {
+			c.errorf("generic %s requires %d type argument(s) but %d were given", class, len(class.TypeParameters), len(typeArgs))
+			return c.instantiateClass(class, nil)
+		}
+
+		return c.instantiateClass(class, typeArgs)
+	case ast.ListType:
+		return &ListType{Element: c.resolveType(t.Underlying)}
+	case ast.MapType:
+		return &MapType{Key: c.resolveType(t.Key), Value: c.resolveType(t.Value)}
+	case ast.ChannelType:
+		return &ChannelType{Element: c.resolveType(t.Element)}
+	case ast.TupleType:
+		members := make([]Type, len(t.Members))
+		for i, member := range t.Members {
+			members[i] = c.resolveType(member)
+		}
+		return &TupleType{Members: members}
+	case ast.UnionType:
+		members := make([]Type, len(t.Members))
+		for i, member := range t.Members {
+			members[i] = c.resolveType(member)
+		}
+		return NewUnion(members...)
+	case ast.OptionalType:
+		return NewUnion(c.resolveType(t.Underlying), Null)
+	case ast.FunctionType:
+		params := make([]Type, len(t.Parameters))
+		for i, param := range t.Parameters {
+			params[i] = c.resolveType(param)
+		}
+
+		returnType := Type(Void)
+		if t.ReturnType != nil {
+			returnType = c.resolveType(t.ReturnType)
+		}
+
+		signature := &FunctionType{Parameters: params, ReturnType: returnType}
+		if t.Rest != nil {
+			rest := c.resolveType(t.Rest)
+			list, ok := rest.(*ListType)
+			if !ok {
+				c.errorf("rest parameter must have a list type, not %s", rest)
+				list = &ListType{Element: Any}
+			}
+			signature.Rest = list
+		}
+
+		return signature
+	default:
+		panic(fmt.Sprintf("checker: unsupported type node %T", t))
+	}
+}
+
+// instantiateClass 返回以 typeArgs 实例化 class 得到的实例类型，并检查类型实参是否满足约束。
+// typeArgs 为 nil 时，所有类型参数都以 Any 实例化。
+func (c *Checker) instantiateClass(class *ClassType, typeArgs []Type) *InstanceType {
+	if typeArgs == nil {
+		typeArgs = make([]Type, len(class.TypeParameters))
+		for i := range typeArgs {
+			typeArgs[i] = Any
+		}
+	}
+
+	c.checkConstraints(class.TypeParameters, typeArgs)
+	return &InstanceType{Class: class, TypeArguments: typeArgs}
+}
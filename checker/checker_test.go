@@ -0,0 +1,45 @@
+package checker_test
+
+import (
+	"strings"
+	"testing"
+
+	"dreamlang/checker"
+	"dreamlang/parser"
+)
+
+// checkSource 解析并检查 source，返回所有类型错误组成的字符串，没有错误时为空。
+func checkSource(t *testing.T, source string) string {
+	t.Helper()
+
+	messages := make([]string, 0)
+	for _, err := range checker.Check(parser.Parse(source)) {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// checkTest 是一个类型检查的测试用例。err 为空时程序应当通过检查，否则为期望的错误信息中的一部分。
+type checkTest struct {
+	name   string
+	source string
+	err    string
+}
+
+// runCheckTests 以子测试的形式检查 tests 中的每个程序。
+func runCheckTests(t *testing.T, tests []checkTest) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := checkSource(t, test.source)
+			if test.err == "" && got != "" {
+				t.Fatalf("unexpected errors:\n%s", got)
+			}
+			if test.err != "" && !strings.Contains(got, test.err) {
+				t.Fatalf("errors = %q, want %q", got, test.err)
+			}
+		})
+	}
+}
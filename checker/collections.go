@@ -0,0 +1,135 @@
+package checker
+
+// listMember 返回元素类型为 element 的列表的内置成员 name 的类型。
+//
+// 列表的方法:
+//   - length: number（只读）
+//   - push(...items: T): number，返回新的长度
+//   - pop(): T，列表为空时抛出错误
+//   - slice(start: number, end?: number): []T
+//   - map<U>(f: fn(T, number): U): []U
+//   - filter(f: fn(T, number): bool): []T
+//   - reduce<U>(f: fn(U, T, number): U, initial: U): U
+//   - sort(compare?: fn(T, T): number): []T，原地排序并返回列表本身
+//   - find(f: fn(T, number): bool): T | null
+//   - includes(value: T): bool
+//   - reverse(): []T，原地反转并返回列表本身
+//   - zip<U>(other: []U): [](T, U)
+//   - enumerate(): [](number, T)
+//
+// 回调的第二个参数为元素的下标，回调可以忽略它。
+func listMember(element Type, name string) (Type, bool) {
+	list := &ListType{Element: element}
+	predicate := &FunctionType{Parameters: []Type{element, Number}, ReturnType: Bool}
+
+	switch name {
+	case "length":
+		return Number, true
+	case "push":
+		return &FunctionType{ParameterNames: []string{}, Rest: list, ReturnType: Number}, true
+	case "pop":
+		return &FunctionType{ReturnType: element}, true
+	case "slice":
+		return &FunctionType{Parameters: []Type{Number, Number}, ParameterNames: []string{"start", "end"}, Optional: 1, ReturnType: list}, true
+	case "map":
+		u := &TypeParameter{Name: "U"}
+		return &FunctionType{
+			TypeParameters: []*TypeParameter{u},
+			Parameters:     []Type{&FunctionType{Parameters: []Type{element, Number}, ReturnType: u}},
+			ParameterNames: []string{"f"},
+			ReturnType:     &ListType{Element: u},
+		}, true
+	case "filter":
+		return &FunctionType{Parameters: []Type{predicate}, ParameterNames: []string{"f"}, ReturnType: list}, true
+	case "reduce":
+		u := &TypeParameter{Name: "U"}
+		return &FunctionType{
+			TypeParameters: []*TypeParameter{u},
+			Parameters:     []Type{&FunctionType{Parameters: []Type{u, element, Number}, ReturnType: u}, u},
+			ParameterNames: []string{"f", "initial"},
+			ReturnType:     u,
+		}, true
+	case "sort":
+		compare := &FunctionType{Parameters: []Type{element, element}, ReturnType: Number}
+		return &FunctionType{Parameters: []Type{compare}, ParameterNames: []string{"compare"}, Optional: 1, ReturnType: list}, true
+	case "find":
+		return &FunctionType{Parameters: []Type{predicate}, ParameterNames: []string{"f"}, ReturnType: NewUnion(element, Null)}, true
+	case "includes":
+		return &FunctionType{Parameters: []Type{element}, ParameterNames: []string{"value"}, ReturnType: Bool}, true
+	case "reverse":
+		return &FunctionType{ReturnType: list}, true
+	case "zip":
+		u := &TypeParameter{Name: "U"}
+		return &FunctionType{
+			TypeParameters: []*TypeParameter{u},
+			Parameters:     []Type{&ListType{Element: u}},
+			ParameterNames: []string{"other"},
+			ReturnType:     &ListType{Element: &TupleType{Members: []Type{element, u}}},
+		}, true
+	case "enumerate":
+		return &FunctionType{ReturnType: &ListType{Element: &TupleType{Members: []Type{Number, element}}}}, true
+	}
+
+	return nil, false
+}
+
+// SetClass 是内置的泛型类 Set<T>，按插入顺序保存不重复的元素：new Set<T>(values?: []T)。
+// size 为元素个数（只读）。
+var SetClass = func() *ClassType {
+	t := &TypeParameter{Name: "T"}
+	value := []string{"value"}
+
+	return &ClassType{
+		Name:           "Set",
+		TypeParameters: []*TypeParameter{t},
+		Fields:         map[string]Type{"size": Number},
+		Methods: map[string]*FunctionType{
+			"add":    {Parameters: []Type{t}, ParameterNames: value, ReturnType: Void},
+			"has":    {Parameters: []Type{t}, ParameterNames: value, ReturnType: Bool},
+			"delete": {Parameters: []Type{t}, ParameterNames: value, ReturnType: Bool},
+			"clear":  {ReturnType: Void},
+			"values": {ReturnType: &ListType{Element: t}},
+		},
+		Constructor: &FunctionType{Parameters: []Type{&ListType{Element: t}}, ParameterNames: []string{"values"}, Optional: 1, ReturnType: Void},
+	}
+}()
+
+// MapClass 是内置的泛型类 Map<K, V>，按插入顺序保存键值对：new Map<K, V>(entries?: [](K, V))。
+// 与字典 map[K]V 不同，它的键值对只能通过方法访问；size 为键值对的个数（只读）。
+var MapClass = func() *ClassType {
+	k := &TypeParameter{Name: "K"}
+	v := &TypeParameter{Name: "V"}
+	key := []string{"key"}
+	entry := &TupleType{Members: []Type{k, v}}
+
+	return &ClassType{
+		Name:           "Map",
+		TypeParameters: []*TypeParameter{k, v},
+		Fields:         map[string]Type{"size": Number},
+		Methods: map[string]*FunctionType{
+			"set":     {Parameters: []Type{k, v}, ParameterNames: []string{"key", "value"}, ReturnType: Void},
+			"get":     {Parameters: []Type{k}, ParameterNames: key, ReturnType: NewUnion(v, Null)},
+			"has":     {Parameters: []Type{k}, ParameterNames: key, ReturnType: Bool},
+			"delete":  {Parameters: []Type{k}, ParameterNames: key, ReturnType: Bool},
+			"clear":   {ReturnType: Void},
+			"keys":    {ReturnType: &ListType{Element: k}},
+			"values":  {ReturnType: &ListType{Element: v}},
+			"entries": {ReturnType: &ListType{Element: entry}},
+		},
+		Constructor: &FunctionType{Parameters: []Type{&ListType{Element: entry}}, ParameterNames: []string{"entries"}, Optional: 1, ReturnType: Void},
+	}
+}()
+
+// readOnlyMember 判断 object.name 是否为不能赋值的内置成员：模块的成员、列表的成员、Set 和 Map 的 size，
+// 以及 Regex、RegexMatch 和 Type 的字段。
+func readOnlyMember(object Type, name string) bool {
+	switch object := object.(type) {
+	case *ModuleType, *ListType:
+		return true
+	case *InstanceType:
+		return object.Class == SetClass || object.Class == MapClass || object.Class == RegexClass || object.Class == RegexMatchClass ||
+			object.Class == TypeClass
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package checker_test
+
+import "testing"
+
+func TestTypeDeclarations(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "type alias",
+			source: `type Id = number; let id: Id = 1; let n: number = id;`,
+		},
+		{
+			name:   "type alias is checked",
+			source: `type Id = number; let id: Id = "a";`,
+			err:    "cannot assign string to id",
+		},
+		{
+			name:   "enum with implicit and explicit values",
+			source: `enum Color { Red, Green = 5, Blue } let c: Color = Color.Green;`,
+		},
+		{
+			name:   "string enum",
+			source: `enum Color { Red = "red", Green = "green" } let c: Color = Color.Red;`,
+		},
+		{
+			name:   "enum member after a string member needs a value",
+			source: `enum Color { Red = "red", Green }`,
+			err:    "enum member Color.Green must have an explicit value",
+		},
+		{
+			name:   "duplicate enum member",
+			source: `enum Color { Red, Red }`,
+			err:    "enum member Color.Red redeclared",
+		},
+		{
+			name:   "unknown enum member",
+			source: `enum Color { Red } Color.Blue;`,
+			err:    "has no member Blue",
+		},
+		{
+			name: "class satisfies an interface structurally",
+			source: `
+				interface Shape { area(): number; }
+				class Square { let side: number = 1; fn area(): number { return this.side * this.side; } }
+				let s: Shape = new Square();
+				let a: number = s.area();`,
+		},
+		{
+			name: "class with a missing method",
+			source: `
+				interface Shape { area(): number; }
+				class Circle { let r: number = 1; }
+				let s: Shape = new Circle();`,
+			err: "cannot assign Circle to s of type Shape",
+		},
+		{
+			name: "class with a method of the wrong type",
+			source: `
+				interface Shape { area(): number; }
+				class Label { fn area(): string { return "big"; } }
+				let s: Shape = new Label();`,
+			err: "cannot assign Label to s of type Shape",
+		},
+		{
+			name:   "interface fields",
+			source: `interface Point { x: number; y: number; } class P { let x: number = 0; let y: number = 0; } let p: Point = new P();`,
+		},
+		{
+			name:   "interfaces cannot be instantiated",
+			source: `interface Shape { area(): number; } new Shape();`,
+			err:    "cannot instantiate interface Shape",
+		},
+	})
+}
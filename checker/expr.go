@@ -0,0 +1,730 @@
+package checker
+
+import (
+	"fmt"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+// checkExpr 推断表达式的类型。
+//
+// 参数:
+//   - expr: 要检查的表达式。
+//   - expected: 上下文期望的类型，可以为 nil。它只用于引导推断，例如空列表字面量的元素类型，
+//     并不会在这里检查可赋值性，可赋值性由调用方负责。
+//
+// 返回值:
+//   - Type: 表达式的类型。出错时报告错误并返回 Any，以免同一个错误被重复报告。
+func (c *Checker) checkExpr(expr ast.Expr, expected Type) Type {
+	switch expr := expr.(type) {
+	case ast.NumberExpr:
+		return Number
+	case ast.StringExpr:
+		return String
+	case ast.BooleanExpr:
+		return Bool
+	case ast.NullExpr:
+		return Null
+	case ast.RegexExpr:
+		return &InstanceType{Class: RegexClass}
+	case ast.TemplateExpr:
+		for _, part := range expr.Parts {
+			c.checkExpr(part, nil)
+		}
+		return String
+	case ast.SymbolExpr:
+		symbol, exists := c.scope.lookupValue(expr.Value)
+		if !exists {
+			c.errorf("undefined: %s", expr.Value)
+			return Any
+		}
+		return symbol.Type
+	case ast.ArrayLiteral:
+		return c.checkArrayLiteral(expr, expected)
+	case ast.MapLiteral:
+		return c.checkMapLiteral(expr, expected)
+	case ast.PrefixExpr:
+		return c.checkPrefixExpr(expr)
+	case ast.BinaryExpr:
+		return c.checkBinaryExpr(expr)
+	case ast.TypeTestExpr:
+		return c.checkTypeTestExpr(expr)
+	case ast.AssignmentExpr:
+		return c.checkAssignmentExpr(expr)
+	case ast.RangeExpr:
+		c.expectType(Number, c.checkExpr(expr.Lower, Number), "range lower bound")
+		c.expectType(Number, c.checkExpr(expr.Upper, Number), "range upper bound")
+		return &ListType{Element: Number}
+	case ast.MemberExpr:
+		return c.checkMemberExpr(expr)
+	case ast.ComputedExpr:
+		return c.checkComputedExpr(expr)
+	case ast.CallExpr:
+		return c.checkCallExpr(expr, expected)
+	case ast.NewExpr:
+		return c.checkNewExpr(expr)
+	case ast.FunctionExpr:
+		return c.checkFunctionExpr(expr, expected)
+	case ast.ChannelExpr:
+		return c.checkChannelExpr(expr)
+	case ast.ReceiveExpr:
+		return c.checkReceiveExpr(expr)
+	case ast.SendExpr:
+		return c.checkSendExpr(expr)
+	default:
+		panic(fmt.Sprintf("checker: unsupported expression %T", expr))
+	}
+}
+
+// checkFunctionExpr 检查函数字面量。省略了类型的参数取期望的函数类型 expected 中对应参数的类型；
+// expected 为联合类型时取其中唯一的函数类型，例如 string | fn(RegexMatch): string。
+// 没有期望的函数类型时无法推断，报告错误并作为 any 处理。
+func (c *Checker) checkFunctionExpr(expr ast.FunctionExpr, expected Type) Type {
+	signature := c.functionSignature(nil, expr.Parameters, expr.ReturnType)
+	contextual := contextualFunction(expected)
+
+	for i, param := range expr.Parameters {
+		if param.Type != nil {
+			continue
+		}
+
+		var inferred Type
+		if contextual != nil {
+			if param.Rest {
+				if contextual.Rest != nil {
+					signature.Rest = contextual.Rest
+				}
+				continue
+			}
+			inferred = contextual.parameterAt(i)
+		}
+
+		if inferred == nil {
+			c.errorf("cannot infer type of parameter %s: add a type annotation", patternString(param.Pattern))
+			continue
+		}
+		signature.Parameters[i] = inferred
+	}
+
+	c.checkFunctionBody("function literal", signature, expr.Parameters, expr.Body, expr.ReturnType == nil)
+	return signature
+}
+
+// contextualFunction 返回函数字面量的期望类型 expected 中的函数类型，没有或者不唯一时返回 nil。
+func contextualFunction(expected Type) *FunctionType {
+	switch expected := expected.(type) {
+	case *FunctionType:
+		return expected
+	case *UnionType:
+		var found *FunctionType
+		for _, member := range expected.Members {
+			if function, ok := member.(*FunctionType); ok {
+				if found != nil {
+					return nil
+				}
+				found = function
+			}
+		}
+		return found
+	}
+
+	return nil
+}
+
+// isContextSensitive 判断表达式的类型是否依赖于期望类型，即是否为省略了参数类型的函数字面量。
+func isContextSensitive(expr ast.Expr) bool {
+	function, ok := expr.(ast.FunctionExpr)
+	if !ok {
+		return false
+	}
+
+	for _, param := range function.Parameters {
+		if param.Type == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expectType 在 actual 不能赋值给 expected 时报告错误，what 描述出错的位置。
+func (c *Checker) expectType(expected Type, actual Type, what string) {
+	if !c.assignable(expected, actual) {
+		c.errorf("%s: expected %s but got %s%s", what, expected, actual, c.explainMismatch(expected, actual))
+	}
+}
+
+// checkArrayLiteral 推断列表字面量的类型。期望类型为等长的元组时按元组检查；有期望的列表类型时以其元素类型引导推断；
+// 否则元素类型为所有元素类型的联合，空列表的元素类型为 Any。
+func (c *Checker) checkArrayLiteral(expr ast.ArrayLiteral, expected Type) Type {
+	if tuple, ok := expected.(*TupleType); ok && len(tuple.Members) == len(expr.Contents) {
+		members := make([]Type, len(expr.Contents))
+		for i, element := range expr.Contents {
+			members[i] = c.checkExpr(element, tuple.Members[i])
+		}
+		return &TupleType{Members: members}
+	}
+
+	var expectedElement Type
+	if list, ok := expected.(*ListType); ok {
+		expectedElement = list.Element
+	}
+
+	elements := make([]Type, len(expr.Contents))
+	for i, element := range expr.Contents {
+		elements[i] = c.checkExpr(element, expectedElement)
+	}
+
+	if expectedElement != nil {
+		for _, element := range elements {
+			if !c.assignable(expectedElement, element) {
+				return &ListType{Element: NewUnion(elements...)}
+			}
+		}
+		return expected
+	}
+
+	if len(elements) == 0 {
+		return &ListType{Element: Any}
+	}
+
+	return &ListType{Element: NewUnion(elements...)}
+}
+
+// checkMapLiteral 推断字典字面量的类型，规则与 checkArrayLiteral 相同，标识符键和字符串键的类型为 string。
+func (c *Checker) checkMapLiteral(expr ast.MapLiteral, expected Type) Type {
+	var expectedKey, expectedValue Type
+	if m, ok := expected.(*MapType); ok {
+		expectedKey, expectedValue = m.Key, m.Value
+	}
+
+	keys := make([]Type, len(expr.Entries))
+	values := make([]Type, len(expr.Entries))
+	for i, entry := range expr.Entries {
+		keys[i] = c.checkExpr(entry.Key, expectedKey)
+		values[i] = c.checkExpr(entry.Value, expectedValue)
+	}
+
+	if expectedKey != nil {
+		fits := true
+		for i := range keys {
+			fits = fits && c.assignable(expectedKey, keys[i]) && c.assignable(expectedValue, values[i])
+		}
+		if fits {
+			return expected
+		}
+	}
+
+	if len(expr.Entries) == 0 {
+		return &MapType{Key: String, Value: Any}
+	}
+
+	return &MapType{Key: NewUnion(keys...), Value: NewUnion(values...)}
+}
+
+func (c *Checker) checkPrefixExpr(expr ast.PrefixExpr) Type {
+	operand := c.checkExpr(expr.Right, nil)
+
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolDash:
+		c.expectType(Number, operand, "operand of unary -")
+		return Number
+	case lexer.TokenTypeSymbolNot:
+		return Bool
+	case lexer.TokenTypeKeywordTypeof:
+		return &InstanceType{Class: TypeClass}
+	default:
+		c.errorf("unsupported prefix operator %s", lexer.TokenKindString(expr.Operator.Kind))
+		return Any
+	}
+}
+
+// checkBinaryExpr 检查二元运算。+ 可用于数字相加，也可用于字符串拼接（任一侧为字符串）；
+// 其它算术运算只接受数字；比较运算接受数字或字符串；相等性和逻辑运算接受任意类型。
+func (c *Checker) checkBinaryExpr(expr ast.BinaryExpr) Type {
+	left := c.checkExpr(expr.Left, nil)
+	operator := lexer.TokenKindString(expr.Operator.Kind)
+
+	// a && b 中的 b 只在 a 成立时求值，a || b 中的 b 只在 a 不成立时求值，因此 b 按 a 的结果收窄。
+	var right Type
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolAnd, lexer.TokenTypeSymbolOr:
+		c.withNarrowing(c.narrowCondition(expr.Left, expr.Operator.Kind == lexer.TokenTypeSymbolAnd), func() {
+			right = c.checkExpr(expr.Right, nil)
+		})
+	default:
+		right = c.checkExpr(expr.Right, nil)
+	}
+
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolPlus:
+		if left == String || right == String {
+			return String
+		}
+		if left == Any || right == Any {
+			return Any
+		}
+		c.expectType(Number, left, "left operand of +")
+		c.expectType(Number, right, "right operand of +")
+		return Number
+	case lexer.TokenTypeSymbolDash, lexer.TokenTypeSymbolStar, lexer.TokenTypeSymbolSlash, lexer.TokenTypeSymbolPercent, lexer.TokenTypeSymbolStarStar:
+		c.expectType(Number, left, "left operand of "+operator)
+		c.expectType(Number, right, "right operand of "+operator)
+		return Number
+	case lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolLTEQ, lexer.TokenTypeSymbolGT, lexer.TokenTypeSymbolGTEQ:
+		if !(c.assignable(Number, left) && c.assignable(Number, right)) && !(c.assignable(String, left) && c.assignable(String, right)) {
+			c.errorf("cannot compare %s %s %s", left, operator, right)
+		}
+		return Bool
+	case lexer.TokenTypeSymbolEqual, lexer.TokenTypeSymbolNotEqual, lexer.TokenTypeSymbolAnd, lexer.TokenTypeSymbolOr:
+		return Bool
+	default:
+		c.errorf("unsupported binary operator %s", operator)
+		return Any
+	}
+}
+
+// checkAssignmentExpr 检查赋值。复合赋值 x op= y 按 x = x op y 检查：
+// += 可用于数字或字符串，其它复合赋值只能用于数字。
+func (c *Checker) checkAssignmentExpr(expr ast.AssignmentExpr) Type {
+	var target Type
+	switch assigne := expr.Assigne.(type) {
+	case ast.SymbolExpr:
+		symbol, exists := c.scope.lookupValue(assigne.Value)
+		if exists && symbol.Constant {
+			c.errorf("cannot assign to constant %s", assigne.Value)
+		}
+		target = c.checkExpr(assigne, nil)
+		if exists && symbol.Declared != nil && expr.Operator.Kind == lexer.TokenTypeSymbolAssignment {
+			// 被收窄的变量可以被赋值为声明的类型允许的任意值，之后不再收窄。
+			target = symbol.Declared
+			defer c.widen(assigne.Value)
+		}
+	case ast.MemberExpr:
+		object := c.checkExpr(assigne.Member, nil)
+		if readOnlyMember(object, assigne.Property) {
+			c.errorf("cannot assign to member %s of %s", assigne.Property, object)
+		}
+		target = c.member(object, assigne.Property)
+	default:
+		target = c.checkExpr(assigne, nil)
+	}
+	value := c.checkExpr(expr.AssignedValue, target)
+
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolAssignment:
+		c.expectType(target, value, "assignment")
+	case lexer.TokenTypeSymbolPlusEqual:
+		if target == String {
+			if !c.assignable(String, value) && !c.assignable(Number, value) {
+				c.errorf("cannot append %s to string", value)
+			}
+			break
+		}
+		c.expectType(Number, target, "left operand of +=")
+		c.expectType(Number, value, "right operand of +=")
+	default:
+		operator := lexer.TokenKindString(expr.Operator.Kind)
+		c.expectType(Number, target, "left operand of "+operator)
+		c.expectType(Number, value, "right operand of "+operator)
+	}
+
+	return target
+}
+
+// memberType 返回类型 t 上名为 name 的成员的类型。泛型类实例的成员会以实例的类型实参进行替换。
+func (c *Checker) memberType(t Type, name string) (Type, bool) {
+	switch t := t.(type) {
+	case *InstanceType:
+		subst := classSubstitution(t)
+		if field, exists := t.Class.Fields[name]; exists {
+			return substitute(field, subst), true
+		}
+		if method, exists := t.Class.Methods[name]; exists {
+			return substitute(method, subst), true
+		}
+	case *MapType:
+		if c.assignable(t.Key, String) {
+			return t.Value, true
+		}
+	case *ListType:
+		return listMember(t.Element, name)
+	case *EnumObjectType:
+		if t.Enum.hasMember(name) {
+			return t.Enum, true
+		}
+	case *ModuleType:
+		if member, exists := t.Members[name]; exists {
+			return member, true
+		}
+	case *TypeParameter:
+		if t.Constraint != nil {
+			return c.memberType(t.Constraint, name)
+		}
+	}
+
+	if t == Any {
+		return Any, true
+	}
+
+	return nil, false
+}
+
+func (c *Checker) checkMemberExpr(expr ast.MemberExpr) Type {
+	return c.member(c.checkExpr(expr.Member, nil), expr.Property)
+}
+
+// member 返回 object.name 的类型，object 没有该成员时报告错误并返回 Any。
+func (c *Checker) member(object Type, name string) Type {
+	member, exists := c.memberType(object, name)
+	if !exists {
+		c.errorf("%s has no member %s", object, name)
+		return Any
+	}
+
+	return member
+}
+
+func (c *Checker) checkComputedExpr(expr ast.ComputedExpr) Type {
+	object := c.checkExpr(expr.Member, nil)
+
+	switch object := object.(type) {
+	case *ListType:
+		c.expectType(Number, c.checkExpr(expr.Property, Number), "list index")
+		return object.Element
+	case *MapType:
+		c.expectType(object.Key, c.checkExpr(expr.Property, object.Key), "map key")
+		return object.Value
+	case *TupleType:
+		index, ok := expr.Property.(ast.NumberExpr)
+		if !ok || index.Value != float64(int(index.Value)) || int(index.Value) < 0 || int(index.Value) >= len(object.Members) {
+			c.errorf("tuple %s must be indexed by a constant between 0 and %d", object, len(object.Members)-1)
+			return Any
+		}
+		return object.Members[int(index.Value)]
+	}
+
+	property := c.checkExpr(expr.Property, nil)
+	if object == String {
+		c.expectType(Number, property, "string index")
+		return String
+	}
+
+	if object != Any {
+		c.errorf("cannot index %s", object)
+	}
+
+	return Any
+}
+
+// checkCallExpr 检查调用表达式，expected 为调用结果的期望类型，用于推断只出现在返回类型中的类型参数。
+// 调用显式给出了类型实参时，类型参数不再从实参推断，例如 id<string>("a")。
+func (c *Checker) checkCallExpr(expr ast.CallExpr, expected Type) Type {
+	callee := c.checkExpr(expr.Method, nil)
+
+	signature, ok := callee.(*FunctionType)
+	if !ok {
+		if callee != Any {
+			c.errorf("cannot call non-function %s", callee)
+		}
+		for _, typeArg := range expr.TypeArguments {
+			c.resolveType(typeArg)
+		}
+		c.checkUntypedArguments(expr)
+		return Any
+	}
+
+	name := describeCallee(expr.Method, signature)
+	inferable := signature.TypeParameters
+	bindings := make(substitution)
+	if expr.TypeArguments != nil {
+		typeArgs := make([]Type, len(expr.TypeArguments))
+		for i, typeArg := range expr.TypeArguments {
+			typeArgs[i] = c.resolveType(typeArg)
+		}
+
+		if len(typeArgs) != len(signature.TypeParameters) {
+			c.errorf("%s requires %d type argument(s) but %d were given", name, len(signature.TypeParameters), len(typeArgs))
+		} else {
+			for i, typeParam := range signature.TypeParameters {
+				bindings[typeParam] = typeArgs[i]
+			}
+			c.checkConstraints(signature.TypeParameters, typeArgs)
+			inferable = nil
+		}
+	}
+
+	return c.checkCall(name, signature, inferable, expr, bindings, expected)
+}
+
+// checkUntypedArguments 在被调用者的签名未知时检查实参表达式本身。
+func (c *Checker) checkUntypedArguments(call ast.CallExpr) {
+	for _, arg := range call.Arguments {
+		if spread, ok := arg.(ast.SpreadExpr); ok {
+			arg = spread.Argument
+		}
+		c.checkExpr(arg, nil)
+	}
+
+	for _, named := range call.NamedArguments {
+		c.checkExpr(named.Value, nil)
+	}
+}
+
+// argumentCheck 记录一个实参的类型及其对应的形参类型。类型实参全部推断完之后才统一检查是否匹配。
+type argumentCheck struct {
+	param Type
+	arg   Type
+	what  string
+}
+
+// checkCall 检查一次调用并返回调用结果的类型。
+//
+// 参数:
+//   - callee: 被调用者的描述，用于错误信息。
+//   - signature: 被调用函数的签名。
+//   - inferable: 需要在这次调用中推断的类型参数，例如泛型函数自身的类型参数，或 new 表达式中类的类型参数。
+//   - call: 调用表达式，提供位置实参和命名实参。
+//   - bindings: 已经确定的类型实参，例如 new Box<number>(...) 中显式给出的实参；推断结果也会写入其中。
+//   - expected: 调用结果的期望类型，没有时为 nil。
+//
+// 实参按顺序检查，每个实参都以当前已推断出的形参类型作为期望类型，
+// 因此前面实参推断出的类型实参可以引导后面的回调等实参。省略了参数类型的箭头函数的形参类型
+// 还依赖尚未推断出的类型参数时，它会推迟到其它实参之后再检查，例如 apply(x -> x * 2, 21)。
+// 所有实参检查完后，仍未推断出的类型参数按返回类型与期望类型匹配推断，例如 let xs: []string = empty()；
+// 这样仍无法推断的类型参数会报告错误并以 Any 代替。
+//
+// 位置实参依次对应各个参数，多出的位置实参由剩余参数接收。展开元组 ...t 相当于依次写出元组的每个成员；
+// 展开列表 ...xs 的长度未知，因此只能作为最后一个位置实参，且它覆盖的参数都必须有默认值或为剩余参数。
+// 命名实参按参数名对应参数，不能与位置实参重复，也不能用于剩余参数。
+func (c *Checker) checkCall(callee string, signature *FunctionType, inferable []*TypeParameter, call ast.CallExpr, bindings substitution, expected Type) Type {
+	checks := make([]argumentCheck, 0, len(call.Arguments)+len(call.NamedArguments))
+	provided := make([]bool, len(signature.Parameters))
+	position := 0
+
+	bind := func(param Type, arg Type, what string) {
+		c.infer(param, arg, inferable, bindings)
+		checks = append(checks, argumentCheck{param: param, arg: arg, what: what})
+	}
+
+	type deferredArgument struct {
+		expr  ast.Expr
+		param Type
+		what  string
+	}
+	deferred := make([]deferredArgument, 0)
+
+	check := func(expr ast.Expr, param Type, what string) {
+		if isContextSensitive(expr) && mentionsTypeParameters(substitute(param, bindings), inferable) {
+			deferred = append(deferred, deferredArgument{expr: expr, param: param, what: what})
+			return
+		}
+		bind(param, c.checkExpr(expr, substitute(param, bindings)), what)
+	}
+
+	for i, arg := range call.Arguments {
+		what := fmt.Sprintf("argument %d in call to %s", i+1, callee)
+
+		spread, isSpread := arg.(ast.SpreadExpr)
+		if !isSpread {
+			param := signature.parameterAt(position)
+			if param == nil {
+				c.checkExpr(arg, nil)
+			} else {
+				check(arg, param, what)
+			}
+
+			if position < len(provided) {
+				provided[position] = true
+			}
+			position++
+			continue
+		}
+
+		switch spreadType := c.checkExpr(spread.Argument, nil).(type) {
+		case *TupleType:
+			for _, member := range spreadType.Members {
+				if param := signature.parameterAt(position); param != nil {
+					bind(param, member, what)
+				}
+
+				if position < len(provided) {
+					provided[position] = true
+				}
+				position++
+			}
+		case *ListType:
+			if i != len(call.Arguments)-1 {
+				c.errorf("spread of %s must be the last positional argument in call to %s", spreadType, callee)
+			}
+
+			for ; position < len(signature.Parameters); position++ {
+				if position < signature.required() {
+					c.errorf("spread of %s may not provide required parameter %s in call to %s", spreadType, describeParameter(signature, position), callee)
+				}
+				bind(signature.Parameters[position], spreadType.Element, what)
+				provided[position] = true
+			}
+
+			if signature.Rest != nil {
+				bind(signature.Rest.Element, spreadType.Element, what)
+			}
+		default:
+			if spreadType != Any {
+				c.errorf("cannot spread %s in call to %s", spreadType, callee)
+			}
+
+			for ; position < len(signature.Parameters); position++ {
+				provided[position] = true
+			}
+		}
+	}
+
+	for _, named := range call.NamedArguments {
+		index := -1
+		for i, name := range signature.ParameterNames {
+			if name == named.Name {
+				index = i
+			}
+		}
+
+		switch {
+		case signature.ParameterNames == nil:
+			c.errorf("named argument %s in call to %s: parameter names are unknown", named.Name, callee)
+		case index < 0:
+			c.errorf("unknown parameter %s in call to %s", named.Name, callee)
+		case provided[index]:
+			c.errorf("parameter %s given more than once in call to %s", named.Name, callee)
+		default:
+			check(named.Value, signature.Parameters[index], fmt.Sprintf("argument %s in call to %s", named.Name, callee))
+			provided[index] = true
+			continue
+		}
+
+		c.checkExpr(named.Value, nil)
+	}
+
+	for _, arg := range deferred {
+		// 其它实参仍未确定的类型参数在箭头函数的参数中按 any 处理，返回类型仍可用于推断。
+		unresolved := make(substitution, len(inferable))
+		for _, typeParam := range inferable {
+			if _, exists := bindings[typeParam]; !exists {
+				unresolved[typeParam] = Any
+			}
+		}
+
+		expected := substitute(substitute(arg.param, bindings), unresolved)
+		bind(arg.param, c.checkExpr(arg.expr, expected), arg.what)
+	}
+
+	if position > len(signature.Parameters) && signature.Rest == nil || len(call.NamedArguments) == 0 && position < signature.required() {
+		c.errorf("wrong number of arguments in call to %s: expected %s but got %d", callee, signature.arity(), position)
+	} else {
+		for i := 0; i < signature.required(); i++ {
+			if !provided[i] {
+				c.errorf("missing argument for parameter %s in call to %s", describeParameter(signature, i), callee)
+			}
+		}
+	}
+
+	if expected != nil && len(inferable) > 0 {
+		fromContext := make(substitution)
+		c.infer(signature.ReturnType, expected, inferable, fromContext)
+		for typeParam, t := range fromContext {
+			if _, exists := bindings[typeParam]; !exists {
+				bindings[typeParam] = t
+			}
+		}
+	}
+
+	if len(inferable) > 0 {
+		typeArgs := make([]Type, len(inferable))
+		for i, typeParam := range inferable {
+			bound, exists := bindings[typeParam]
+			if !exists {
+				c.errorf("cannot infer type argument %s in call to %s", typeParam.Name, callee)
+				bound = Any
+				bindings[typeParam] = Any
+			}
+			typeArgs[i] = bound
+		}
+		c.checkConstraints(inferable, typeArgs)
+	}
+
+	for _, check := range checks {
+		c.expectType(substitute(check.param, bindings), check.arg, check.what)
+	}
+
+	return substitute(signature.ReturnType, bindings)
+}
+
+// describeParameter 返回第 i 个参数的名称；参数没有名称时返回它的序号。
+func describeParameter(signature *FunctionType, i int) string {
+	if i < len(signature.ParameterNames) && signature.ParameterNames[i] != "" {
+		return signature.ParameterNames[i]
+	}
+
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// describeCallee 返回调用表达式中被调用者的可读描述：具名函数或方法使用其名称，其它情况使用签名。
+func describeCallee(method ast.Expr, signature *FunctionType) string {
+	switch method := method.(type) {
+	case ast.SymbolExpr:
+		return method.Value
+	case ast.MemberExpr:
+		return method.Property
+	}
+
+	return signature.String()
+}
+
+// checkNewExpr 检查类的实例化。类的类型参数可以显式给出，也可以从构造函数的实参推断；
+// 没有构造函数的类只能以零个参数实例化。
+func (c *Checker) checkNewExpr(expr ast.NewExpr) Type {
+	name, ok := expr.Instantiation.Method.(ast.SymbolExpr)
+	if !ok {
+		c.errorf("new expects a class name")
+		return Any
+	}
+
+	resolved, exists := c.scope.lookupType(name.Value)
+	class, isClass := resolved.(*ClassType)
+	if !exists || !isClass {
+		c.errorf("%s is not a class", name.Value)
+		c.checkUntypedArguments(expr.Instantiation)
+		return Any
+	}
+
+	if class.Interface {
+		c.errorf("cannot instantiate interface %s", class.Name)
+	}
+
+	bindings := make(substitution)
+	if expr.TypeArguments != nil {
+		if len(expr.TypeArguments) != len(class.TypeParameters) {
+			c.errorf("class %s requires %d type argument(s) but %d were given", class.Name, len(class.TypeParameters), len(expr.TypeArguments))
+		}
+		for i, typeArg := range expr.TypeArguments {
+			if i < len(class.TypeParameters) {
+				bindings[class.TypeParameters[i]] = c.resolveType(typeArg)
+			}
+		}
+	}
+
+	constructor := class.Constructor
+	if constructor == nil {
+		constructor = &FunctionType{ReturnType: Void}
+	}
+
+	inferable := append(append([]*TypeParameter{}, class.TypeParameters...), constructor.TypeParameters...)
+	c.checkCall("new "+class.Name, constructor, inferable, expr.Instantiation, bindings, nil)
+
+	typeArgs := make([]Type, len(class.TypeParameters))
+	for i, typeParam := range class.TypeParameters {
+		typeArgs[i] = bindings[typeParam]
+	}
+
+	return &InstanceType{Class: class, TypeArguments: typeArgs}
+}
@@ -0,0 +1,212 @@
+package checker
+
+// substitution 把类型参数映射到具体的类型实参。
+type substitution map[*TypeParameter]Type
+
+// substitute 返回把 t 中出现的类型参数按 subst 替换之后的类型。未出现在 subst 中的类型参数保持不变。
+func substitute(t Type, subst substitution) Type {
+	if len(subst) == 0 {
+		return t
+	}
+
+	switch t := t.(type) {
+	case *TypeParameter:
+		if replacement, exists := subst[t]; exists {
+			return replacement
+		}
+		return t
+	case *ListType:
+		return &ListType{Element: substitute(t.Element, subst)}
+	case *MapType:
+		return &MapType{Key: substitute(t.Key, subst), Value: substitute(t.Value, subst)}
+	case *ChannelType:
+		return &ChannelType{Element: substitute(t.Element, subst)}
+	case *TupleType:
+		return &TupleType{Members: substituteAll(t.Members, subst)}
+	case *UnionType:
+		return NewUnion(substituteAll(t.Members, subst)...)
+	case *FunctionType:
+		signature := &FunctionType{
+			TypeParameters: t.TypeParameters,
+			Parameters:     substituteAll(t.Parameters, subst),
+			ParameterNames: t.ParameterNames,
+			Optional:       t.Optional,
+			ReturnType:     substitute(t.ReturnType, subst),
+		}
+		if t.Rest != nil {
+			signature.Rest = &ListType{Element: substitute(t.Rest.Element, subst)}
+		}
+		return signature
+	case *InstanceType:
+		return &InstanceType{Class: t.Class, TypeArguments: substituteAll(t.TypeArguments, subst)}
+	default:
+		return t
+	}
+}
+
+func substituteAll(types []Type, subst substitution) []Type {
+	result := make([]Type, len(types))
+	for i, t := range types {
+		result[i] = substitute(t, subst)
+	}
+
+	return result
+}
+
+// classSubstitution 返回把实例所属类的类型参数映射到该实例类型实参的替换表。
+func classSubstitution(instance *InstanceType) substitution {
+	subst := make(substitution, len(instance.TypeArguments))
+	for i, typeParam := range instance.Class.TypeParameters {
+		if i < len(instance.TypeArguments) {
+			subst[typeParam] = instance.TypeArguments[i]
+		}
+	}
+
+	return subst
+}
+
+// infer 通过把形参类型 param 与实参类型 arg 进行结构匹配，为 bindings 中尚未确定的类型参数推断类型实参。
+//
+// 只有出现在 inferable 中的类型参数才会被推断。同一个类型参数从多个实参推断出不同的类型时，
+// 若新类型可以容纳已推断的类型则放宽为新类型，否则保留先前的结果，由之后的可赋值性检查报告错误。
+func (c *Checker) infer(param Type, arg Type, inferable []*TypeParameter, bindings substitution) {
+	switch param := param.(type) {
+	case *TypeParameter:
+		if !containsTypeParameter(inferable, param) {
+			return
+		}
+
+		bound, exists := bindings[param]
+		if !exists || bound == Any {
+			bindings[param] = arg
+		} else if !c.assignable(bound, arg) && c.assignable(arg, bound) {
+			bindings[param] = arg
+		}
+	case *ListType:
+		if arg, ok := arg.(*ListType); ok {
+			c.infer(param.Element, arg.Element, inferable, bindings)
+		}
+	case *MapType:
+		if arg, ok := arg.(*MapType); ok {
+			c.infer(param.Key, arg.Key, inferable, bindings)
+			c.infer(param.Value, arg.Value, inferable, bindings)
+		}
+	case *ChannelType:
+		if arg, ok := arg.(*ChannelType); ok {
+			c.infer(param.Element, arg.Element, inferable, bindings)
+		}
+	case *TupleType:
+		if arg, ok := arg.(*TupleType); ok && len(arg.Members) == len(param.Members) {
+			for i := range param.Members {
+				c.infer(param.Members[i], arg.Members[i], inferable, bindings)
+			}
+		}
+	case *FunctionType:
+		if arg, ok := arg.(*FunctionType); ok {
+			for i := 0; i < len(param.Parameters) && i < len(arg.Parameters); i++ {
+				c.infer(param.Parameters[i], arg.Parameters[i], inferable, bindings)
+			}
+			if param.Rest != nil && arg.Rest != nil {
+				c.infer(param.Rest, arg.Rest, inferable, bindings)
+			}
+			c.infer(param.ReturnType, arg.ReturnType, inferable, bindings)
+		}
+	case *InstanceType:
+		if arg, ok := arg.(*InstanceType); ok && arg.Class == param.Class {
+			for i := range param.TypeArguments {
+				c.infer(param.TypeArguments[i], arg.TypeArguments[i], inferable, bindings)
+			}
+		}
+	case *UnionType:
+		// T? 与 number 匹配时推断 T = number；实参已能赋值给联合类型中的具体成员时不做推断。
+		generic := make([]Type, 0)
+		for _, member := range param.Members {
+			if mentionsTypeParameters(member, inferable) {
+				generic = append(generic, member)
+			} else if c.assignable(member, arg) {
+				return
+			}
+		}
+
+		remaining := arg
+		if union, ok := arg.(*UnionType); ok {
+			kept := make([]Type, 0)
+			for _, member := range union.Members {
+				if !containsType(param.Members, member) {
+					kept = append(kept, member)
+				}
+			}
+			if len(kept) > 0 {
+				remaining = NewUnion(kept...)
+			}
+		}
+
+		for _, member := range generic {
+			c.infer(member, remaining, inferable, bindings)
+		}
+	}
+}
+
+func containsTypeParameter(typeParams []*TypeParameter, t *TypeParameter) bool {
+	for _, candidate := range typeParams {
+		if candidate == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mentionsTypeParameters 判断类型 t 中是否出现了 typeParams 中的任意一个类型参数。
+func mentionsTypeParameters(t Type, typeParams []*TypeParameter) bool {
+	switch t := t.(type) {
+	case *TypeParameter:
+		return containsTypeParameter(typeParams, t)
+	case *ListType:
+		return mentionsTypeParameters(t.Element, typeParams)
+	case *MapType:
+		return mentionsTypeParameters(t.Key, typeParams) || mentionsTypeParameters(t.Value, typeParams)
+	case *ChannelType:
+		return mentionsTypeParameters(t.Element, typeParams)
+	case *TupleType:
+		return mentionsAny(t.Members, typeParams)
+	case *UnionType:
+		return mentionsAny(t.Members, typeParams)
+	case *FunctionType:
+		return mentionsAny(t.Parameters, typeParams) || mentionsTypeParameters(t.ReturnType, typeParams) ||
+			t.Rest != nil && mentionsTypeParameters(t.Rest, typeParams)
+	case *InstanceType:
+		return mentionsAny(t.TypeArguments, typeParams)
+	}
+
+	return false
+}
+
+func mentionsAny(types []Type, typeParams []*TypeParameter) bool {
+	for _, t := range types {
+		if mentionsTypeParameters(t, typeParams) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkConstraints 检查每个类型实参是否满足对应类型参数的约束。
+func (c *Checker) checkConstraints(typeParams []*TypeParameter, typeArgs []Type) {
+	subst := make(substitution, len(typeParams))
+	for i, typeParam := range typeParams {
+		subst[typeParam] = typeArgs[i]
+	}
+
+	for i, typeParam := range typeParams {
+		if typeParam.Constraint == nil {
+			continue
+		}
+
+		constraint := substitute(typeParam.Constraint, subst)
+		if !c.assignable(constraint, typeArgs[i]) {
+			c.errorf("type %s does not satisfy constraint %s of type parameter %s", typeArgs[i], constraint, typeParam.Name)
+		}
+	}
+}
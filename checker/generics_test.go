@@ -0,0 +1,86 @@
+package checker_test
+
+import "testing"
+
+// genericDecls 是泛型测试共用的声明。
+const genericDecls = `
+	fn id<T>(x: T): T { return x; }
+	fn empty<T>(): []T { return []; }
+	fn map<T, U>(xs: []T, f: fn(T): U): []U { let out: []U = []; foreach x in xs { out.push(f(x)); } return out; }
+	fn longest<T: string | []any>(a: T, b: T): T { return a; }
+	class Box<T> {
+		let value: T;
+		fn constructor(value: T) { this.value = value; }
+		fn get(): T { return this.value; }
+		fn as<U>(f: fn(T): U): Box<U> { return new Box<U>(f(this.value)); }
+	}
+`
+
+func TestGenerics(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "inferred from arguments",
+			source: genericDecls + `let s: string = id("a"); let ns: []string = map([1, 2], n -> "x");`,
+		},
+		{
+			name:   "inferred type is checked",
+			source: genericDecls + `let n: number = id("a");`,
+			err:    "cannot assign string to n of type number",
+		},
+		{
+			name:   "explicit type arguments",
+			source: genericDecls + `let s: string = id<string>("a"); let xs = empty<number>(); xs.push(1);`,
+		},
+		{
+			name:   "explicit type arguments check the arguments",
+			source: genericDecls + `id<string>(1);`,
+			err:    "argument 1 in call to id: expected string but got number",
+		},
+		{
+			name:   "wrong number of type arguments",
+			source: genericDecls + `id<string, number>("a");`,
+			err:    "id requires 1 type argument(s) but 2 were given",
+		},
+		{
+			name:   "explicit type arguments on a method",
+			source: genericDecls + `let b = new Box(1); let s: Box<string> = b.as<string>(n -> "x");`,
+		},
+		{
+			name:   "inferred from the expected type",
+			source: genericDecls + `let xs: []string = empty(); xs.push("a"); fn names(): []string { return empty(); }`,
+		},
+		{
+			name:   "expected type does not override arguments",
+			source: genericDecls + `let s: string = id(1);`,
+			err:    "cannot assign number to s of type string",
+		},
+		{
+			name:   "cannot infer without context",
+			source: genericDecls + `let xs = empty();`,
+			err:    "cannot infer type argument T in call to empty",
+		},
+		{
+			name:   "constraint satisfied",
+			source: genericDecls + `let s: string = longest("a", "bc");`,
+		},
+		{
+			name:   "constraint violated",
+			source: genericDecls + `longest(1, 2);`,
+			err:    "type number does not satisfy constraint",
+		},
+		{
+			name:   "explicit type argument violates the constraint",
+			source: genericDecls + `longest<number>(1, 2);`,
+			err:    "type number does not satisfy constraint",
+		},
+		{
+			name:   "generic class",
+			source: genericDecls + `let b = new Box("a"); let s: string = b.get(); let c = new Box<number>(1); let n: number = c.value;`,
+		},
+		{
+			name:   "generic class rejects the wrong type",
+			source: genericDecls + `let b = new Box<number>("a");`,
+			err:    "expected number but got string",
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package checker_test
+
+import "testing"
+
+func TestLambdas(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "expression body with annotated parameters",
+			source: `let add = (x: number, y: number) -> x + y; let n: number = add(1, 2);`,
+		},
+		{
+			name:   "parameter types inferred from the expected type",
+			source: `let f: fn(number, number): number = (x, y) -> x * y;`,
+		},
+		{
+			name:   "single parameter without parentheses",
+			source: `let f: fn(string): number = s -> 1; let xs: []string = ["a"]; xs.map(s -> s + "!");`,
+		},
+		{
+			name:   "block body",
+			source: `let f: fn(number): string = x -> { if x > 0 { return "positive"; } return "other"; };`,
+		},
+		{
+			name:   "inferred parameter types are checked in the body",
+			source: `let f: fn(string): number = s -> s * 2;`,
+			err:    "left operand of *: expected number but got string",
+		},
+		{
+			name:   "result type is checked",
+			source: `let f: fn(number): string = x -> x + 1;`,
+			err:    "cannot assign fn(number): number to f of type fn(number): string",
+		},
+		{
+			name:   "parameter types cannot be inferred without an expected type",
+			source: `let f = x -> x;`,
+			err:    "cannot infer type of parameter x: add a type annotation",
+		},
+		{
+			name:   "arity must match the expected type",
+			source: `let f: fn(number): number = (x, y) -> x;`,
+			err:    "cannot assign fn(number, any): number to f of type fn(number): number",
+		},
+	})
+}
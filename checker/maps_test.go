@@ -0,0 +1,44 @@
+package checker_test
+
+import "testing"
+
+func TestMaps(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "literal with identifier, quoted and computed keys",
+			source: `let k = "c"; let m: map[string]number = {a: 1, "b": 2, [k]: 3};`,
+		},
+		{
+			name:   "inferred map type",
+			source: `let m = {a: 1}; let n: number = m["a"];`,
+		},
+		{
+			name:   "brace map type",
+			source: `let m: {string: number} = {a: 1}; let n: number = m["a"];`,
+		},
+		{
+			name:   "member access",
+			source: `let m: map[string]number = {a: 1}; let n: number = m.a;`,
+		},
+		{
+			name:   "value type is checked",
+			source: `let m: map[string]number = {a: "x"};`,
+			err:    "cannot assign map[string]string to m of type map[string]number",
+		},
+		{
+			name:   "key type is checked",
+			source: `let m: map[number]string = {a: "x"};`,
+			err:    "cannot assign map[string]string to m of type map[number]string",
+		},
+		{
+			name:   "index with the wrong key type",
+			source: `let m: map[string]number = {a: 1}; m[1];`,
+			err:    "expected string but got number",
+		},
+		{
+			name:   "element type is checked on assignment",
+			source: `let m: map[string]number = {}; m["a"] = "x";`,
+			err:    "expected number but got string",
+		},
+	})
+}
@@ -0,0 +1,494 @@
+package checker
+
+import (
+	"strings"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+// checkTypeTestExpr 检查 value is T 和 value instanceof T。instanceof 的右侧必须是类或接口；
+// 类型参数在运行时不存在，不能用于检查。
+func (c *Checker) checkTypeTestExpr(expr ast.TypeTestExpr) Type {
+	c.checkExpr(expr.Value, nil)
+	c.checkTestedType(expr.Type, expr.Operator.Kind)
+
+	return Bool
+}
+
+// checkTestedType 解析 is 或 instanceof（由 kind 指定）右侧的类型 t。
+func (c *Checker) checkTestedType(t ast.Type, kind lexer.TokenKind) Type {
+	target := c.resolveType(t)
+	operator := lexer.TokenKindString(kind)
+
+	if _, ok := target.(*InstanceType); !ok && kind == lexer.TokenTypeKeywordInstanceof && target != Any {
+		c.errorf("right operand of instanceof must be a class or interface, not %s", target)
+	}
+	if hasTypeParameter(target) {
+		c.errorf("cannot use %s with %s: type parameters are not available at runtime", operator, target)
+	}
+
+	return target
+}
+
+// hasTypeParameter 判断类型 t 中是否出现了类型参数。
+func hasTypeParameter(t Type) bool {
+	switch t := t.(type) {
+	case *TypeParameter:
+		return true
+	case *ListType:
+		return hasTypeParameter(t.Element)
+	case *MapType:
+		return hasTypeParameter(t.Key) || hasTypeParameter(t.Value)
+	case *ChannelType:
+		return hasTypeParameter(t.Element)
+	case *TupleType:
+		return anyHasTypeParameter(t.Members)
+	case *UnionType:
+		return anyHasTypeParameter(t.Members)
+	case *FunctionType:
+		return anyHasTypeParameter(t.Parameters) || hasTypeParameter(t.ReturnType) || t.Rest != nil && hasTypeParameter(t.Rest)
+	case *InstanceType:
+		return anyHasTypeParameter(t.TypeArguments)
+	}
+
+	return false
+}
+
+func anyHasTypeParameter(types []Type) bool {
+	for _, t := range types {
+		if hasTypeParameter(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// narrowing 是条件成立或不成立时变量被收窄后的类型，键为变量名。
+type narrowing map[string]Type
+
+// narrowCondition 返回条件 cond 的值为 truthy 时可以确定的变量类型:
+//   - x is T 和 x instanceof T 成立时 x 的类型收窄为 T，不成立时从 x 的联合类型中去掉 T 的成员；
+//   - x == null 和 x != null 把 x 收窄为 null 或去掉 x 的类型中的 null，null 也可以写在左侧；
+//   - typeof x == "name" 成立时只保留 x 的类型中可能名为 name 的成员，不成立时去掉一定名为 name 的成员；
+//   - 单独的 x 成立时去掉 x 的类型中的 null；
+//   - a && b 成立时 a 和 b 都成立，b 的收窄基于 a 成立时的类型；不成立时为 a 不成立或 a 成立且 b 不成立两种情况的合并；
+//   - a || b 与 && 对称；!cond 与 cond 相反。
+func (c *Checker) narrowCondition(cond ast.Expr, truthy bool) narrowing {
+	switch cond := cond.(type) {
+	case ast.TypeTestExpr:
+		symbol, ok := c.narrowable(cond.Value)
+		if !ok {
+			return nil
+		}
+		target := c.resolveTypeQuietly(cond.Type)
+		if truthy {
+			return narrowing{symbol.Name: c.narrowTo(symbol.Type, target)}
+		}
+		return narrowing{symbol.Name: c.exclude(symbol.Type, target)}
+	case ast.PrefixExpr:
+		if cond.Operator.Kind == lexer.TokenTypeSymbolNot {
+			return c.narrowCondition(cond.Right, !truthy)
+		}
+	case ast.SymbolExpr:
+		if symbol, ok := c.narrowable(cond); ok && truthy {
+			return narrowing{symbol.Name: c.exclude(symbol.Type, Null)}
+		}
+	case ast.BinaryExpr:
+		switch cond.Operator.Kind {
+		case lexer.TokenTypeSymbolEqual:
+			return c.narrowEquality(cond.Left, cond.Right, truthy)
+		case lexer.TokenTypeSymbolNotEqual:
+			return c.narrowEquality(cond.Left, cond.Right, !truthy)
+		case lexer.TokenTypeSymbolAnd:
+			if truthy {
+				return c.narrowSequence(cond.Left, true, cond.Right, true)
+			}
+			return c.merge(c.narrowCondition(cond.Left, false), c.narrowSequence(cond.Left, true, cond.Right, false))
+		case lexer.TokenTypeSymbolOr:
+			if !truthy {
+				return c.narrowSequence(cond.Left, false, cond.Right, false)
+			}
+			return c.merge(c.narrowCondition(cond.Left, true), c.narrowSequence(cond.Left, false, cond.Right, true))
+		}
+	}
+
+	return nil
+}
+
+// narrowEquality 返回 left == right 的值为 equal 时可以确定的变量类型。
+func (c *Checker) narrowEquality(left, right ast.Expr, equal bool) narrowing {
+	if _, ok := left.(ast.NullExpr); ok {
+		left, right = right, left
+	}
+	if _, ok := right.(ast.NullExpr); ok {
+		symbol, ok := c.narrowable(left)
+		if !ok {
+			return nil
+		}
+		if equal {
+			return narrowing{symbol.Name: c.narrowTo(symbol.Type, Null)}
+		}
+		return narrowing{symbol.Name: c.exclude(symbol.Type, Null)}
+	}
+
+	if _, ok := left.(ast.StringExpr); ok {
+		left, right = right, left
+	}
+	typeName, ok := right.(ast.StringExpr)
+	if !ok {
+		return nil
+	}
+	typeOf, ok := left.(ast.PrefixExpr)
+	if !ok || typeOf.Operator.Kind != lexer.TokenTypeKeywordTypeof {
+		return nil
+	}
+	symbol, ok := c.narrowable(typeOf.Right)
+	if !ok {
+		return nil
+	}
+
+	return narrowing{symbol.Name: narrowTypeof(symbol.Type, typeName.Value, equal)}
+}
+
+// narrowSequence 返回 first 的值为 firstTruthy 且之后 second 的值为 secondTruthy 时可以确定的变量类型。
+func (c *Checker) narrowSequence(first ast.Expr, firstTruthy bool, second ast.Expr, secondTruthy bool) narrowing {
+	narrowed := c.narrowCondition(first, firstTruthy)
+
+	var then narrowing
+	c.withNarrowing(narrowed, func() {
+		then = c.narrowCondition(second, secondTruthy)
+	})
+
+	return narrowed.and(then)
+}
+
+// and 返回 n 和 m 同时成立时的收窄，m 中的类型基于 n 收窄之后的类型，因此覆盖 n 中的同名变量。
+func (n narrowing) and(m narrowing) narrowing {
+	if len(m) == 0 {
+		return n
+	}
+
+	result := make(narrowing, len(n)+len(m))
+	for name, t := range n {
+		result[name] = t
+	}
+	for name, t := range m {
+		result[name] = t
+	}
+
+	return result
+}
+
+// merge 返回 a 和 b 至少一个成立时的收窄：只有两者都收窄的变量被收窄为两个类型的联合。
+func (c *Checker) merge(a, b narrowing) narrowing {
+	result := make(narrowing)
+	for name, t := range a {
+		if other, exists := b[name]; exists {
+			result[name] = NewUnion(t, other)
+		}
+	}
+
+	return result
+}
+
+// narrowTypeof 返回类型为 t 的值的 typeof 是否等于 name 为 equal 时的类型：
+// 成立时保留 t 的联合类型中可能名为 name 的成员，any 在 name 为基本类型时收窄为该基本类型；
+// 不成立时去掉一定名为 name 的成员。没有剩余成员时仍为 t。
+func narrowTypeof(t Type, name string, equal bool) Type {
+	if t == Any {
+		switch name {
+		case "number":
+			return Number
+		case "string":
+			return String
+		case "bool":
+			return Bool
+		case "null":
+			return Null
+		}
+		return t
+	}
+
+	union, ok := t.(*UnionType)
+	if !ok {
+		return t
+	}
+
+	members := make([]Type, 0, len(union.Members))
+	for _, member := range union.Members {
+		maybe, always := typeofMatches(member, name)
+		if equal && maybe || !equal && !always {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return t
+	}
+
+	return NewUnion(members...)
+}
+
+// typeofMatches 判断类型为 t 的值的 typeof 是否可能（maybe）以及是否一定（always）等于 name，与 interpreter.TypeName 一致。
+// 列表的类型名取决于元素的运行时类型，接口和类型参数可以是任意实例，因此只能确定可能相等。
+func typeofMatches(t Type, name string) (maybe, always bool) {
+	switch t := t.(type) {
+	case *PrimitiveType:
+		if t == Void {
+			return name == "null", name == "null"
+		}
+		return t.Name == name, t.Name == name
+	case *EnumType:
+		members := []Type{t.Underlying}
+		if union, ok := t.Underlying.(*UnionType); ok {
+			members = union.Members
+		}
+		always = true
+		for _, member := range members {
+			memberMaybe, memberAlways := typeofMatches(member, name)
+			maybe = maybe || memberMaybe
+			always = always && memberAlways
+		}
+		return maybe, always
+	case *ListType, *TupleType:
+		return strings.HasPrefix(name, "[]"), false
+	case *MapType:
+		return name == "map", name == "map"
+	case *FunctionType:
+		return name == "function", name == "function"
+	case *ChannelType:
+		return name == "chan", name == "chan"
+	case *InstanceType:
+		if !t.Class.Interface {
+			return name == t.Class.Name, name == t.Class.Name
+		}
+	}
+
+	return true, false
+}
+
+// narrowable 返回可以被收窄的变量 expr 的符号。只有变量可以被收窄，成员表达式等不能；
+// 被嵌套的函数赋值的变量也不能，因为在收窄之后调用该函数就会改变变量的值。
+func (c *Checker) narrowable(expr ast.Expr) (*Symbol, bool) {
+	symbol, ok := expr.(ast.SymbolExpr)
+	if !ok {
+		return nil, false
+	}
+	for scope := c.scope; scope != nil; scope = scope.parent {
+		if scope.captured[symbol.Value] {
+			return nil, false
+		}
+	}
+
+	return c.scope.lookupValue(symbol.Value)
+}
+
+// resolveTypeQuietly 解析类型 t 而不报告错误，错误已在检查条件表达式时报告过。
+func (c *Checker) resolveTypeQuietly(t ast.Type) Type {
+	errors := len(c.errors)
+	resolved := c.resolveType(t)
+	c.errors = c.errors[:errors]
+
+	return resolved
+}
+
+// narrowTo 返回类型为 t 的值同时属于 target 时的类型：t 为联合类型时保留其中属于 target 的成员，
+// 否则 t 本身属于 target 时为 t，都不满足时为 target，例如接口收窄为实现它的类。
+func (c *Checker) narrowTo(t, target Type) Type {
+	if union, ok := t.(*UnionType); ok {
+		members := make([]Type, 0, len(union.Members))
+		for _, member := range union.Members {
+			if c.assignable(target, member) && member != Any {
+				members = append(members, member)
+			}
+		}
+		if len(members) > 0 {
+			return NewUnion(members...)
+		}
+	}
+
+	if t != Any && c.assignable(target, t) {
+		return t
+	}
+
+	return target
+}
+
+// exclude 返回类型为 t 的值不属于 target 时的类型：从 t 的联合类型中去掉属于 target 的成员；
+// t 不是联合类型或者去掉之后没有剩余成员时仍为 t。
+func (c *Checker) exclude(t, target Type) Type {
+	union, ok := t.(*UnionType)
+	if !ok {
+		return t
+	}
+
+	members := make([]Type, 0, len(union.Members))
+	for _, member := range union.Members {
+		if !c.assignable(target, member) {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return t
+	}
+
+	return NewUnion(members...)
+}
+
+// checkNarrowed 检查语句 stmt，其中 narrowed 中的变量具有收窄后的类型。
+func (c *Checker) checkNarrowed(stmt ast.Stmt, narrowed narrowing) {
+	c.withNarrowing(narrowed, func() {
+		c.checkStmt(stmt)
+	})
+}
+
+// withNarrowing 在一个新的作用域中声明 narrowed 中的变量为收窄后的类型，然后调用 fn。
+func (c *Checker) withNarrowing(narrowed narrowing, fn func()) {
+	if len(narrowed) == 0 {
+		fn()
+		return
+	}
+
+	c.pushScope()
+	defer c.popScope()
+
+	for name, t := range narrowed {
+		c.scope.values[name] = c.narrowedSymbol(name, t)
+	}
+
+	fn()
+}
+
+// narrowedSymbol 返回变量 name 被收窄为类型 t 之后的符号。
+func (c *Checker) narrowedSymbol(name string, t Type) *Symbol {
+	symbol, _ := c.scope.lookupValue(name)
+	declared := symbol.Declared
+	if declared == nil {
+		declared = symbol.Type
+	}
+
+	return &Symbol{Name: name, Type: t, Constant: symbol.Constant, Declared: declared}
+}
+
+// widenAssigned 返回把 names 中被收窄的变量恢复为声明的类型的收窄，用于循环体。
+func (c *Checker) widenAssigned(names map[string]bool) narrowing {
+	widened := make(narrowing)
+	for name := range names {
+		if symbol, exists := c.scope.lookupValue(name); exists && symbol.Declared != nil {
+			widened[name] = symbol.Declared
+		}
+	}
+
+	return widened
+}
+
+// narrowAfter 返回语句 stmt 执行完毕且没有提前 return 或 throw 时可以确定的变量类型，例如
+// if x == null { return } 之后 x 不为 null。else if 链中每个以 return 结束的分支都会收窄之后的语句。
+func (c *Checker) narrowAfter(stmt ast.Stmt) narrowing {
+	ifStmt, ok := stmt.(ast.IfStmt)
+	if !ok {
+		return nil
+	}
+
+	consequentReturns := alwaysReturns([]ast.Stmt{ifStmt.Consequent})
+	switch {
+	case ifStmt.Alternate == nil:
+		if consequentReturns {
+			return c.narrowCondition(ifStmt.Condition, false)
+		}
+	case consequentReturns:
+		narrowed := c.narrowCondition(ifStmt.Condition, false)
+		var rest narrowing
+		c.withNarrowing(narrowed, func() {
+			rest = c.narrowAfter(ifStmt.Alternate)
+		})
+		return narrowed.and(rest)
+	case alwaysReturns([]ast.Stmt{ifStmt.Alternate}):
+		return c.narrowCondition(ifStmt.Condition, true)
+	}
+
+	return nil
+}
+
+// narrowScope 在当前作用域中把 narrowed 中的变量声明为收窄后的类型，用于提前返回之后的语句。
+// 与 withNarrowing 不同，之后声明的变量仍位于当前作用域中；被覆盖的符号保存在 Scope.narrowed 中，由 restoreScope 恢复。
+func (c *Checker) narrowScope(narrowed narrowing) {
+	for name, t := range narrowed {
+		symbol := c.narrowedSymbol(name, t)
+		if c.scope.narrowed == nil {
+			c.scope.narrowed = make(map[string]*Symbol)
+		}
+		if _, saved := c.scope.narrowed[name]; !saved {
+			c.scope.narrowed[name] = c.scope.values[name]
+		}
+		c.scope.values[name] = symbol
+	}
+}
+
+// restoreScope 撤销 narrowScope 对当前作用域的修改。
+func (c *Checker) restoreScope() {
+	for name, symbol := range c.scope.narrowed {
+		if symbol == nil {
+			delete(c.scope.values, name)
+		} else {
+			c.scope.values[name] = symbol
+		}
+	}
+	c.scope.narrowed = nil
+}
+
+// widen 在变量 name 被赋值之后撤销它的收窄：从当前作用域开始，把各层作用域中收窄后的符号恢复为声明的类型。
+// 这样 if x != null { x = null; x.f } 中的 x.f 会报告错误。
+func (c *Checker) widen(name string) {
+	for scope := c.scope; scope != nil; scope = scope.parent {
+		symbol, exists := scope.values[name]
+		if !exists {
+			continue
+		}
+		if symbol.Declared == nil {
+			return
+		}
+		symbol.Type = symbol.Declared
+		if original, saved := scope.narrowed[name]; saved && original != nil {
+			return
+		}
+	}
+}
+
+// switchCondition 返回 switch 语句的第 index 个分支被选中的条件：之前的分支都不匹配且该分支匹配。
+// index 为分支数时返回 default 分支的条件，即所有分支都不匹配。
+// 分支 case a, b 匹配等价于 value == a || value == b，case is T 等价于 value is T，
+// 因此 switch x { case null {} } 和 switch typeof x { case "string" {} } 与对应的 if 语句一样收窄 x。
+func switchCondition(stmt ast.SwitchStmt, index int) ast.Expr {
+	var condition ast.Expr
+	for i, switchCase := range stmt.Cases {
+		if i > index {
+			break
+		}
+
+		var matches ast.Expr
+		if switchCase.Type != nil {
+			matches = ast.TypeTestExpr{Value: stmt.Value, Operator: lexer.Token{Kind: lexer.TokenTypeKeywordIs, Value: "is"}, Type: switchCase.Type}
+		}
+		for _, value := range switchCase.Values {
+			equal := ast.BinaryExpr{Left: stmt.Value, Operator: lexer.Token{Kind: lexer.TokenTypeSymbolEqual, Value: "=="}, Right: value}
+			if matches == nil {
+				matches = equal
+			} else {
+				matches = ast.BinaryExpr{Left: matches, Operator: lexer.Token{Kind: lexer.TokenTypeSymbolOr, Value: "||"}, Right: equal}
+			}
+		}
+		if i < index {
+			matches = ast.PrefixExpr{Operator: lexer.Token{Kind: lexer.TokenTypeSymbolNot, Value: "!"}, Right: matches}
+		}
+
+		if condition == nil {
+			condition = matches
+		} else {
+			condition = ast.BinaryExpr{Left: condition, Operator: lexer.Token{Kind: lexer.TokenTypeSymbolAnd, Value: "&&"}, Right: matches}
+		}
+	}
+
+	return condition
+}
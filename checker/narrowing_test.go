@@ -0,0 +1,121 @@
+package checker_test
+
+import "testing"
+
+func TestNarrowing(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "not null",
+			source: `let x: string | null = "a"; if x != null { let s: string = x; }`,
+		},
+		{
+			name:   "null on the left",
+			source: `let x: string | null = "a"; if null != x { let s: string = x; }`,
+		},
+		{
+			name:   "without narrowing",
+			source: `let x: string | null = "a"; let s: string = x;`,
+			err:    "cannot assign string | null to s of type string",
+		},
+		{
+			name:   "else branch",
+			source: `let x: string | null = "a"; if x == null { } else { let s: string = x; }`,
+		},
+		{
+			name:   "and chain",
+			source: `let x: string | null = "a"; let y: number | null = 1; if x != null && y != null { let s: string = x; let n: number = y; }`,
+		},
+		{
+			name:   "right operand of and",
+			source: `let x: string | null = "a"; let ok: bool = x != null && x == "a";`,
+		},
+		{
+			name:   "and chain on the same variable",
+			source: `let x: string | number | null = 1; if x != null && typeof x != "string" { let n: number = x; }`,
+		},
+		{
+			name:   "or chain narrows only when both sides narrow",
+			source: `let x: string | null = "a"; let y: number | null = 1; if x != null || y != null { let s: string = x; }`,
+			err:    "cannot assign string | null to s of type string",
+		},
+		{
+			name:   "negated or chain",
+			source: `let x: string | null = "a"; let y: number | null = 1; if !(x == null || y == null) { let s: string = x; let n: number = y; }`,
+		},
+		{
+			name:   "else branch of or chain",
+			source: `let x: string | null = "a"; let y: number | null = 1; if x == null || y == null { } else { let s: string = x; let n: number = y; }`,
+		},
+		{
+			name:   "right operand of or",
+			source: `let x: string | null = "a"; let ok: bool = x == null || x == "a";`,
+		},
+		{
+			name: "else if",
+			source: `let x: string | number | null = 1;
+				if x == null { } else if typeof x == "string" { let s: string = x; } else { let n: number = x; }`,
+		},
+		{
+			name: "nested elseif",
+			source: `let x: string | number | bool | null = 1;
+				if x == null { }
+				elseif typeof x == "string" { let s: string = x; }
+				elseif x is number { let n: number = x; }
+				else { let b: bool = x; }`,
+		},
+		{
+			name: "elseif with and chain",
+			source: `let x: string | null = "a"; let y: number | null = 1;
+				if x == null { } elseif y != null && x != "b" { let s: string = x; let n: number = y; }`,
+		},
+		{
+			name: "switch with type cases",
+			source: `let x: string | number | null = 1;
+				switch x { case null { } case is string { let s: string = x; } default { let n: number = x; } }`,
+		},
+		{
+			name:   "switch on typeof",
+			source: `let x: string | number = 1; switch typeof x { case "string" { let s: string = x; } case "number" { let n: number = x; } }`,
+		},
+		{
+			name:   "switch case with several values",
+			source: `let x: string | number | null = 1; switch x { case null, "a" { let s: string = x; } }`,
+			err:    "cannot assign null | string | number to s of type string",
+		},
+		{
+			name:   "early return",
+			source: `fn f(x: string | null): string { if x == null { return ""; } return x; }`,
+		},
+		{
+			name:   "assignment widens",
+			source: `let x: string | null = "a"; if x != null { x = null; let s: string = x; }`,
+			err:    "cannot assign string | null to s of type string",
+		},
+		{
+			name:   "assignment in loop body",
+			source: `let x: string | null = "a"; if x != null { foreach i in [1, 2] { let s: string = x; x = null; } }`,
+			err:    "cannot assign string | null to s of type string",
+		},
+		{
+			name:   "loop without assignment",
+			source: `let x: string | null = "a"; if x != null { foreach i in [1, 2] { let s: string = x; } }`,
+		},
+		{
+			name: "closure declared before the if",
+			source: `let x: string | null = "a"; let clear = fn() { x = null; };
+				if x != null { clear(); let s: string = x; }`,
+			err: "cannot assign string | null to s of type string",
+		},
+		{
+			name: "function declared after the if",
+			source: `let x: string | null = "a";
+				if x != null { clear(); let s: string = x; }
+				fn clear() { x = null; }`,
+			err: "cannot assign string | null to s of type string",
+		},
+		{
+			name:   "closure that only reads",
+			source: `let x: string | null = "a"; let read = fn(): string | null { return x; }; if x != null { read(); let s: string = x; }`,
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"strings"
+
+	"dreamlang/ast"
+)
+
+// declarePattern 在当前作用域中声明模式 pattern 绑定的全部变量，t 为被解构的值的类型。
+//
+// 列表模式可以解构列表和元组：解构列表时每个元素的类型为列表的元素类型，...rest 的类型与列表相同；
+// 解构元组时元素按位置取对应成员的类型，...rest 为剩余成员组成的元组。
+// 对象模式按成员名取值，可以解构实例、键为字符串的字典以及 any。
+func (c *Checker) declarePattern(pattern ast.Pattern, t Type, constant bool) {
+	switch pattern := pattern.(type) {
+	case ast.IdentifierPattern:
+		c.declareValue(pattern.Name, t, constant)
+	case ast.ArrayPattern:
+		elementTypes, restType := c.destructureList(pattern, t)
+		for i, element := range pattern.Elements {
+			c.declarePattern(element, elementTypes[i], constant)
+		}
+		if pattern.Rest != nil {
+			c.declarePattern(pattern.Rest, restType, constant)
+		}
+	case ast.ObjectPattern:
+		for _, property := range pattern.Properties {
+			propertyType, exists := c.memberType(t, property.Key)
+			if !exists {
+				c.errorf("cannot destructure %s: %s has no member %s", patternString(pattern), t, property.Key)
+				propertyType = Any
+			}
+			c.declarePattern(property.Value, propertyType, constant)
+		}
+	}
+}
+
+// patternShape 返回没有类型注解时用于检查被解构的值的期望类型。
+// 没有 ...rest 的列表模式期望一个元组，因此 let [n, s] = [1, "x"]; 中 n 为 number、s 为 string，
+// 而不是都为 number | string。元组中为 nil 的成员表示对该位置没有要求。
+func patternShape(pattern ast.Pattern) Type {
+	array, ok := pattern.(ast.ArrayPattern)
+	if !ok || array.Rest != nil {
+		return nil
+	}
+
+	members := make([]Type, len(array.Elements))
+	for i, element := range array.Elements {
+		members[i] = patternShape(element)
+	}
+
+	return &TupleType{Members: members}
+}
+
+// destructureList 返回列表模式中每个元素的类型以及 ...rest 的类型。
+func (c *Checker) destructureList(pattern ast.ArrayPattern, t Type) ([]Type, Type) {
+	elementTypes := make([]Type, len(pattern.Elements))
+
+	switch t := t.(type) {
+	case *ListType:
+		for i := range elementTypes {
+			elementTypes[i] = t.Element
+		}
+		return elementTypes, t
+	case *TupleType:
+		if len(pattern.Elements) > len(t.Members) {
+			c.errorf("cannot destructure %s: tuple %s has only %d member(s)", patternString(pattern), t, len(t.Members))
+		}
+
+		for i := range elementTypes {
+			elementTypes[i] = Any
+			if i < len(t.Members) {
+				elementTypes[i] = t.Members[i]
+			}
+		}
+
+		rest := []Type{}
+		if len(pattern.Elements) < len(t.Members) {
+			rest = t.Members[len(pattern.Elements):]
+		}
+		return elementTypes, &TupleType{Members: rest}
+	}
+
+	if t != Any {
+		c.errorf("cannot destructure %s as a list: %s", t, patternString(pattern))
+	}
+
+	for i := range elementTypes {
+		elementTypes[i] = Any
+	}
+	return elementTypes, Any
+}
+
+// patternString 返回模式在源代码中的写法，用于错误信息。
+func patternString(pattern ast.Pattern) string {
+	switch pattern := pattern.(type) {
+	case ast.IdentifierPattern:
+		return pattern.Name
+	case ast.ArrayPattern:
+		parts := make([]string, 0, len(pattern.Elements)+1)
+		for _, element := range pattern.Elements {
+			parts = append(parts, patternString(element))
+		}
+		if pattern.Rest != nil {
+			parts = append(parts, "..."+patternString(pattern.Rest))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case ast.ObjectPattern:
+		parts := make([]string, len(pattern.Properties))
+		for i, property := range pattern.Properties {
+			if identifier, ok := property.Value.(ast.IdentifierPattern); ok && identifier.Name == property.Key {
+				parts[i] = property.Key
+			} else {
+				parts[i] = property.Key + ": " + patternString(property.Value)
+			}
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	return "?"
+}
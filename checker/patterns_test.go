@@ -0,0 +1,35 @@
+package checker_test
+
+import "testing"
+
+func TestPatterns(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "tuple elements keep their types",
+			source: `let [n, s] = [1, "x"]; let m: number = n; let t: string = s;`,
+		},
+		{
+			name:   "short tuple",
+			source: `let t: (number, string) = [1, "x"]; let [a, b, c] = t;`,
+			err:    "tuple (number, string) has only 2 member(s)",
+		},
+		{
+			name:   "rest of a list",
+			source: `let xs: []number = [1, 2]; let [a, ...rest] = xs; let ys: []number = rest;`,
+		},
+		{
+			name:   "object pattern",
+			source: `class P { let x: number = 1; } let {x, x: alias} = new P(); let n: number = alias;`,
+		},
+		{
+			name:   "object pattern with an unknown member",
+			source: `class P { let x: number = 1; } let {y} = new P();`,
+			err:    "has no member y",
+		},
+		{
+			name:   "not a list",
+			source: `let [a] = "s";`,
+			err:    "cannot destructure string as a list",
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package checker
+
+// RegexMatchClass 是内置类 RegexMatch，表示正则表达式的一次匹配，它的字段都是只读的。
+//   - text: 匹配的文本
+//   - index: 匹配在字符串中的位置（以字符计）
+//   - groups: 各个捕获组匹配的文本，不包括整个匹配；没有参与匹配的组为 null
+//   - named: 命名捕获组 (?P<name>...) 的组名到匹配文本的映射
+var RegexMatchClass = &ClassType{
+	Name: "RegexMatch",
+	Fields: map[string]Type{
+		"text":   String,
+		"index":  Number,
+		"groups": &ListType{Element: NewUnion(String, Null)},
+		"named":  &MapType{Key: String, Value: NewUnion(String, Null)},
+	},
+	Methods: map[string]*FunctionType{},
+}
+
+// RegexClass 是内置类 Regex，即编译后的正则表达式：new Regex(pattern: string, flags?: string)，
+// 或者正则表达式字面量 /pattern/flags。source 和 flags 为只读字段。
+//
+// Regex 的方法:
+//   - test(s: string): bool，s 中是否有匹配；match 是它的别名，与 regex 模块的 match 函数同名
+//   - find(s: string): RegexMatch | null，第一个匹配
+//   - findAll(s: string, n?: number): []RegexMatch，至多 n 个不重叠的匹配，省略 n 时返回全部
+//   - replace(s: string, replacement: string | fn(RegexMatch): string): string，替换全部匹配；
+//     replacement 为字符串时其中的 $1 和 ${name} 展开为捕获组，为函数时以其返回值替换
+//   - split(s: string, n?: number): []string，以匹配为分隔符拆分 s，至多拆分为 n 个部分
+var RegexClass = func() *ClassType {
+	s := []string{"s"}
+	match := &InstanceType{Class: RegexMatchClass}
+	replacement := NewUnion(String, &FunctionType{Parameters: []Type{match}, ReturnType: String})
+
+	test := &FunctionType{Parameters: []Type{String}, ParameterNames: s, ReturnType: Bool}
+
+	return &ClassType{
+		Name:   "Regex",
+		Fields: map[string]Type{"source": String, "flags": String},
+		Methods: map[string]*FunctionType{
+			"test":    test,
+			"match":   test,
+			"find":    {Parameters: []Type{String}, ParameterNames: s, ReturnType: NewUnion(match, Null)},
+			"findAll": {Parameters: []Type{String, Number}, ParameterNames: []string{"s", "n"}, Optional: 1, ReturnType: &ListType{Element: match}},
+			"replace": {Parameters: []Type{String, replacement}, ParameterNames: []string{"s", "replacement"}, ReturnType: String},
+			"split":   {Parameters: []Type{String, Number}, ParameterNames: []string{"s", "n"}, Optional: 1, ReturnType: &ListType{Element: String}},
+		},
+		Constructor: &FunctionType{Parameters: []Type{String, String}, ParameterNames: []string{"pattern", "flags"}, Optional: 1, ReturnType: Void},
+	}
+}()
@@ -0,0 +1,195 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// assignable 判断类型为 from 的值能否赋值给类型为 to 的位置。
+//
+// 规则:
+//   - Any 可以赋值给任何类型，任何类型也都可以赋值给 Any。
+//   - 联合类型 from 的每个成员都能赋值给 to 时，from 才能赋值给 to；
+//     from 能赋值给联合类型 to 的任意一个成员时，即可赋值给 to。
+//   - 列表、字典和元组按成员逐一比较；函数的参数逆变、返回值协变，
+//     返回 void 的函数类型可以接受任意返回值的函数，参数更少的函数也可以作为回调传入，
+//     详见 assignableFunction。
+//   - 类型参数只与自身相同；作为来源时可以使用其约束进行比较。
+//   - 枚举值可以赋值给其成员值的类型。
+//   - 任何拥有接口全部字段和方法（且类型兼容）的类型都可以赋值给该接口。
+func (c *Checker) assignable(to, from Type) bool {
+	if to == Any || from == Any || identical(to, from) {
+		return true
+	}
+
+	if union, ok := from.(*UnionType); ok {
+		for _, member := range union.Members {
+			if !c.assignable(to, member) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if typeParam, ok := from.(*TypeParameter); ok && typeParam.Constraint != nil {
+		if c.assignable(to, typeParam.Constraint) {
+			return true
+		}
+	}
+
+	if enum, ok := from.(*EnumType); ok && c.assignable(to, enum.Underlying) {
+		return true
+	}
+
+	switch to := to.(type) {
+	case *UnionType:
+		for _, member := range to.Members {
+			if c.assignable(member, from) {
+				return true
+			}
+		}
+	case *ListType:
+		if from, ok := from.(*ListType); ok {
+			return c.assignable(to.Element, from.Element)
+		}
+	case *MapType:
+		if from, ok := from.(*MapType); ok {
+			return c.assignable(to.Key, from.Key) && c.assignable(to.Value, from.Value)
+		}
+	case *ChannelType:
+		if from, ok := from.(*ChannelType); ok {
+			return to.Element == Any || from.Element == Any
+		}
+	case *TupleType:
+		if from, ok := from.(*TupleType); ok && len(from.Members) == len(to.Members) {
+			for i := range to.Members {
+				if !c.assignable(to.Members[i], from.Members[i]) {
+					return false
+				}
+			}
+			return true
+		}
+	case *FunctionType:
+		if from, ok := from.(*FunctionType); ok && len(from.TypeParameters) == 0 {
+			return c.assignableFunction(to, from)
+		}
+	case *InstanceType:
+		if to.Class.Interface {
+			return c.missingMember(to, from) == ""
+		}
+
+		if from, ok := from.(*InstanceType); ok && from.Class == to.Class {
+			for i := range to.TypeArguments {
+				if !identical(to.TypeArguments[i], from.TypeArguments[i]) && to.TypeArguments[i] != Any && from.TypeArguments[i] != Any {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// assignableFunction 判断函数 from 能否用在需要函数类型 to 的位置。
+//
+// 按 to 调用时传入的每个实参都必须能传给 from 的对应参数（参数逆变）；from 可以忽略多余的实参，
+// 但 from 必须传入的参数个数不能多于 to 至少会传入的个数。to 有剩余参数时，from 也必须能接收任意多个实参。
+func (c *Checker) assignableFunction(to, from *FunctionType) bool {
+	if from.required() > to.required() {
+		return false
+	}
+
+	for i, param := range to.Parameters {
+		if fromParam := from.parameterAt(i); fromParam != nil && !c.assignable(fromParam, param) {
+			return false
+		}
+	}
+
+	if to.Rest != nil {
+		if from.Rest == nil || !c.assignable(from.Rest.Element, to.Rest.Element) {
+			return false
+		}
+		for i := len(to.Parameters); i < len(from.Parameters); i++ {
+			if !c.assignable(from.Parameters[i], to.Rest.Element) {
+				return false
+			}
+		}
+	}
+
+	return to.ReturnType == Void || c.assignable(to.ReturnType, from.ReturnType)
+}
+
+// missingMember 检查 from 是否在结构上满足接口 iface，满足时返回空字符串，
+// 否则返回对第一个缺失或类型不兼容的成员的描述。
+//
+// 比较递归接口（例如 interface Node { next(): Node }）时，正在比较的类型对被假定为满足，
+// 以免无限递归。
+func (c *Checker) missingMember(iface *InstanceType, from Type) string {
+	key := iface.String() + " <- " + from.String()
+	if c.assuming[key] {
+		return ""
+	}
+
+	c.assuming[key] = true
+	defer delete(c.assuming, key)
+
+	subst := classSubstitution(iface)
+	for _, name := range sortedKeys(iface.Class.Fields) {
+		expected := substitute(iface.Class.Fields[name], subst)
+		actual, exists := c.memberType(from, name)
+
+		if !exists {
+			return "missing field " + name
+		}
+		if !isField(from, name) || !c.assignable(expected, actual) {
+			return fmt.Sprintf("field %s has type %s, not %s", name, actual, expected)
+		}
+	}
+
+	for _, name := range sortedKeys(iface.Class.Methods) {
+		expected := substitute(iface.Class.Methods[name], subst)
+		actual, exists := c.memberType(from, name)
+
+		if !exists {
+			return "missing method " + name
+		}
+		if !c.assignable(expected, actual) {
+			return fmt.Sprintf("method %s has type %s, not %s", name, actual, expected)
+		}
+	}
+
+	return ""
+}
+
+// isField 判断 name 是否为实例类型 t 的字段而非方法。
+func isField(t Type, name string) bool {
+	instance, ok := t.(*InstanceType)
+	if !ok {
+		return true
+	}
+
+	_, exists := instance.Class.Fields[name]
+	return exists
+}
+
+// explainMismatch 在 to 为接口时返回 from 不满足该接口的原因，用于补充错误信息。
+func (c *Checker) explainMismatch(to, from Type) string {
+	if iface, ok := to.(*InstanceType); ok && iface.Class.Interface {
+		if reason := c.missingMember(iface, from); reason != "" {
+			return " (" + reason + ")"
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
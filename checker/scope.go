@@ -0,0 +1,130 @@
+package checker
+
+// Symbol 表示作用域中声明的一个值，例如变量、常量、函数或参数。
+// 变量被收窄时（例如在 if 的分支中或提前返回之后），Type 为收窄后的类型，Declared 为声明的类型（用于检查赋值）；
+// 未被收窄时 Declared 为 nil。
+type Symbol struct {
+	Name     string
+	Type     Type
+	Constant bool
+	Declared Type
+}
+
+// Scope 表示一个词法作用域。值和类型位于不同的命名空间中，
+// 因此类 Box 既可以作为类型名使用，也不会与同名变量冲突。
+// narrowed 保存提前返回之后被收窄的变量原来在该作用域中的符号（变量声明在外层作用域时为 nil），没有时为 nil。
+// captured 为该作用域的语句中被嵌套的函数赋值的变量名，这些变量不能被收窄，见 assignments。
+type Scope struct {
+	parent   *Scope
+	values   map[string]*Symbol
+	types    map[string]Type
+	narrowed map[string]*Symbol
+	captured map[string]bool
+}
+
+func newScope(parent *Scope) *Scope {
+	return &Scope{
+		parent: parent,
+		values: make(map[string]*Symbol),
+		types:  make(map[string]Type),
+	}
+}
+
+// ErrorClass 是内置的 Error 类。catch 子句捕获到的值总是 Error 的实例：
+// message 为错误信息，value 为 throw 语句抛出的原始值，stack 为抛出时的调用栈。
+var ErrorClass = &ClassType{
+	Name: "Error",
+	Fields: map[string]Type{
+		"message": String,
+		"value":   Any,
+		"stack":   &ListType{Element: String},
+	},
+	Methods:     map[string]*FunctionType{},
+	Constructor: &FunctionType{Parameters: []Type{Any}, ReturnType: Void},
+}
+
+// TypeClass 是内置的 Type 类，即 typeof 表达式的结果，描述一个值的运行时类型:
+// name 为类型名（例如 number、[]string 或类名），kind 为类型的种类（例如 number、list、instance 或 class），
+// fields 和 methods 为实例的字段名和方法名，element 为列表的元素类型（其它值为 null）。
+// Type 与字符串比较时比较的是 name，因此可以写 typeof x == "string"。
+var TypeClass = func() *ClassType {
+	class := &ClassType{Name: "Type", Methods: map[string]*FunctionType{}}
+	class.Fields = map[string]Type{
+		"name":    String,
+		"kind":    String,
+		"fields":  &ListType{Element: String},
+		"methods": &ListType{Element: String},
+		"element": NewUnion(&InstanceType{Class: class}, Null),
+	}
+	return class
+}()
+
+// closeFunction 是内置函数 close 的类型：close(ch) 关闭通道，之后不能再向它发送值。
+var closeFunction = &FunctionType{
+	Parameters:     []Type{&ChannelType{Element: Any}},
+	ParameterNames: []string{"ch"},
+	ReturnType:     Void,
+}
+
+// printFunction 是内置函数 print 和 println 的类型：print(...values) 输出各个值的字符串形式，以空格分隔。
+var printFunction = &FunctionType{
+	Rest:       &ListType{Element: Any},
+	ReturnType: Void,
+}
+
+// printfFunction 是内置函数 printf 的类型：printf(format, ...args) 按 printf 风格的格式输出。
+var printfFunction = &FunctionType{
+	Parameters:     []Type{String},
+	ParameterNames: []string{"format"},
+	Rest:           &ListType{Element: Any},
+	ReturnType:     Void,
+}
+
+// readLineFunction 是内置函数 readLine 的类型：readLine() 从标准输入读取一行，输入结束时返回 null。
+var readLineFunction = &FunctionType{
+	ReturnType: NewUnion(String, Null),
+}
+
+// newUniverseScope 创建最外层的作用域，其中包含内置的基本类型、Error、Set、Map、Regex、RegexMatch 和 Type 类，
+// 以及内置函数 close、print、println、printf 和 readLine。
+func newUniverseScope() *Scope {
+	scope := newScope(nil)
+	scope.values["close"] = &Symbol{Name: "close", Type: closeFunction, Constant: true}
+	scope.values["print"] = &Symbol{Name: "print", Type: printFunction, Constant: true}
+	scope.values["println"] = &Symbol{Name: "println", Type: printFunction, Constant: true}
+	scope.values["printf"] = &Symbol{Name: "printf", Type: printfFunction, Constant: true}
+	scope.values["readLine"] = &Symbol{Name: "readLine", Type: readLineFunction, Constant: true}
+
+	for _, primitive := range []*PrimitiveType{Number, String, Bool, Null, Void, Any} {
+		scope.types[primitive.Name] = primitive
+	}
+	scope.types["boolean"] = Bool
+	scope.types[ErrorClass.Name] = ErrorClass
+	scope.types[SetClass.Name] = SetClass
+	scope.types[MapClass.Name] = MapClass
+	scope.types[RegexClass.Name] = RegexClass
+	scope.types[RegexMatchClass.Name] = RegexMatchClass
+	scope.types[TypeClass.Name] = TypeClass
+
+	return scope
+}
+
+func (s *Scope) lookupValue(name string) (*Symbol, bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if symbol, exists := scope.values[name]; exists {
+			return symbol, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *Scope) lookupType(name string) (Type, bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if t, exists := scope.types[name]; exists {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,566 @@
+package checker
+
+import (
+	"fmt"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+const constructorName = "constructor"
+
+// checkStatements 检查一个语句列表。
+//
+// 检查分为四步：
+//  1. 声明列表中所有类、接口和枚举的名称，使它们可以互相引用；
+//  2. 按顺序解析类型别名；
+//  3. 解析类和接口的成员以及函数的签名，使函数和类可以在声明之前被使用；
+//  4. 按顺序检查每条语句，包括函数体和方法体。if 语句的分支以 return 或 throw 结束时，之后的语句中变量按条件收窄，见 narrowAfter。
+func (c *Checker) checkStatements(body []ast.Stmt) {
+	classDecls := make([]ast.ClassDeclarationStmt, 0)
+	classes := make([]*ClassType, 0)
+	interfaceDecls := make([]ast.InterfaceDeclarationStmt, 0)
+	interfaces := make([]*ClassType, 0)
+
+	for _, stmt := range body {
+		switch decl := stmt.(type) {
+		case ast.ClassDeclarationStmt:
+			class := newClassType(decl.Name, false)
+			c.declareType(decl.Name, class)
+			classDecls = append(classDecls, decl)
+			classes = append(classes, class)
+		case ast.InterfaceDeclarationStmt:
+			iface := newClassType(decl.Name, true)
+			c.declareType(decl.Name, iface)
+			interfaceDecls = append(interfaceDecls, decl)
+			interfaces = append(interfaces, iface)
+		case ast.EnumDeclarationStmt:
+			c.declareEnum(decl)
+		}
+	}
+
+	for _, stmt := range body {
+		if decl, ok := stmt.(ast.TypeAliasStmt); ok {
+			c.declareType(decl.Name, c.resolveType(decl.Type))
+		}
+	}
+
+	for i, decl := range interfaceDecls {
+		c.declareInterfaceMembers(decl, interfaces[i])
+	}
+
+	for i, decl := range classDecls {
+		c.declareClassMembers(decl, classes[i])
+	}
+
+	for _, stmt := range body {
+		if decl, ok := stmt.(ast.FunctionDeclarationStmt); ok {
+			c.declareValue(decl.Name, c.functionSignature(decl.TypeParameters, decl.Parameters, decl.ReturnType), true)
+		}
+	}
+
+	c.scope.captured = collectAssignments(body).captured
+	for _, stmt := range body {
+		c.checkStmt(stmt)
+		c.narrowScope(c.narrowAfter(stmt))
+	}
+	c.restoreScope()
+}
+
+func newClassType(name string, isInterface bool) *ClassType {
+	return &ClassType{
+		Name:      name,
+		Interface: isInterface,
+		Fields:    make(map[string]Type),
+		Methods:   make(map[string]*FunctionType),
+	}
+}
+
+// declareEnum 声明枚举类型，以及同名的、用于访问成员的枚举对象。
+// 显式给出的成员值必须是数字或字符串常量；字符串成员之后的成员必须显式给出值，因为无法自增。
+func (c *Checker) declareEnum(decl ast.EnumDeclarationStmt) {
+	enum := &EnumType{Name: decl.Name}
+	valueTypes := make([]Type, 0)
+	autoIncrement := true
+
+	for _, member := range decl.Members {
+		if enum.hasMember(member.Name) {
+			c.errorf("enum member %s.%s redeclared", decl.Name, member.Name)
+		}
+		enum.Members = append(enum.Members, member.Name)
+
+		switch value := member.Value.(type) {
+		case nil:
+			if !autoIncrement {
+				c.errorf("enum member %s.%s must have an explicit value because it follows a string member", decl.Name, member.Name)
+			}
+			valueTypes = append(valueTypes, Number)
+		case ast.NumberExpr:
+			autoIncrement = true
+			valueTypes = append(valueTypes, Number)
+		case ast.StringExpr:
+			autoIncrement = false
+			valueTypes = append(valueTypes, String)
+		case ast.PrefixExpr:
+			if _, ok := value.Right.(ast.NumberExpr); ok && value.Operator.Kind == lexer.TokenTypeSymbolDash {
+				autoIncrement = true
+				valueTypes = append(valueTypes, Number)
+				continue
+			}
+			c.errorf("enum member %s.%s must be a constant number or string", decl.Name, member.Name)
+		default:
+			c.errorf("enum member %s.%s must be a constant number or string", decl.Name, member.Name)
+		}
+	}
+
+	enum.Underlying = Number
+	if len(valueTypes) > 0 {
+		enum.Underlying = NewUnion(valueTypes...)
+	}
+
+	c.declareType(decl.Name, enum)
+	c.declareValue(decl.Name, &EnumObjectType{Enum: enum}, true)
+}
+
+// declareInterfaceMembers 解析接口的类型参数、字段和方法签名。
+func (c *Checker) declareInterfaceMembers(decl ast.InterfaceDeclarationStmt, iface *ClassType) {
+	c.pushScope()
+	defer c.popScope()
+
+	iface.TypeParameters = c.declareTypeParameters(decl.TypeParameters)
+
+	for _, field := range decl.Fields {
+		if _, exists := iface.Fields[field.Name]; exists {
+			c.errorf("field %s redeclared in interface %s", field.Name, iface.Name)
+		}
+		iface.Fields[field.Name] = c.resolveType(field.Type)
+	}
+
+	for _, method := range decl.Methods {
+		if _, exists := iface.Methods[method.Name]; exists {
+			c.errorf("method %s redeclared in interface %s", method.Name, iface.Name)
+		}
+		iface.Methods[method.Name] = c.functionSignature(nil, method.Parameters, method.ReturnType)
+	}
+}
+
+// functionSignature 根据类型参数、参数列表和返回类型构造函数签名。类型参数只在解析签名期间可见。
+// 剩余参数的类型必须是列表类型。省略了类型的参数（只出现在箭头函数中）暂时为 any，
+// 由 checkFunctionExpr 根据期望的函数类型确定。
+func (c *Checker) functionSignature(typeParams []ast.TypeParameter, params []ast.Parameter, returnType ast.Type) *FunctionType {
+	c.pushScope()
+	defer c.popScope()
+
+	signature := &FunctionType{
+		TypeParameters: c.declareTypeParameters(typeParams),
+		Parameters:     make([]Type, 0, len(params)),
+		ParameterNames: make([]string, 0, len(params)),
+		ReturnType:     Void,
+	}
+
+	for _, param := range params {
+		paramType := c.resolveType(param.Type)
+
+		if param.Rest {
+			list, ok := paramType.(*ListType)
+			if param.Type == nil {
+				list, ok = &ListType{Element: Any}, true
+			}
+			if !ok {
+				c.errorf("rest parameter %s must have a list type, not %s", patternString(param.Pattern), paramType)
+				list = &ListType{Element: Any}
+			}
+			signature.Rest = list
+			continue
+		}
+
+		name := ""
+		if identifier, ok := param.Pattern.(ast.IdentifierPattern); ok {
+			name = identifier.Name
+		}
+
+		signature.Parameters = append(signature.Parameters, paramType)
+		signature.ParameterNames = append(signature.ParameterNames, name)
+		if param.Default != nil {
+			signature.Optional++
+		}
+	}
+
+	if returnType != nil {
+		signature.ReturnType = c.resolveType(returnType)
+	}
+
+	return signature
+}
+
+// declareClassMembers 解析类的类型参数、字段和方法签名。名为 constructor 的方法作为构造函数。
+func (c *Checker) declareClassMembers(decl ast.ClassDeclarationStmt, class *ClassType) {
+	c.pushScope()
+	defer c.popScope()
+
+	class.TypeParameters = c.declareTypeParameters(decl.TypeParameters)
+
+	for _, member := range decl.Body {
+		switch member := member.(type) {
+		case ast.VarDeclarationStmt:
+			field, ok := member.Pattern.(ast.IdentifierPattern)
+			if !ok {
+				c.errorf("field %s of class %s cannot be a destructuring pattern", patternString(member.Pattern), class.Name)
+				continue
+			}
+
+			if _, exists := class.Fields[field.Name]; exists {
+				c.errorf("field %s redeclared in class %s", field.Name, class.Name)
+			}
+
+			if member.ExplicitType != nil {
+				class.Fields[field.Name] = c.resolveType(member.ExplicitType)
+			} else {
+				class.Fields[field.Name] = c.checkExpr(member.AssignedValue, nil)
+			}
+		case ast.FunctionDeclarationStmt:
+			signature := c.functionSignature(member.TypeParameters, member.Parameters, member.ReturnType)
+
+			if member.Name == constructorName {
+				class.Constructor = signature
+			} else if _, exists := class.Methods[member.Name]; exists {
+				c.errorf("method %s redeclared in class %s", member.Name, class.Name)
+			} else {
+				class.Methods[member.Name] = signature
+			}
+		default:
+			c.errorf("unexpected %T in body of class %s", member, class.Name)
+		}
+	}
+}
+
+func (c *Checker) checkStmt(stmt ast.Stmt) {
+	switch stmt := stmt.(type) {
+	case ast.BlockStmt:
+		c.pushScope()
+		c.checkStatements(stmt.Body)
+		c.popScope()
+	case ast.ExpressionStmt:
+		c.checkExpr(stmt.Expression, nil)
+	case ast.VarDeclarationStmt:
+		c.checkVarDeclaration(stmt)
+	case ast.FunctionDeclarationStmt:
+		symbol, _ := c.scope.lookupValue(stmt.Name)
+		c.checkFunctionBody(stmt.Name, symbol.Type.(*FunctionType), stmt.Parameters, stmt.Body, false)
+	case ast.ClassDeclarationStmt:
+		c.checkClassDeclaration(stmt)
+	case ast.IfStmt:
+		c.checkExpr(stmt.Condition, nil)
+		c.checkNarrowed(stmt.Consequent, c.narrowCondition(stmt.Condition, true))
+		if stmt.Alternate != nil {
+			c.checkNarrowed(stmt.Alternate, c.narrowCondition(stmt.Condition, false))
+		}
+	case ast.ForeachStmt:
+		c.checkForeach(stmt)
+	case ast.ImportStmt:
+		c.checkImport(stmt)
+	case ast.ReturnStmt:
+		c.checkReturn(stmt)
+	case ast.ThrowStmt:
+		c.checkExpr(stmt.Value, nil)
+	case ast.TryStmt:
+		c.checkTry(stmt)
+	case ast.SpawnStmt:
+		c.checkSpawn(stmt)
+	case ast.SelectStmt:
+		c.checkSelect(stmt)
+	case ast.SwitchStmt:
+		c.checkSwitch(stmt)
+	case ast.TypeAliasStmt, ast.EnumDeclarationStmt, ast.InterfaceDeclarationStmt:
+		// 已在 checkStatements 的声明阶段处理。
+	default:
+		panic(fmt.Sprintf("checker: unsupported statement %T", stmt))
+	}
+}
+
+func (c *Checker) checkVarDeclaration(stmt ast.VarDeclarationStmt) {
+	var explicitType Type
+	if stmt.ExplicitType != nil {
+		explicitType = c.resolveType(stmt.ExplicitType)
+	}
+
+	declaredType := explicitType
+	if stmt.AssignedValue != nil {
+		expected := explicitType
+		if expected == nil {
+			expected = patternShape(stmt.Pattern)
+		}
+
+		valueType := c.checkExpr(stmt.AssignedValue, expected)
+
+		if explicitType == nil {
+			declaredType = valueType
+		} else if !c.assignable(explicitType, valueType) {
+			c.errorf("cannot assign %s to %s of type %s%s", valueType, patternString(stmt.Pattern), explicitType, c.explainMismatch(explicitType, valueType))
+		}
+	}
+
+	c.declarePattern(stmt.Pattern, declaredType, stmt.Constant)
+}
+
+// checkFunctionBody 在新的作用域中检查函数体：类型参数和参数在函数体内可见。
+//
+// inferReturn 为 true 时（没有声明返回类型的函数字面量），返回类型由函数体中的 return 语句推断，
+// 并写回 signature.ReturnType。返回类型不是 void 的函数必须在每条执行路径的末尾返回或抛出错误，
+// 否则报告缺少 return。
+func (c *Checker) checkFunctionBody(name string, signature *FunctionType, params []ast.Parameter, body []ast.Stmt, inferReturn bool) {
+	c.pushScope()
+	enclosing := c.function
+	c.function = &functionContext{name: name, returnType: signature.ReturnType, inferReturn: inferReturn}
+
+	defer func() {
+		c.function = enclosing
+		c.popScope()
+	}()
+
+	for _, typeParam := range signature.TypeParameters {
+		c.scope.types[typeParam.Name] = typeParam
+	}
+
+	for i, param := range params {
+		if param.Rest {
+			c.declarePattern(param.Pattern, signature.Rest, false)
+			continue
+		}
+
+		if param.Default != nil {
+			c.expectType(signature.Parameters[i], c.checkExpr(param.Default, signature.Parameters[i]), "default value of "+patternString(param.Pattern))
+		}
+		c.declarePattern(param.Pattern, signature.Parameters[i], false)
+	}
+
+	c.checkStatements(body)
+
+	if inferReturn && len(c.function.returns) > 0 {
+		signature.ReturnType = NewUnion(c.function.returns...)
+	}
+
+	if signature.ReturnType != Void && !alwaysReturns(body) {
+		c.errorf("missing return at end of %s", name)
+	}
+}
+
+// checkReturn 检查 return 语句：返回值必须能赋值给函数的返回类型，void 函数不能返回值。
+func (c *Checker) checkReturn(stmt ast.ReturnStmt) {
+	if c.function == nil {
+		c.errorf("return outside of function")
+		if stmt.Value != nil {
+			c.checkExpr(stmt.Value, nil)
+		}
+		return
+	}
+
+	valueType := Type(Void)
+	if stmt.Value != nil {
+		var expected Type
+		if !c.function.inferReturn {
+			expected = c.function.returnType
+		}
+		valueType = c.checkExpr(stmt.Value, expected)
+	}
+
+	if c.function.inferReturn {
+		if valueType != Void {
+			c.function.returns = append(c.function.returns, valueType)
+		}
+		return
+	}
+
+	if c.function.returnType == Void {
+		if valueType != Void {
+			c.errorf("too many return values in %s: function does not return a value", c.function.name)
+		}
+		return
+	}
+
+	if valueType == Void {
+		c.errorf("missing return value in %s: expected %s", c.function.name, c.function.returnType)
+		return
+	}
+
+	c.expectType(c.function.returnType, valueType, "return value of "+c.function.name)
+}
+
+// checkTry 检查 try 语句。catch 绑定的值总是 Error 的实例；
+// 给出类型注解时，该类型必须能够接收 Error。
+func (c *Checker) checkTry(stmt ast.TryStmt) {
+	c.pushScope()
+	c.checkStatements(stmt.Body)
+	c.popScope()
+
+	if stmt.Catch != nil {
+		c.pushScope()
+		if stmt.Catch.Name != "" {
+			bindingType := Type(&InstanceType{Class: ErrorClass})
+			if stmt.Catch.Type != nil {
+				declared := c.resolveType(stmt.Catch.Type)
+				c.expectType(declared, bindingType, "catch binding "+stmt.Catch.Name)
+				bindingType = declared
+			}
+			c.declareValue(stmt.Catch.Name, bindingType, false)
+		}
+		c.checkStatements(stmt.Catch.Body)
+		c.popScope()
+	}
+
+	if stmt.Finally != nil {
+		c.pushScope()
+		c.checkStatements(stmt.Finally)
+		c.popScope()
+	}
+}
+
+// alwaysReturns 判断语句列表是否在每条执行路径的末尾都以 return 或 throw 结束。
+func alwaysReturns(body []ast.Stmt) bool {
+	if len(body) == 0 {
+		return false
+	}
+
+	switch last := body[len(body)-1].(type) {
+	case ast.ReturnStmt, ast.ThrowStmt:
+		return true
+	case ast.BlockStmt:
+		return alwaysReturns(last.Body)
+	case ast.IfStmt:
+		return last.Alternate != nil && alwaysReturns([]ast.Stmt{last.Consequent}) && alwaysReturns([]ast.Stmt{last.Alternate})
+	case ast.TryStmt:
+		if last.Finally != nil && alwaysReturns(last.Finally) {
+			return true
+		}
+		return alwaysReturns(last.Body) && (last.Catch == nil || alwaysReturns(last.Catch.Body))
+	case ast.SelectStmt:
+		for _, selectCase := range last.Cases {
+			if !alwaysReturns(selectCase.Body) {
+				return false
+			}
+		}
+		return last.Default == nil || alwaysReturns(last.Default)
+	case ast.SwitchStmt:
+		for _, switchCase := range last.Cases {
+			if !alwaysReturns(switchCase.Body) {
+				return false
+			}
+		}
+		return alwaysReturns(last.Default)
+	}
+
+	return false
+}
+
+// checkClassDeclaration 检查类中字段的初始值和每个方法体。方法体中的 this 为以类自身类型参数实例化的实例类型。
+func (c *Checker) checkClassDeclaration(stmt ast.ClassDeclarationStmt) {
+	classType, _ := c.scope.lookupType(stmt.Name)
+	class := classType.(*ClassType)
+
+	c.pushScope()
+	defer c.popScope()
+
+	typeArgs := make([]Type, len(class.TypeParameters))
+	for i, typeParam := range class.TypeParameters {
+		c.scope.types[typeParam.Name] = typeParam
+		typeArgs[i] = typeParam
+	}
+	c.declareValue("this", &InstanceType{Class: class, TypeArguments: typeArgs}, true)
+
+	for _, member := range stmt.Body {
+		switch member := member.(type) {
+		case ast.VarDeclarationStmt:
+			field, ok := member.Pattern.(ast.IdentifierPattern)
+			if ok && member.ExplicitType != nil && member.AssignedValue != nil {
+				fieldType := class.Fields[field.Name]
+				if valueType := c.checkExpr(member.AssignedValue, fieldType); !c.assignable(fieldType, valueType) {
+					c.errorf("cannot assign %s to field %s of type %s", valueType, field.Name, fieldType)
+				}
+			}
+		case ast.FunctionDeclarationStmt:
+			signature := class.Methods[member.Name]
+			if member.Name == constructorName {
+				signature = class.Constructor
+			}
+			c.checkFunctionBody(class.Name+"."+member.Name, signature, member.Parameters, member.Body, false)
+		}
+	}
+}
+
+// checkImport 检查 import 语句，把模块的类型绑定到导入的名称上。未声明的模块会报告错误，导入的名称为 Any。
+func (c *Checker) checkImport(stmt ast.ImportStmt) {
+	module, exists := c.modules[stmt.From]
+	if !exists {
+		c.errorf("module %s not found", stmt.From)
+		c.declareValue(stmt.Name, Any, true)
+		return
+	}
+
+	c.declareValue(stmt.Name, module, true)
+}
+
+// checkForeach 检查 foreach 循环。遍历列表和字符串时下标为 number，遍历字典时下标为键的类型。
+// 遍历通道时依次接收通道中的值直到通道被关闭，此时不能绑定下标。
+func (c *Checker) checkForeach(stmt ast.ForeachStmt) {
+	iterableType := c.checkExpr(stmt.Iterable, nil)
+	var elementType Type
+	indexType := Type(Number)
+
+	switch iterable := iterableType.(type) {
+	case *ListType:
+		elementType = iterable.Element
+	case *MapType:
+		elementType = iterable.Value
+		indexType = iterable.Key
+	case *ChannelType:
+		elementType = iterable.Element
+		if stmt.Index != "" {
+			c.errorf("cannot bind an index when iterating over %s", iterable)
+			indexType = Any
+		}
+	default:
+		if iterableType == String {
+			elementType = String
+		} else if iterableType == Any {
+			elementType = Any
+			indexType = Any
+		} else {
+			c.errorf("cannot iterate over %s", iterableType)
+			elementType = Any
+			indexType = Any
+		}
+	}
+
+	// 循环体中被赋值的变量在下一次迭代开始时可能已经不是收窄后的类型，因此在整个循环体中恢复为声明的类型。
+	c.withNarrowing(c.widenAssigned(collectAssignments(stmt.Body).all), func() {
+		c.pushScope()
+		defer c.popScope()
+
+		if stmt.Index != "" {
+			c.declareValue(stmt.Index, indexType, false)
+		}
+		c.declarePattern(stmt.Value, elementType, false)
+		c.checkStatements(stmt.Body)
+	})
+}
+
+// checkSwitch 检查 switch 语句。值分支中的值可以是任意类型，与被比较的值用 == 比较；
+// 类型分支 case is T 与 x is T 的要求相同。每个分支按 switchCondition 收窄变量，default 分支按所有分支都不匹配收窄。
+func (c *Checker) checkSwitch(stmt ast.SwitchStmt) {
+	c.checkExpr(stmt.Value, nil)
+
+	for i, switchCase := range stmt.Cases {
+		if switchCase.Type != nil {
+			c.checkTestedType(switchCase.Type, lexer.TokenTypeKeywordIs)
+		}
+		for _, value := range switchCase.Values {
+			c.checkExpr(value, nil)
+		}
+
+		c.checkNarrowed(ast.BlockStmt{Body: switchCase.Body}, c.narrowCondition(switchCondition(stmt, i), true))
+	}
+
+	if stmt.Default != nil {
+		c.checkNarrowed(ast.BlockStmt{Body: stmt.Default}, c.narrowCondition(switchCondition(stmt, len(stmt.Cases)), true))
+	}
+}
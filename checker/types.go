@@ -0,0 +1,342 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type 表示类型检查器内部使用的语义类型。
+// 与 ast.Type 不同，ast.Type 只描述源代码中写出的类型语法，而 Type 是解析名称、展开别名之后的结果。
+type Type interface {
+	String() string
+}
+
+type PrimitiveType struct {
+	Name string
+}
+
+func (t *PrimitiveType) String() string { return t.Name }
+
+var (
+	Number = &PrimitiveType{Name: "number"}
+	String = &PrimitiveType{Name: "string"}
+	Bool   = &PrimitiveType{Name: "bool"}
+	Null   = &PrimitiveType{Name: "null"}
+	Void   = &PrimitiveType{Name: "void"}
+	Any    = &PrimitiveType{Name: "any"}
+)
+
+type ListType struct {
+	Element Type
+}
+
+func (t *ListType) String() string { return "[]" + parenthesize(t.Element) }
+
+type MapType struct {
+	Key   Type
+	Value Type
+}
+
+func (t *MapType) String() string { return fmt.Sprintf("map[%s]%s", t.Key, parenthesize(t.Value)) }
+
+// ChannelType 表示通道类型 chan<T>。通道既可以发送也可以接收，因此元素类型是不变的：
+// chan<number> 不能赋值给 chan<number | string>，反之亦然。
+type ChannelType struct {
+	Element Type
+}
+
+func (t *ChannelType) String() string { return "chan<" + t.Element.String() + ">" }
+
+type TupleType struct {
+	Members []Type
+}
+
+func (t *TupleType) String() string { return "(" + joinTypes(t.Members, ", ") + ")" }
+
+// UnionType 表示联合类型。请使用 NewUnion 创建，它会展开嵌套的联合类型并去除重复成员。
+type UnionType struct {
+	Members []Type
+}
+
+func (t *UnionType) String() string { return joinTypes(t.Members, " | ") }
+
+// TypeParameter 表示泛型函数或泛型类声明的一个类型参数。
+// 类型参数按指针比较，同名的两个类型参数（例如两个函数各自的 T）互不相同。
+type TypeParameter struct {
+	Name       string
+	Constraint Type
+}
+
+func (t *TypeParameter) String() string { return t.Name }
+
+// FunctionType 表示函数的签名。TypeParameters 非空时为泛型函数，调用时根据实参推断类型实参。
+//
+// 字段:
+//   - ParameterNames: 与 Parameters 一一对应的参数名，用于检查命名实参；解构参数的名称为空字符串。
+//     由函数类型语法 fn(number): string 得到的签名没有参数名，为 nil。
+//   - Optional: Parameters 中最后 Optional 个参数有默认值，调用时可以省略。
+//   - Rest: 剩余参数的类型（总是列表类型），不包含在 Parameters 中；没有剩余参数时为 nil。
+type FunctionType struct {
+	TypeParameters []*TypeParameter
+	Parameters     []Type
+	ParameterNames []string
+	Optional       int
+	Rest           *ListType
+	ReturnType     Type
+}
+
+// required 返回调用时必须传入的参数个数。
+func (t *FunctionType) required() int {
+	return len(t.Parameters) - t.Optional
+}
+
+// parameterAt 返回第 i 个位置实参对应的参数类型；超出全部参数时为剩余参数的元素类型，没有剩余参数时返回 nil。
+func (t *FunctionType) parameterAt(i int) Type {
+	if i < len(t.Parameters) {
+		return t.Parameters[i]
+	}
+	if t.Rest != nil {
+		return t.Rest.Element
+	}
+
+	return nil
+}
+
+// arity 描述调用时可以传入的参数个数，用于错误信息。
+func (t *FunctionType) arity() string {
+	switch {
+	case t.Rest != nil:
+		return fmt.Sprintf("at least %d", t.required())
+	case t.Optional > 0:
+		return fmt.Sprintf("%d to %d", t.required(), len(t.Parameters))
+	}
+
+	return fmt.Sprint(len(t.Parameters))
+}
+
+func (t *FunctionType) String() string {
+	var sb strings.Builder
+	sb.WriteString("fn")
+
+	if len(t.TypeParameters) > 0 {
+		names := make([]string, len(t.TypeParameters))
+		for i, typeParam := range t.TypeParameters {
+			names[i] = typeParam.Name
+		}
+		sb.WriteString("<" + strings.Join(names, ", ") + ">")
+	}
+
+	params := make([]string, len(t.Parameters), len(t.Parameters)+1)
+	for i, param := range t.Parameters {
+		params[i] = param.String()
+		if i >= t.required() {
+			params[i] += "?"
+		}
+	}
+	if t.Rest != nil {
+		params = append(params, "..."+t.Rest.String())
+	}
+
+	sb.WriteString("(" + strings.Join(params, ", ") + ")")
+	if t.ReturnType != Void {
+		sb.WriteString(": " + t.ReturnType.String())
+	}
+
+	return sb.String()
+}
+
+// ClassType 表示一个类或接口声明。它本身不是值的类型，类的实例的类型为 InstanceType。
+// Interface 为 true 时表示接口：接口不能被实例化，且按结构匹配，
+// 任何拥有接口全部字段和方法的类型都可以赋值给该接口。
+type ClassType struct {
+	Name           string
+	Interface      bool
+	TypeParameters []*TypeParameter
+	Fields         map[string]Type
+	Methods        map[string]*FunctionType
+	Constructor    *FunctionType
+}
+
+func (t *ClassType) String() string {
+	if t.Interface {
+		return "interface " + t.Name
+	}
+
+	return "class " + t.Name
+}
+
+// InstanceType 表示类的实例的类型，例如 Box<number>。
+// TypeArguments 与 Class.TypeParameters 一一对应；非泛型类的 TypeArguments 为空。
+type InstanceType struct {
+	Class         *ClassType
+	TypeArguments []Type
+}
+
+func (t *InstanceType) String() string {
+	if len(t.TypeArguments) == 0 {
+		return t.Class.Name
+	}
+
+	return t.Class.Name + "<" + joinTypes(t.TypeArguments, ", ") + ">"
+}
+
+// parenthesize 在 t 为联合类型或函数类型时为其加上括号，避免 []number | string 这样有歧义的写法。
+func parenthesize(t Type) string {
+	switch t.(type) {
+	case *UnionType, *FunctionType:
+		return "(" + t.String() + ")"
+	}
+
+	return t.String()
+}
+
+// EnumType 表示一个枚举类型。Underlying 为成员值的类型：全部为数字时为 number，
+// 全部为字符串时为 string，混合时为 number | string。枚举值可以赋值给其 Underlying 类型。
+type EnumType struct {
+	Name       string
+	Members    []string
+	Underlying Type
+}
+
+func (t *EnumType) String() string { return t.Name }
+
+func (t *EnumType) hasMember(name string) bool {
+	for _, member := range t.Members {
+		if member == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnumObjectType 是枚举名作为值使用时的类型，例如 Color.Red 中的 Color。
+type EnumObjectType struct {
+	Enum *EnumType
+}
+
+func (t *EnumObjectType) String() string { return "enum " + t.Enum.Name }
+
+// ModuleType 是 import 语句导入的模块的类型，Members 为成员名到成员类型的映射。
+type ModuleType struct {
+	Name    string
+	Members map[string]Type
+}
+
+func (t *ModuleType) String() string { return "module " + t.Name }
+
+func joinTypes(types []Type, sep string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.String()
+	}
+
+	return strings.Join(names, sep)
+}
+
+// NewUnion 创建由 members 组成的联合类型。嵌套的联合类型会被展开，重复的成员会被去除；
+// 去重后只剩一个成员时直接返回该成员，含有 Any 时返回 Any。
+func NewUnion(members ...Type) Type {
+	flat := make([]Type, 0, len(members))
+
+	for _, member := range members {
+		var parts []Type
+		if union, ok := member.(*UnionType); ok {
+			parts = union.Members
+		} else {
+			parts = []Type{member}
+		}
+
+		for _, part := range parts {
+			if part == Any {
+				return Any
+			}
+
+			duplicate := false
+			for _, existing := range flat {
+				if identical(existing, part) {
+					duplicate = true
+					break
+				}
+			}
+
+			if !duplicate {
+				flat = append(flat, part)
+			}
+		}
+	}
+
+	if len(flat) == 1 {
+		return flat[0]
+	}
+
+	return &UnionType{Members: flat}
+}
+
+// identical 判断两个类型在结构上是否完全相同。
+func identical(a, b Type) bool {
+	if a == b {
+		return true
+	}
+
+	switch a := a.(type) {
+	case *ListType:
+		b, ok := b.(*ListType)
+		return ok && identical(a.Element, b.Element)
+	case *MapType:
+		b, ok := b.(*MapType)
+		return ok && identical(a.Key, b.Key) && identical(a.Value, b.Value)
+	case *ChannelType:
+		b, ok := b.(*ChannelType)
+		return ok && identical(a.Element, b.Element)
+	case *TupleType:
+		b, ok := b.(*TupleType)
+		return ok && identicalLists(a.Members, b.Members)
+	case *UnionType:
+		b, ok := b.(*UnionType)
+		if !ok || len(a.Members) != len(b.Members) {
+			return false
+		}
+		for _, member := range a.Members {
+			if !containsType(b.Members, member) {
+				return false
+			}
+		}
+		return true
+	case *FunctionType:
+		b, ok := b.(*FunctionType)
+		return ok && len(a.TypeParameters) == 0 && len(b.TypeParameters) == 0 &&
+			identicalLists(a.Parameters, b.Parameters) && a.Optional == b.Optional &&
+			(a.Rest == nil) == (b.Rest == nil) && (a.Rest == nil || identical(a.Rest, b.Rest)) &&
+			identical(a.ReturnType, b.ReturnType)
+	case *InstanceType:
+		b, ok := b.(*InstanceType)
+		return ok && a.Class == b.Class && identicalLists(a.TypeArguments, b.TypeArguments)
+	}
+
+	return false
+}
+
+func identicalLists(a, b []Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !identical(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsType(types []Type, t Type) bool {
+	for _, candidate := range types {
+		if identical(candidate, t) {
+			return true
+		}
+	}
+
+	return false
+}
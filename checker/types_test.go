@@ -0,0 +1,68 @@
+package checker_test
+
+import "testing"
+
+func TestTypeSyntax(t *testing.T) {
+	runCheckTests(t, []checkTest{
+		{
+			name:   "function type",
+			source: `let f: fn(number, string): bool = fn(n: number, s: string): bool { return n > 0; }; let b: bool = f(1, "a");`,
+		},
+		{
+			name:   "function type checks the arguments",
+			source: `let f: fn(number): bool = fn(n: number): bool { return true; }; f("a");`,
+			err:    "expected number but got string",
+		},
+		{
+			name:   "function type checks the return type",
+			source: `let f: fn(number): bool = fn(n: number): string { return "a"; };`,
+			err:    "cannot assign fn(number): string to f of type fn(number): bool",
+		},
+		{
+			name:   "union",
+			source: `let x: number | string = 1; x = "a";`,
+		},
+		{
+			name:   "union rejects other members",
+			source: `let x: number | string = true;`,
+			err:    "cannot assign bool to x of type number | string",
+		},
+		{
+			name:   "optional",
+			source: `let x: number? = null; x = 1;`,
+		},
+		{
+			name:   "optional rejects other types",
+			source: `let x: number? = "a";`,
+			err:    "cannot assign string to x",
+		},
+		{
+			name:   "tuple",
+			source: `let p: (number, string) = [1, "a"]; let n: number = p[0]; let s: string = p[1];`,
+		},
+		{
+			name:   "tuple members are checked",
+			source: `let p: (number, string) = ["a", 1];`,
+			err:    "cannot assign (string, number) to p of type (number, string)",
+		},
+		{
+			name:   "tuple index out of range",
+			source: `let p: (number, string) = [1, "a"]; p[2];`,
+			err:    "must be indexed by a constant between 0 and 1",
+		},
+		{
+			name:   "generic application",
+			source: `class Box<T> { let value: T; fn constructor(value: T) { this.value = value; } } let b: Box<number> = new Box<number>(1); let n: number = b.value;`,
+		},
+		{
+			name:   "generic application requires type arguments",
+			source: `class Box<T> { let value: T; fn constructor(value: T) { this.value = value; } } let b: Box<number, string> = new Box<number>(1);`,
+			err:    "requires 1 type argument(s)",
+		},
+		{
+			name:   "undefined type",
+			source: `let x: Missing = 1;`,
+			err:    "undefined type Missing",
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dreamlang"
+	"dreamlang/parser"
+
+	"github.com/sanity-io/litter"
+)
+
+const usage = `usage:
+  dream                            解析并执行当前目录下的 test.lang，同时输出抽象语法树
+  dream run [--allow=cap,...] file  执行 file，--allow 授予程序的能力，例如 --allow=fs.read:/data,env,net,time`
+
+// main 函数是程序的入口点。以 dream run 运行时执行指定的文件，否则执行 demo。
+func main() {
+	if len(os.Args) > 1 {
+		if os.Args[1] != "run" {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		os.Exit(run(os.Args[2:]))
+	}
+
+	demo()
+}
+
+// run 实现 dream run 命令，返回进程的退出码。
+func run(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	allow := flags.String("allow", "", "逗号分隔的能力列表，例如 fs.read:/data,env,net,time")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	source, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	rt := dreamlang.NewRuntime(dreamlang.Options{})
+	if *allow != "" {
+		if err := rt.Grant(strings.Split(*allow, ",")...); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	if _, err := rt.Eval(context.Background(), string(source)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// demo 执行以下操作：
+// 1. 读取名为 "test.lang" 的文件内容，并将其转换为字符串。
+// 2. 记录解析操作的开始时间。
+// 3. 使用 parser.Parse 函数解析源代码字符串，生成抽象语法树（AST）。
+// 4. 计算解析操作所花费的时间。
+// 5. 使用 litter.Dump 函数输出生成的 AST。
+// 6. 使用 dreamlang.Runtime 对程序进行类型检查并执行，打印类型错误或未被捕获的错误及其调用栈。
+// 7. 打印解析操作所花费的时间。
+func demo() {
+	sourceBytes, _ := os.ReadFile("test.lang")
+	source := string(sourceBytes)
+	start := time.Now()
+	ast := parser.Parse(source)
+	duration := time.Since(start)
+
+	litter.Dump(ast)
+	if _, err := dreamlang.NewRuntime(dreamlang.Options{}).Eval(context.Background(), source); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("Duration: %v\n", duration)
+}
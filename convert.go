@@ -0,0 +1,644 @@
+package dreamlang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"dreamlang/checker"
+	"dreamlang/helpers"
+	"dreamlang/interpreter"
+)
+
+var (
+	anyType     = helpers.TypeOf[any]()
+	errorType   = helpers.TypeOf[error]()
+	contextType = helpers.TypeOf[context.Context]()
+)
+
+// isRuntimeValue 判断 v 是否已经是解释器中的值，例如通过回调传给 Go 的 DreamLang 函数。这些值原样传递。
+func isRuntimeValue(v any) bool {
+	switch v.(type) {
+	case *interpreter.Array, *interpreter.Map, *interpreter.Function, *interpreter.Builtin, *interpreter.Class,
+		*interpreter.Instance, *interpreter.Enum, *interpreter.ErrorValue, *interpreter.Channel, *interpreter.Module,
+		*interpreter.Set, *interpreter.HashMap, *interpreter.Regex, *interpreter.TypeInfo:
+		return true
+	}
+
+	return false
+}
+
+// fieldName 返回结构体字段在 DreamLang 中的名称：`dream:"name"` 标签指定的名称，或者首字母小写的字段名。
+// 未导出的字段和标签为 "-" 的字段返回空字符串。
+func fieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	if tag, ok := field.Tag.Lookup("dream"); ok {
+		if tag == "-" {
+			return ""
+		}
+		return tag
+	}
+
+	r, size := utf8.DecodeRuneInString(field.Name)
+	return string(unicode.ToLower(r)) + field.Name[size:]
+}
+
+// toValue 把 Go 的值 v 转换为 DreamLang 的值，what 描述被转换的值，用于错误信息。
+func toValue(what string, v reflect.Value) (interpreter.Value, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.CanInterface() && isRuntimeValue(v.Interface()) {
+		return v.Interface(), nil
+	}
+
+	if v.Type().Implements(errorType) && !isNil(v) {
+		message := v.Interface().(error).Error()
+		return &interpreter.ErrorValue{Message: message, Value: message}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return toValue(what, v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]interpreter.Value, v.Len())
+		for i := range elements {
+			element, err := toValue(fmt.Sprintf("%s[%d]", what, i), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return &interpreter.Array{Elements: elements}, nil
+	case reflect.Map:
+		m := interpreter.NewMap()
+		entries := make([][2]interpreter.Value, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toValue(what+" key", iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			value, err := toValue(fmt.Sprintf("%s[%s]", what, interpreter.Stringify(key)), iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, [2]interpreter.Value{key, value})
+		}
+		// Go 的 map 没有顺序，按键排序使得到的字典的遍历顺序是确定的。
+		sort.Slice(entries, func(i, j int) bool { return lessKey(entries[i][0], entries[j][0]) })
+		for _, entry := range entries {
+			m.Set(entry[0], entry[1])
+		}
+		return m, nil
+	case reflect.Struct:
+		m := interpreter.NewMap()
+		for i := 0; i < v.NumField(); i++ {
+			name := fieldName(v.Type().Field(i))
+			if name == "" {
+				continue
+			}
+			value, err := toValue(what+"."+name, v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			m.Set(name, value)
+		}
+		return m, nil
+	case reflect.Func:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if _, err := signatureOf(v.Type()); err != nil {
+			return nil, err
+		}
+		return wrapFunc(what, v), nil
+	}
+
+	return nil, fmt.Errorf("cannot convert %s of Go type %s to a DreamLang value", what, v.Type())
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// lessKey 比较两个字典键，数字排在字符串之前，其它类型的键按字符串形式比较。
+func lessKey(a, b interpreter.Value) bool {
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			return a < b
+		}
+		return true
+	case string:
+		if b, ok := b.(string); ok {
+			return a < b
+		}
+		_, isNumber := b.(float64)
+		return !isNumber && a < interpreter.Stringify(b)
+	}
+
+	return interpreter.Stringify(a) < interpreter.Stringify(b)
+}
+
+// errCyclic 是转换引用了自身的列表、字典或实例时返回的错误，这样的值无法转换为 Go 的值。
+var errCyclic = errors.New("cannot convert cyclic value")
+
+// visit 把容器 v 记入 visiting，v 已经在其中（即 v 引用了自身）时返回 errCyclic。
+// 返回的函数在转换完 v 之后把它从 visiting 中移除，因此被多处引用但没有循环的值仍然可以转换。
+func visit(v any, visiting map[any]bool) (func(), error) {
+	if visiting[v] {
+		return nil, errCyclic
+	}
+	visiting[v] = true
+
+	return func() { delete(visiting, v) }, nil
+}
+
+// natural 把 DreamLang 的值转换为最自然的 Go 值，用于转换为 any 的情况。visiting 为正在转换的容器。
+func natural(v interpreter.Value, visiting map[any]bool) (any, error) {
+	switch v := v.(type) {
+	case *interpreter.Array:
+		leave, err := visit(v, visiting)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
+		elements := make([]any, len(v.Elements))
+		for i, element := range v.Elements {
+			if elements[i], err = natural(element, visiting); err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	case *interpreter.Map:
+		leave, err := visit(v, visiting)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
+		stringKeys := true
+		for _, key := range v.Keys {
+			if _, ok := key.(string); !ok {
+				stringKeys = false
+				break
+			}
+		}
+
+		if stringKeys {
+			m := make(map[string]any, len(v.Keys))
+			for _, key := range v.Keys {
+				if m[key.(string)], err = natural(v.Values[key], visiting); err != nil {
+					return nil, err
+				}
+			}
+			return m, nil
+		}
+
+		m := make(map[any]any, len(v.Keys))
+		for _, key := range v.Keys {
+			naturalKey, err := natural(key, visiting)
+			if err != nil {
+				return nil, err
+			}
+			if m[naturalKey], err = natural(v.Values[key], visiting); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case *interpreter.Set:
+		return natural(&interpreter.Array{Elements: v.Entries.Keys}, visiting)
+	case *interpreter.HashMap:
+		return natural(v.Entries, visiting)
+	}
+
+	return v, nil
+}
+
+// fromValue 把 DreamLang 的值 v 转换为 Go 类型 t 的值。
+//
+// 参数:
+//   - v: 要转换的值。
+//   - t: 目标 Go 类型。
+//   - in: 当前正在执行的解释器，把 DreamLang 的函数转换为 Go 函数时用于回调；不在运行期间转换时为 nil。
+//
+// 返回值:
+//   - any: 转换后的值，其动态类型为 t（t 为 any 时按包文档中转换为 any 的规则）。
+//   - error: v 无法转换为 t 时返回错误，包括 v 引用了自身的情况。
+func fromValue(v interpreter.Value, t reflect.Type, in *interpreter.Interpreter) (any, error) {
+	converted, err := convertValue(v, t, in, map[any]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	return converted.Interface(), nil
+}
+
+// convertValue 实现 fromValue。visiting 为正在转换的列表、字典和实例，用于发现引用了自身的值。
+func convertValue(v interpreter.Value, t reflect.Type, in *interpreter.Interpreter, visiting map[any]bool) (reflect.Value, error) {
+	mismatch := func() (reflect.Value, error) {
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to Go type %s", interpreter.TypeName(v), t)
+	}
+
+	if t.Kind() == reflect.Interface {
+		if t.NumMethod() == 0 {
+			if v == nil {
+				return reflect.Zero(t), nil
+			}
+			converted, err := natural(v, visiting)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(converted), nil
+		}
+		if v != nil && reflect.TypeOf(v).Implements(t) {
+			return reflect.ValueOf(v), nil
+		}
+		return mismatch()
+	}
+
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func:
+			return reflect.Zero(t), nil
+		}
+		return mismatch()
+	}
+
+	if reflect.TypeOf(v) == t {
+		return reflect.ValueOf(v), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, ok := v.(bool); ok {
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+	case reflect.String:
+		if s, ok := v.(string); ok {
+			return reflect.ValueOf(s).Convert(t), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := v.(float64); ok {
+			result := reflect.New(t).Elem()
+			if float64(int64(n)) != n || result.OverflowInt(int64(n)) {
+				return reflect.Value{}, fmt.Errorf("cannot convert %s to Go type %s", interpreter.Stringify(n), t)
+			}
+			result.SetInt(int64(n))
+			return result, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := v.(float64); ok {
+			result := reflect.New(t).Elem()
+			if n < 0 || float64(uint64(n)) != n || result.OverflowUint(uint64(n)) {
+				return reflect.Value{}, fmt.Errorf("cannot convert %s to Go type %s", interpreter.Stringify(n), t)
+			}
+			result.SetUint(uint64(n))
+			return result, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := v.(float64); ok {
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := v.(*interpreter.Array)
+		if !ok {
+			break
+		}
+		leave, err := visit(array, visiting)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		defer leave()
+
+		var result reflect.Value
+		if t.Kind() == reflect.Slice {
+			result = reflect.MakeSlice(t, len(array.Elements), len(array.Elements))
+		} else if len(array.Elements) == t.Len() {
+			result = reflect.New(t).Elem()
+		} else {
+			return reflect.Value{}, fmt.Errorf("cannot convert list of length %d to Go type %s", len(array.Elements), t)
+		}
+
+		for i, element := range array.Elements {
+			converted, err := convertValue(element, t.Elem(), in, visiting)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			result.Index(i).Set(converted)
+		}
+		return result, nil
+	case reflect.Map:
+		m, ok := v.(*interpreter.Map)
+		if !ok {
+			break
+		}
+		leave, err := visit(m, visiting)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		defer leave()
+
+		result := reflect.MakeMapWithSize(t, len(m.Keys))
+		for _, key := range m.Keys {
+			convertedKey, err := convertValue(key, t.Key(), in, visiting)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %s: %w", interpreter.Stringify(key), err)
+			}
+			convertedValue, err := convertValue(m.Values[key], t.Elem(), in, visiting)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("value of key %s: %w", interpreter.Stringify(key), err)
+			}
+			result.SetMapIndex(convertedKey, convertedValue)
+		}
+		return result, nil
+	case reflect.Struct:
+		var lookup func(name string) (interpreter.Value, bool)
+		switch v := v.(type) {
+		case *interpreter.Map:
+			lookup = func(name string) (interpreter.Value, bool) {
+				return v.Get(name)
+			}
+		case *interpreter.Instance:
+			lookup = func(name string) (interpreter.Value, bool) {
+				value, exists := v.Fields[name]
+				return value, exists
+			}
+		default:
+			return mismatch()
+		}
+		leave, err := visit(v, visiting)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		defer leave()
+
+		result := reflect.New(t).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			name := fieldName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			value, exists := lookup(name)
+			if !exists {
+				continue
+			}
+			converted, err := convertValue(value, t.Field(i).Type, in, visiting)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", name, err)
+			}
+			result.Field(i).Set(converted)
+		}
+		return result, nil
+	case reflect.Pointer:
+		converted, err := convertValue(v, t.Elem(), in, visiting)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(t.Elem())
+		result.Elem().Set(converted)
+		return result, nil
+	case reflect.Func:
+		switch v.(type) {
+		case *interpreter.Function, *interpreter.Builtin:
+		default:
+			return mismatch()
+		}
+		if in == nil {
+			return reflect.Value{}, fmt.Errorf("cannot convert function to Go type %s outside of a call", t)
+		}
+		return callback(v, t, in), nil
+	}
+
+	return mismatch()
+}
+
+// callback 把 DreamLang 的函数 fn 包装为 Go 类型 t 的函数。包装得到的函数只能在 in 的当前调用中使用；
+// fn 抛出的错误不会作为 error 返回，而是继续在 DreamLang 中向外传递。
+func callback(fn interpreter.Value, t reflect.Type, in *interpreter.Interpreter) reflect.Value {
+	return reflect.MakeFunc(t, func(goArgs []reflect.Value) []reflect.Value {
+		args := make([]interpreter.Value, 0, len(goArgs))
+		for i, goArg := range goArgs {
+			if t.IsVariadic() && i == len(goArgs)-1 {
+				for j := 0; j < goArg.Len(); j++ {
+					args = append(args, toValueOrThrow(in, "callback argument", goArg.Index(j)))
+				}
+				continue
+			}
+			args = append(args, toValueOrThrow(in, "callback argument", goArg))
+		}
+
+		result := in.Call(fn, args...)
+
+		results := make([]reflect.Value, t.NumOut())
+		for i := range results {
+			out := t.Out(i)
+			if out == errorType {
+				results[i] = reflect.Zero(out)
+				continue
+			}
+
+			converted, err := convertValue(result, out, in, map[any]bool{})
+			if err != nil {
+				in.Throwf("result of callback: %v", err)
+			}
+			results[i] = converted
+		}
+
+		return results
+	})
+}
+
+func toValueOrThrow(in *interpreter.Interpreter, what string, v reflect.Value) interpreter.Value {
+	value, err := toValue(what, v)
+	if err != nil {
+		in.Throwf("%v", err)
+	}
+
+	return value
+}
+
+// wrapFunc 把 Go 函数 fn 包装为 DreamLang 的内置函数，调用时按 RegisterFunc 描述的规则转换实参和返回值。
+func wrapFunc(name string, fn reflect.Value) *interpreter.Builtin {
+	t := fn.Type()
+	first := 0
+	if t.NumIn() > 0 && t.In(0) == contextType {
+		first = 1
+	}
+
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+
+	return &interpreter.Builtin{Name: name, Fn: func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		fixed := t.NumIn() - first
+		if t.IsVariadic() {
+			fixed--
+		}
+		if len(args) < fixed || !t.IsVariadic() && len(args) > fixed {
+			in.Throwf("wrong number of arguments in call to %s: expected %d but got %d", name, fixed, len(args))
+		}
+
+		goArgs := make([]reflect.Value, 0, first+len(args))
+		if first == 1 {
+			goArgs = append(goArgs, reflect.ValueOf(in.Context()))
+		}
+
+		for i, arg := range args {
+			var paramType reflect.Type
+			if i < fixed {
+				paramType = t.In(first + i)
+			} else {
+				paramType = t.In(t.NumIn() - 1).Elem()
+			}
+
+			converted, err := convertValue(arg, paramType, in, map[any]bool{})
+			if err != nil {
+				in.Throwf("argument %d in call to %s: %v", i+1, name, err)
+			}
+			goArgs = append(goArgs, converted)
+		}
+
+		results := fn.Call(goArgs)
+		if returnsError {
+			if err := results[len(results)-1]; !err.IsNil() {
+				in.Throwf("%s", err.Interface().(error).Error())
+			}
+			results = results[:len(results)-1]
+		}
+
+		values := make([]interpreter.Value, len(results))
+		for i, result := range results {
+			values[i] = toValueOrThrow(in, "result of "+name, result)
+		}
+
+		switch len(values) {
+		case 0:
+			return nil
+		case 1:
+			return values[0]
+		default:
+			return &interpreter.Array{Elements: values}
+		}
+	}}
+}
+
+// typeOf 返回 Go 类型 t 在类型检查器中对应的类型。结构体和接口对应 any，指针 *T 对应 T | null。
+func typeOf(t reflect.Type) (checker.Type, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return checker.Bool, nil
+	case reflect.String:
+		return checker.String, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return checker.Number, nil
+	case reflect.Slice, reflect.Array:
+		element, err := typeOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &checker.ListType{Element: element}, nil
+	case reflect.Map:
+		key, err := typeOf(t.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := typeOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &checker.MapType{Key: key, Value: value}, nil
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct {
+			return checker.Any, nil
+		}
+		element, err := typeOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return checker.NewUnion(element, checker.Null), nil
+	case reflect.Func:
+		return signatureOf(t)
+	case reflect.Interface, reflect.Struct:
+		return checker.Any, nil
+	}
+
+	return nil, fmt.Errorf("Go type %s has no DreamLang equivalent", t)
+}
+
+// signatureOf 返回 Go 函数类型 t 在类型检查器中对应的函数类型。
+// 开头的 context.Context 参数和末尾的 error 返回值不出现在 DreamLang 的签名中。
+func signatureOf(t reflect.Type) (*checker.FunctionType, error) {
+	signature := &checker.FunctionType{Parameters: make([]checker.Type, 0, t.NumIn())}
+
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if i == 0 && in == contextType {
+			continue
+		}
+
+		param, err := typeOf(in)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			signature.Rest = param.(*checker.ListType)
+		} else {
+			signature.Parameters = append(signature.Parameters, param)
+		}
+	}
+
+	results := make([]checker.Type, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		out := t.Out(i)
+		if i == t.NumOut()-1 && out == errorType {
+			continue
+		}
+
+		result, err := typeOf(out)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	switch len(results) {
+	case 0:
+		signature.ReturnType = checker.Void
+	case 1:
+		signature.ReturnType = results[0]
+	default:
+		signature.ReturnType = &checker.TupleType{Members: results}
+	}
+
+	return signature, nil
+}
@@ -28,7 +28,7 @@ import (
 // 注意:
 //   - 如果类型不匹配，函数将触发 panic 并输出错误信息，格式为 "Expected %T but instead received %T inside ExpectType[T](r)"。
 func ExpectType[T any](r any) T {
-	expectedType := reflect.TypeOf((*T)(nil)).Elem()
+	expectedType := TypeOf[T]()
 	recievedType := reflect.TypeOf(r)
 
 	if expectedType == recievedType {
@@ -37,3 +37,14 @@ func ExpectType[T any](r any) T {
 
 	panic(fmt.Sprintf("Expected %T but instead recived %T inside ExpectType[T](r)\n", expectedType, recievedType))
 }
+
+// TypeOf 返回类型 T 的 reflect.Type。与 reflect.TypeOf 不同，它不需要 T 类型的值，
+// 因此 T 也可以是接口类型，例如 TypeOf[error]()。
+func TypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Implements 判断 t 是否实现了接口 I。
+func Implements[I any](t reflect.Type) bool {
+	return t.Implements(TypeOf[I]())
+}
@@ -0,0 +1,246 @@
+package interpreter
+
+import (
+	"reflect"
+
+	"dreamlang/ast"
+)
+
+// Channel 是通道的运行时表示，值在底层的 Go 通道中传递。
+//
+// closed、receivers 和 senders 由调度器的 mu 保护：receivers 和 senders 是阻塞在该通道上、
+// 分别等待接收和发送的 goroutine，用于死锁检测。
+type Channel struct {
+	ch        chan Value
+	closed    bool
+	receivers []*waiter
+	senders   []*waiter
+}
+
+// NewChannel 创建一个缓冲区容量为 capacity 的通道，capacity 为 0 时为无缓冲通道。
+func NewChannel(capacity int) *Channel {
+	return &Channel{ch: make(chan Value, capacity)}
+}
+
+// channelOp 是一次通道操作：send 为 true 时向 channel 发送 value，否则从 channel 接收。
+type channelOp struct {
+	channel *Channel
+	send    bool
+	value   Value
+}
+
+// ready 判断阻塞在 w 中的操作 op 能否继续进行：通道已关闭、缓冲区允许，或者有另一个 goroutine 在等待相反方向的操作。
+// 调用方持有调度器的 mu。
+func (op channelOp) ready(w *waiter) bool {
+	c := op.channel
+	if c.closed {
+		return true
+	}
+
+	if op.send {
+		return len(c.ch) < cap(c.ch) || hasOtherWaiter(c.receivers, w)
+	}
+
+	return len(c.ch) > 0 || hasOtherWaiter(c.senders, w)
+}
+
+func hasOtherWaiter(waiters []*waiter, w *waiter) bool {
+	for _, candidate := range waiters {
+		if candidate != w {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectCases 把 ops 转换为 reflect.Select 的分支，并在末尾留出 extra 个分支的容量。
+func selectCases(ops []channelOp, extra int) []reflect.SelectCase {
+	cases := make([]reflect.SelectCase, len(ops), len(ops)+extra)
+	for i, op := range ops {
+		if op.send {
+			value := op.value
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(op.channel.ch), Send: reflect.ValueOf(&value).Elem()}
+		} else {
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(op.channel.ch)}
+		}
+	}
+
+	return cases
+}
+
+func received(value reflect.Value, ok bool) Value {
+	if !ok {
+		return nil
+	}
+
+	return value.Interface()
+}
+
+// selectOps 进行 ops 中任意一个能够进行的通道操作。
+//
+// 参数:
+//   - ops: 候选的通道操作。多个操作同时可以进行时随机选择其中一个。
+//   - block: 为 false 时，没有操作可以立即进行就返回；为 true 时阻塞直到某个操作完成。
+//
+// 返回值:
+//   - chosen: 完成的操作在 ops 中的下标，没有操作完成时为 -1。
+//   - value: 接收到的值。
+//   - ok: 接收操作在通道已关闭时为 false，此时 value 为 null。
+//
+// 向已关闭的通道发送时抛出错误；阻塞期间程序被取消（包括发生死锁）时停止当前 goroutine。
+func (in *Interpreter) selectOps(ops []channelOp, block bool) (chosen int, value Value, ok bool) {
+	s := in.sched
+
+	for {
+		s.mu.Lock()
+		for _, op := range ops {
+			if op.send && op.channel.closed {
+				s.mu.Unlock()
+				in.throwf("send on closed channel")
+			}
+		}
+
+		cases := append(selectCases(ops, 2), reflect.SelectCase{Dir: reflect.SelectDefault})
+		chosen, recv, recvOK := reflect.Select(cases)
+		if chosen < len(ops) {
+			s.completed(ops[chosen])
+			s.mu.Unlock()
+			return chosen, received(recv, recvOK), recvOK
+		}
+
+		if !block {
+			s.mu.Unlock()
+			return -1, nil, false
+		}
+
+		w := s.park(ops)
+		s.mu.Unlock()
+
+		chosen, recv, recvOK, closed := in.wait(w)
+		if closed {
+			in.throwf("send on closed channel")
+		}
+		if chosen < len(ops) {
+			return chosen, received(recv, recvOK), recvOK
+		}
+	}
+}
+
+// wait 释放解释器锁，在 Go 通道上等待 w 中的某个操作完成、w 被唤醒或程序被取消。
+// chosen 为 len(w.ops) 时 w 被唤醒，调用方应重新尝试；closed 为 true 时等待中的通道被关闭，发送失败。
+func (in *Interpreter) wait(w *waiter) (chosen int, recv reflect.Value, recvOK bool, closed bool) {
+	s := in.sched
+	cases := append(selectCases(w.ops, 2),
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w.wake)},
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.ctx.Done())},
+	)
+
+	s.gil.Unlock()
+	func() {
+		defer func() {
+			// 向 Go 通道发送时通道被关闭会引发 panic。
+			if recover() != nil {
+				closed = true
+			}
+		}()
+		chosen, recv, recvOK = reflect.Select(cases)
+	}()
+
+	s.mu.Lock()
+	if !w.woken {
+		s.unpark(w)
+	}
+	if !closed && chosen < len(w.ops) {
+		s.completed(w.ops[chosen])
+	}
+	s.mu.Unlock()
+
+	s.gil.Lock()
+	in.checkCancelled()
+	return chosen, recv, recvOK, closed
+}
+
+// receive 从通道 c 接收一个值，通道已关闭时返回 null。
+func (in *Interpreter) receive(c *Channel) (Value, bool) {
+	_, value, ok := in.selectOps([]channelOp{{channel: c}}, true)
+	return value, ok
+}
+
+func (in *Interpreter) send(c *Channel, value Value) {
+	in.selectOps([]channelOp{{channel: c, send: true, value: value}}, true)
+}
+
+// closeChannel 实现内置函数 close(ch)。关闭通道会唤醒在它上面等待的全部 goroutine：
+// 接收方得到 null，发送方抛出错误。
+func closeChannel(in *Interpreter, args []Value) Value {
+	var arg Value
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	c := in.expectChannel(arg, "close")
+
+	s := in.sched
+	s.mu.Lock()
+	if c.closed {
+		s.mu.Unlock()
+		in.throwf("close of closed channel")
+	}
+
+	c.closed = true
+	close(c.ch)
+	s.wake(c.receivers)
+	s.wake(c.senders)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (in *Interpreter) expectChannel(v Value, what string) *Channel {
+	c, ok := v.(*Channel)
+	if !ok {
+		in.throwf("cannot %s non-channel %s", what, TypeName(v))
+	}
+
+	return c
+}
+
+func (in *Interpreter) evalChannelExpr(expr ast.ChannelExpr, env *Environment) Value {
+	capacity := 0
+	if expr.Capacity != nil {
+		number := in.expectNumber(in.evalExpr(expr.Capacity, env), "channel capacity")
+		capacity = int(number)
+		if float64(capacity) != number || capacity < 0 {
+			in.throwf("channel capacity must be a non-negative integer, not %s", formatNumber(number))
+		}
+	}
+
+	in.Allocate(capacity * valueSize)
+	return NewChannel(capacity)
+}
+
+// execSelect 执行 select 语句。与 Go 相同，全部分支的通道和要发送的值都先按顺序求值，然后再等待。
+func (in *Interpreter) execSelect(stmt ast.SelectStmt, env *Environment) *completion {
+	ops := make([]channelOp, len(stmt.Cases))
+	for i, selectCase := range stmt.Cases {
+		switch operation := selectCase.Operation.(type) {
+		case ast.ReceiveExpr:
+			ops[i] = channelOp{channel: in.expectChannel(in.evalExpr(operation.Channel, env), "receive from")}
+		case ast.SendExpr:
+			channel := in.expectChannel(in.evalExpr(operation.Channel, env), "send to")
+			ops[i] = channelOp{channel: channel, send: true, value: in.evalExpr(operation.Value, env)}
+		}
+	}
+
+	chosen, value, _ := in.selectOps(ops, stmt.Default == nil)
+	if chosen < 0 {
+		return in.execStatements(stmt.Default, NewEnvironment(env))
+	}
+
+	caseEnv := NewEnvironment(env)
+	if binding := stmt.Cases[chosen].Binding; binding != nil {
+		in.bindPattern(binding, value, caseEnv, false)
+	}
+
+	return in.execStatements(stmt.Cases[chosen].Body, caseEnv)
+}
@@ -0,0 +1,325 @@
+package interpreter
+
+import (
+	"sort"
+)
+
+// Set 是内置类 Set 的实例，Entries 的键按插入顺序保存不重复的元素，值都为 null。
+type Set struct {
+	Entries *Map
+}
+
+// HashMap 是内置类 Map 的实例，Entries 按插入顺序保存键值对。
+// 与字典 *Map 不同，它的键值对只能通过方法访问，因此 m.get 总是方法而不是键 "get" 的值。
+type HashMap struct {
+	Entries *Map
+}
+
+// Delete 删除键 key，返回键是否存在。
+func (m *Map) Delete(key Value) bool {
+	if _, exists := m.Values[key]; !exists {
+		return false
+	}
+
+	delete(m.Values, key)
+	for i, candidate := range m.Keys {
+		if candidate == key {
+			m.Keys = append(m.Keys[:i], m.Keys[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// newSet 实现内置类 Set 的构造函数：new Set(values?)。
+func newSet(in *Interpreter, args []Value) Value {
+	set := &Set{Entries: NewMap()}
+	if len(args) > 0 && args[0] != nil {
+		for _, value := range in.expectList(args[0], "Set values").Elements {
+			in.setEntry(set.Entries, value, nil)
+		}
+	}
+
+	return set
+}
+
+// newHashMap 实现内置类 Map 的构造函数：new Map(entries?)，entries 中的每一项为 [key, value]。
+func newHashMap(in *Interpreter, args []Value) Value {
+	m := &HashMap{Entries: NewMap()}
+	if len(args) > 0 && args[0] != nil {
+		for _, entry := range in.expectList(args[0], "Map entries").Elements {
+			pair, ok := entry.(*Array)
+			if !ok || len(pair.Elements) != 2 {
+				in.throwf("Map entry must be a [key, value] pair, not %s", Stringify(entry))
+			}
+			in.setEntry(m.Entries, pair.Elements[0], pair.Elements[1])
+		}
+	}
+
+	return m
+}
+
+func (in *Interpreter) expectList(v Value, what string) *Array {
+	list, ok := v.(*Array)
+	if !ok {
+		in.throwf("%s must be a list, not %s", what, TypeName(v))
+	}
+
+	return list
+}
+
+// method 创建绑定到某个值上的内置方法，name 为它在调用栈中的名称。
+func method(name string, fn func(in *Interpreter, args []Value) Value) *Builtin {
+	return &Builtin{Name: name, Fn: fn}
+}
+
+// argument 返回第 i 个实参，没有传入时为 null。
+func argument(args []Value, i int) Value {
+	if i < len(args) {
+		return args[i]
+	}
+
+	return nil
+}
+
+// memberOfList 返回列表 list 的内置成员 name，方法的签名见 checker.listMember。
+func (in *Interpreter) memberOfList(list *Array, name string) (Value, bool) {
+	switch name {
+	case "length":
+		return float64(len(list.Elements)), true
+	case "push":
+		return method("list.push", func(in *Interpreter, args []Value) Value {
+			in.Allocate(len(args) * valueSize)
+			list.Elements = append(list.Elements, args...)
+			return float64(len(list.Elements))
+		}), true
+	case "pop":
+		return method("list.pop", func(in *Interpreter, args []Value) Value {
+			if len(list.Elements) == 0 {
+				in.throwf("pop from empty list")
+			}
+			last := list.Elements[len(list.Elements)-1]
+			list.Elements = list.Elements[:len(list.Elements)-1]
+			return last
+		}), true
+	case "slice":
+		return method("list.slice", func(in *Interpreter, args []Value) Value {
+			start := in.checkBound(argument(args, 0), len(list.Elements))
+			end := len(list.Elements)
+			if argument(args, 1) != nil {
+				end = in.checkBound(args[1], len(list.Elements))
+			}
+			if start > end {
+				in.throwf("slice bounds out of range [%d:%d]", start, end)
+			}
+			return in.NewList(append([]Value{}, list.Elements[start:end]...))
+		}), true
+	case "map":
+		return method("list.map", func(in *Interpreter, args []Value) Value {
+			f := argument(args, 0)
+			results := make([]Value, 0, len(list.Elements))
+			for i, element := range list.Elements {
+				results = append(results, in.call(f, []Value{element, float64(i)}, nil))
+			}
+			return in.NewList(results)
+		}), true
+	case "filter":
+		return method("list.filter", func(in *Interpreter, args []Value) Value {
+			f := argument(args, 0)
+			results := []Value{}
+			for i, element := range list.Elements {
+				if Truthy(in.call(f, []Value{element, float64(i)}, nil)) {
+					results = append(results, element)
+				}
+			}
+			return in.NewList(results)
+		}), true
+	case "reduce":
+		return method("list.reduce", func(in *Interpreter, args []Value) Value {
+			f, accumulator := argument(args, 0), argument(args, 1)
+			for i, element := range list.Elements {
+				accumulator = in.call(f, []Value{accumulator, element, float64(i)}, nil)
+			}
+			return accumulator
+		}), true
+	case "sort":
+		return method("list.sort", func(in *Interpreter, args []Value) Value {
+			in.sortList(list, argument(args, 0))
+			return list
+		}), true
+	case "find":
+		return method("list.find", func(in *Interpreter, args []Value) Value {
+			f := argument(args, 0)
+			for i, element := range list.Elements {
+				if Truthy(in.call(f, []Value{element, float64(i)}, nil)) {
+					return element
+				}
+			}
+			return nil
+		}), true
+	case "includes":
+		return method("list.includes", func(in *Interpreter, args []Value) Value {
+			for _, element := range list.Elements {
+				if Equal(element, argument(args, 0)) {
+					return true
+				}
+			}
+			return false
+		}), true
+	case "reverse":
+		return method("list.reverse", func(in *Interpreter, args []Value) Value {
+			for i, j := 0, len(list.Elements)-1; i < j; i, j = i+1, j-1 {
+				list.Elements[i], list.Elements[j] = list.Elements[j], list.Elements[i]
+			}
+			return list
+		}), true
+	case "zip":
+		return method("list.zip", func(in *Interpreter, args []Value) Value {
+			other := in.expectList(argument(args, 0), "zip argument")
+			pairs := make([]Value, min(len(list.Elements), len(other.Elements)))
+			for i := range pairs {
+				pairs[i] = in.NewList([]Value{list.Elements[i], other.Elements[i]})
+			}
+			return in.NewList(pairs)
+		}), true
+	case "enumerate":
+		return method("list.enumerate", func(in *Interpreter, args []Value) Value {
+			pairs := make([]Value, len(list.Elements))
+			for i, element := range list.Elements {
+				pairs[i] = in.NewList([]Value{float64(i), element})
+			}
+			return in.NewList(pairs)
+		}), true
+	}
+
+	return nil, false
+}
+
+// checkBound 检查切片的边界 v，它必须是 [0, length] 中的整数。
+func (in *Interpreter) checkBound(v Value, length int) int {
+	number := in.expectNumber(v, "slice bound")
+	i := int(number)
+
+	if float64(i) != number || i < 0 || i > length {
+		in.throwf("slice bound %s out of range [0, %d]", formatNumber(number), length)
+	}
+
+	return i
+}
+
+// sortList 稳定地原地排序 list。compare 为 null 时元素必须全部是数字或全部是字符串，按自然顺序排序；
+// 否则 compare(a, b) 返回负数时 a 排在 b 之前。
+func (in *Interpreter) sortList(list *Array, compare Value) {
+	less := func(a, b Value) bool {
+		return in.expectNumber(in.call(compare, []Value{a, b}, nil), "sort comparator result") < 0
+	}
+
+	if compare == nil {
+		less = func(a, b Value) bool {
+			switch a := a.(type) {
+			case float64:
+				if b, ok := b.(float64); ok {
+					return a < b
+				}
+			case string:
+				if b, ok := b.(string); ok {
+					return a < b
+				}
+			}
+			in.throwf("cannot sort %s and %s without a comparator", TypeName(a), TypeName(b))
+			return false
+		}
+	}
+
+	sort.SliceStable(list.Elements, func(i, j int) bool {
+		return less(list.Elements[i], list.Elements[j])
+	})
+}
+
+// memberOfSet 返回 Set 的成员 name，方法的签名见 checker.SetClass。
+func (in *Interpreter) memberOfSet(set *Set, name string) (Value, bool) {
+	switch name {
+	case "size":
+		return float64(len(set.Entries.Keys)), true
+	case "add":
+		return method("Set.add", func(in *Interpreter, args []Value) Value {
+			in.setEntry(set.Entries, argument(args, 0), nil)
+			return nil
+		}), true
+	case "has":
+		return method("Set.has", func(in *Interpreter, args []Value) Value {
+			_, exists := set.Entries.Get(argument(args, 0))
+			return exists
+		}), true
+	case "delete":
+		return method("Set.delete", func(in *Interpreter, args []Value) Value {
+			return set.Entries.Delete(argument(args, 0))
+		}), true
+	case "clear":
+		return method("Set.clear", func(in *Interpreter, args []Value) Value {
+			set.Entries = NewMap()
+			return nil
+		}), true
+	case "values":
+		return method("Set.values", func(in *Interpreter, args []Value) Value {
+			return in.NewList(append([]Value{}, set.Entries.Keys...))
+		}), true
+	}
+
+	return nil, false
+}
+
+// memberOfHashMap 返回 Map 的成员 name，方法的签名见 checker.MapClass。
+func (in *Interpreter) memberOfHashMap(m *HashMap, name string) (Value, bool) {
+	switch name {
+	case "size":
+		return float64(len(m.Entries.Keys)), true
+	case "set":
+		return method("Map.set", func(in *Interpreter, args []Value) Value {
+			in.setEntry(m.Entries, argument(args, 0), argument(args, 1))
+			return nil
+		}), true
+	case "get":
+		return method("Map.get", func(in *Interpreter, args []Value) Value {
+			value, _ := m.Entries.Get(argument(args, 0))
+			return value
+		}), true
+	case "has":
+		return method("Map.has", func(in *Interpreter, args []Value) Value {
+			_, exists := m.Entries.Get(argument(args, 0))
+			return exists
+		}), true
+	case "delete":
+		return method("Map.delete", func(in *Interpreter, args []Value) Value {
+			return m.Entries.Delete(argument(args, 0))
+		}), true
+	case "clear":
+		return method("Map.clear", func(in *Interpreter, args []Value) Value {
+			m.Entries = NewMap()
+			return nil
+		}), true
+	case "keys":
+		return method("Map.keys", func(in *Interpreter, args []Value) Value {
+			return in.NewList(append([]Value{}, m.Entries.Keys...))
+		}), true
+	case "values":
+		return method("Map.values", func(in *Interpreter, args []Value) Value {
+			values := make([]Value, len(m.Entries.Keys))
+			for i, key := range m.Entries.Keys {
+				values[i] = m.Entries.Values[key]
+			}
+			return in.NewList(values)
+		}), true
+	case "entries":
+		return method("Map.entries", func(in *Interpreter, args []Value) Value {
+			entries := make([]Value, len(m.Entries.Keys))
+			for i, key := range m.Entries.Keys {
+				entries[i] = in.NewList([]Value{key, m.Entries.Values[key]})
+			}
+			return in.NewList(entries)
+		}), true
+	}
+
+	return nil, false
+}
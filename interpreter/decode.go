@@ -0,0 +1,314 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"dreamlang/ast"
+)
+
+// decodeScope 是解码时解析类型名的上下文：env 为类型注解所在的环境，bindings 为泛型类的类型参数绑定的类型。
+type decodeScope struct {
+	env      *Environment
+	bindings map[string]typeBinding
+}
+
+// typeBinding 是类型参数绑定的类型实参，以及写出该类型实参的上下文。
+type typeBinding struct {
+	t     ast.Type
+	scope decodeScope
+}
+
+// evalInitializer 计算变量声明的初始值。声明带有类型注解、且初始值是对 Decode 内置函数的调用时，
+// 返回值按声明的类型解码。
+func (in *Interpreter) evalInitializer(stmt ast.VarDeclarationStmt, env *Environment) Value {
+	call, isCall := stmt.AssignedValue.(ast.CallExpr)
+	if !isCall || stmt.ExplicitType == nil {
+		if stmt.AssignedValue == nil {
+			return nil
+		}
+		return in.evalExpr(stmt.AssignedValue, env)
+	}
+
+	callee := in.evalExpr(call.Method, env)
+	args, named := in.evalArguments(call, env)
+	value := in.call(callee, args, named)
+
+	if builtin, ok := callee.(*Builtin); ok && builtin.Decode {
+		return in.decode(value, stmt.ExplicitType, env)
+	}
+
+	return value
+}
+
+// decode 按类型 t 验证 value 的结构并返回解码后的值，不符合时抛出错误，错误信息中包含出错的位置，例如 $.users[0].name。
+//
+// 字典按类的字段解码为类的实例：缺少的字段取其初始值，没有初始值时字段的类型必须允许 null；多余的键被忽略；
+// 构造函数不会被调用。列表、元组、字典和联合类型的每个部分都会递归解码。
+// 类型别名按它代表的类型解码。接口按结构解码：字典必须包含接口的每个字段（类型允许 null 的字段可以缺少），
+// 字段的值按字段的类型解码，结果仍是字典；接口声明了方法时只有满足接口的实例可以通过验证。
+// 无法解析的类型名是错误。
+func (in *Interpreter) decode(value Value, t ast.Type, env *Environment) Value {
+	decoded, err := in.decodeValue(value, t, decodeScope{env: env}, "$")
+	if err != nil {
+		in.throwf("cannot decode value as %s: %s", typeString(t), err)
+	}
+
+	return decoded
+}
+
+func (in *Interpreter) decodeValue(value Value, t ast.Type, scope decodeScope, path string) (Value, error) {
+	mismatch := func() (Value, error) {
+		return nil, fmt.Errorf("%s: expected %s but got %s", path, typeString(t), TypeName(value))
+	}
+
+	switch t := t.(type) {
+	case nil:
+		return value, nil
+	case ast.SymbolType:
+		if binding, exists := scope.bindings[t.Value]; exists {
+			return in.decodeValue(value, binding.t, binding.scope, path)
+		}
+
+		var ok bool
+		switch t.Value {
+		case "any":
+			return value, nil
+		case "number":
+			_, ok = value.(float64)
+		case "string":
+			_, ok = value.(string)
+		case "bool", "boolean":
+			_, ok = value.(bool)
+		case "null", "void":
+			ok = value == nil
+		default:
+			if decl, exists := scope.env.lookupType(t.Value); exists {
+				return in.decodeNamedType(value, decl, nil, scope, path)
+			}
+			switch named, _ := scope.env.Lookup(t.Value); named := named.(type) {
+			case *Class:
+				return in.decodeInstance(value, named, nil, scope, path)
+			case *Enum:
+				for _, key := range named.Members.Keys {
+					if Equal(named.Members.Values[key], value) {
+						return value, nil
+					}
+				}
+				return nil, fmt.Errorf("%s: %s is not a member of %s", path, quote(value, map[Value]bool{}), named.Name)
+			}
+			return nil, fmt.Errorf("%s: unknown type %s", path, t.Value)
+		}
+		if !ok {
+			return mismatch()
+		}
+		return value, nil
+	case ast.GenericType:
+		if decl, exists := scope.env.lookupType(t.Name); exists {
+			return in.decodeNamedType(value, decl, t.Arguments, scope, path)
+		}
+		if class, ok := lookupClass(scope.env, t.Name); ok {
+			return in.decodeInstance(value, class, t.Arguments, scope, path)
+		}
+		return nil, fmt.Errorf("%s: unknown type %s", path, t.Name)
+	case ast.OptionalType:
+		if value == nil {
+			return nil, nil
+		}
+		return in.decodeValue(value, t.Underlying, scope, path)
+	case ast.UnionType:
+		var errs []string
+		for _, member := range t.Members {
+			decoded, err := in.decodeValue(value, member, scope, path)
+			if err == nil {
+				return decoded, nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return nil, fmt.Errorf("%s: no member of %s matches (%s)", path, typeString(t), strings.Join(errs, "; "))
+	case ast.ListType:
+		list, ok := value.(*Array)
+		if !ok {
+			return mismatch()
+		}
+		elements := make([]Value, len(list.Elements))
+		for i, element := range list.Elements {
+			decoded, err := in.decodeValue(element, t.Underlying, scope, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = decoded
+		}
+		return in.NewList(elements), nil
+	case ast.TupleType:
+		list, ok := value.(*Array)
+		if !ok || len(list.Elements) != len(t.Members) {
+			return mismatch()
+		}
+		elements := make([]Value, len(list.Elements))
+		for i, member := range t.Members {
+			decoded, err := in.decodeValue(list.Elements[i], member, scope, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = decoded
+		}
+		return in.NewList(elements), nil
+	case ast.MapType:
+		m, ok := value.(*Map)
+		if !ok {
+			return mismatch()
+		}
+		decoded := NewMap()
+		for _, key := range m.Keys {
+			keyPath := fmt.Sprintf("%s[%s]", path, quote(key, map[Value]bool{}))
+			decodedKey, err := in.decodeValue(key, t.Key, scope, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			decodedValue, err := in.decodeValue(m.Values[key], t.Value, scope, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			in.setEntry(decoded, decodedKey, decodedValue)
+		}
+		return decoded, nil
+	}
+
+	return nil, fmt.Errorf("%s: cannot decode into %s", path, typeString(t))
+}
+
+// decodeNamedType 按类型别名或接口 decl 解码 value，typeArgs 为泛型接口的类型实参。
+func (in *Interpreter) decodeNamedType(value Value, decl *typeDecl, typeArgs []ast.Type, scope decodeScope, path string) (Value, error) {
+	if decl.iface == nil {
+		return in.decodeValue(value, decl.alias, decodeScope{env: decl.env}, path)
+	}
+
+	iface := decl.iface
+	if in.implements(value, iface, decl.env) {
+		return value, nil
+	}
+	m, ok := value.(*Map)
+	if !ok || len(iface.Methods) > 0 {
+		return nil, fmt.Errorf("%s: expected %s but got %s", path, iface.Name, TypeName(value))
+	}
+
+	fieldScope := decodeScope{env: decl.env, bindings: make(map[string]typeBinding, len(iface.TypeParameters))}
+	for i, typeParam := range iface.TypeParameters {
+		var typeArg ast.Type = ast.SymbolType{Value: "any"}
+		if i < len(typeArgs) {
+			typeArg = typeArgs[i]
+		}
+		fieldScope.bindings[typeParam.Name] = typeBinding{t: typeArg, scope: scope}
+	}
+
+	decoded := NewMap()
+	for _, key := range m.Keys {
+		in.setEntry(decoded, key, m.Values[key])
+	}
+	for _, field := range iface.Fields {
+		fieldPath := path + "." + field.Name
+
+		raw, exists := m.Get(field.Name)
+		fieldValue, err := in.decodeValue(raw, field.Type, fieldScope, fieldPath)
+		if err != nil {
+			if !exists {
+				return nil, fmt.Errorf("%s: missing field", fieldPath)
+			}
+			return nil, err
+		}
+		in.setEntry(decoded, field.Name, fieldValue)
+	}
+
+	return decoded, nil
+}
+
+// decodeInstance 把字典 value 解码为类 class 的实例，typeArgs 为泛型类的类型实参。
+func (in *Interpreter) decodeInstance(value Value, class *Class, typeArgs []ast.Type, scope decodeScope, path string) (Value, error) {
+	if instance, ok := value.(*Instance); ok && instance.Class == class {
+		return instance, nil
+	}
+	if class.Construct != nil {
+		return nil, fmt.Errorf("%s: cannot decode into built-in class %s", path, class.Name)
+	}
+
+	m, ok := value.(*Map)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected %s but got %s", path, class.Name, TypeName(value))
+	}
+
+	fieldScope := decodeScope{env: class.Closure, bindings: make(map[string]typeBinding, len(class.TypeParameters))}
+	for i, name := range class.TypeParameters {
+		var typeArg ast.Type = ast.SymbolType{Value: "any"}
+		if i < len(typeArgs) {
+			typeArg = typeArgs[i]
+		}
+		fieldScope.bindings[name] = typeBinding{t: typeArg, scope: scope}
+	}
+
+	in.Allocate(len(class.Fields) * entrySize)
+	instance := &Instance{Class: class, Fields: make(map[string]Value, len(class.Fields))}
+	initEnv := NewEnvironment(class.Closure)
+	initEnv.Define("this", instance, true)
+
+	for _, field := range class.Fields {
+		fieldPath := path + "." + field.Name
+
+		raw, exists := m.Get(field.Name)
+		if !exists && field.Initializer != nil {
+			instance.Fields[field.Name] = in.evalExpr(field.Initializer, initEnv)
+			continue
+		}
+
+		decoded, err := in.decodeValue(raw, field.Type, fieldScope, fieldPath)
+		if err != nil {
+			if !exists {
+				return nil, fmt.Errorf("%s: missing field", fieldPath)
+			}
+			return nil, err
+		}
+		instance.Fields[field.Name] = decoded
+	}
+
+	return instance, nil
+}
+
+func lookupClass(env *Environment, name string) (*Class, bool) {
+	value, _ := env.Lookup(name)
+	class, ok := value.(*Class)
+	return class, ok
+}
+
+// typeString 返回类型语法 t 的源代码形式，用于错误信息。
+func typeString(t ast.Type) string {
+	join := func(types []ast.Type, sep string) string {
+		parts := make([]string, len(types))
+		for i, member := range types {
+			parts[i] = typeString(member)
+		}
+		return strings.Join(parts, sep)
+	}
+
+	switch t := t.(type) {
+	case ast.SymbolType:
+		return t.Value
+	case ast.GenericType:
+		return t.Name + "<" + join(t.Arguments, ", ") + ">"
+	case ast.ListType:
+		return "[]" + typeString(t.Underlying)
+	case ast.MapType:
+		return "map[" + typeString(t.Key) + "]" + typeString(t.Value)
+	case ast.TupleType:
+		return "(" + join(t.Members, ", ") + ")"
+	case ast.UnionType:
+		return join(t.Members, " | ")
+	case ast.OptionalType:
+		return typeString(t.Underlying) + "?"
+	case ast.ChannelType:
+		return "chan<" + typeString(t.Element) + ">"
+	case ast.FunctionType:
+		return "fn(" + join(t.Parameters, ", ") + ")"
+	}
+
+	return "any"
+}
@@ -0,0 +1,45 @@
+package interpreter
+
+type binding struct {
+	value    Value
+	constant bool
+}
+
+// Environment 是运行时的词法作用域，保存变量名到值的绑定。
+// 接口和类型别名位于单独的命名空间 types 中，只在 is 和 instanceof 检查时使用，没有时为 nil。
+type Environment struct {
+	parent *Environment
+	values map[string]*binding
+	types  map[string]*typeDecl
+}
+
+func NewEnvironment(parent *Environment) *Environment {
+	return &Environment{
+		parent: parent,
+		values: make(map[string]*binding),
+	}
+}
+
+// Define 在当前作用域中声明一个变量。同名的变量会被覆盖。
+func (e *Environment) Define(name string, value Value, constant bool) {
+	e.values[name] = &binding{value: value, constant: constant}
+}
+
+// Lookup 从当前作用域开始逐层向外查找变量。
+func (e *Environment) Lookup(name string) (Value, bool) {
+	if b := e.resolve(name); b != nil {
+		return b.value, true
+	}
+
+	return nil, false
+}
+
+func (e *Environment) resolve(name string) *binding {
+	for env := e; env != nil; env = env.parent {
+		if b, exists := env.values[name]; exists {
+			return b
+		}
+	}
+
+	return nil
+}
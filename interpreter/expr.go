@@ -0,0 +1,415 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+func (in *Interpreter) evalExpr(expr ast.Expr, env *Environment) Value {
+	switch expr := expr.(type) {
+	case ast.NumberExpr:
+		return expr.Value
+	case ast.StringExpr:
+		return expr.Value
+	case ast.BooleanExpr:
+		return expr.Value
+	case ast.NullExpr:
+		return nil
+	case ast.RegexExpr:
+		return in.evalRegexExpr(expr)
+	case ast.TemplateExpr:
+		var sb strings.Builder
+		for _, part := range expr.Parts {
+			sb.WriteString(Stringify(in.evalExpr(part, env)))
+		}
+		in.Allocate(sb.Len())
+		return sb.String()
+	case ast.SymbolExpr:
+		value, exists := env.Lookup(expr.Value)
+		if !exists {
+			in.throwf("undefined: %s", expr.Value)
+		}
+		return value
+	case ast.ArrayLiteral:
+		in.Allocate(len(expr.Contents) * valueSize)
+		elements := make([]Value, len(expr.Contents))
+		for i, element := range expr.Contents {
+			elements[i] = in.evalExpr(element, env)
+		}
+		return &Array{Elements: elements}
+	case ast.MapLiteral:
+		in.Allocate(len(expr.Entries) * entrySize)
+		m := NewMap()
+		for _, entry := range expr.Entries {
+			m.Set(in.evalExpr(entry.Key, env), in.evalExpr(entry.Value, env))
+		}
+		return m
+	case ast.PrefixExpr:
+		return in.evalPrefixExpr(expr, env)
+	case ast.BinaryExpr:
+		return in.evalBinaryExpr(expr, env)
+	case ast.TypeTestExpr:
+		return in.evalTypeTestExpr(expr, env)
+	case ast.AssignmentExpr:
+		return in.evalAssignmentExpr(expr, env)
+	case ast.RangeExpr:
+		lower, upper := in.evalRangeBounds(expr, env)
+		if upper >= lower {
+			// 在创建列表之前按元素个数记录分配，使超过内存限制的范围立即失败。
+			in.Allocate(int(min(upper-lower+1, math.MaxInt32)) * valueSize)
+		}
+		// 每个元素计为一步，使步数限制同样约束创建很大的范围。
+		elements := []Value{}
+		for i := lower; i <= upper; i++ {
+			in.tick()
+			elements = append(elements, i)
+		}
+		return &Array{Elements: elements}
+	case ast.MemberExpr:
+		return in.member(in.evalExpr(expr.Member, env), expr.Property)
+	case ast.ComputedExpr:
+		return in.index(in.evalExpr(expr.Member, env), in.evalExpr(expr.Property, env))
+	case ast.CallExpr:
+		callee := in.evalExpr(expr.Method, env)
+		args, named := in.evalArguments(expr, env)
+		return in.call(callee, args, named)
+	case ast.NewExpr:
+		class, ok := in.evalExpr(expr.Instantiation.Method, env).(*Class)
+		if !ok {
+			in.throwf("new expects a class")
+		}
+		args, named := in.evalArguments(expr.Instantiation, env)
+		return in.instantiate(class, args, named)
+	case ast.FunctionExpr:
+		return &Function{Name: "<anonymous>", Parameters: expr.Parameters, Body: expr.Body, Closure: env}
+	case ast.ChannelExpr:
+		return in.evalChannelExpr(expr, env)
+	case ast.ReceiveExpr:
+		value, _ := in.receive(in.expectChannel(in.evalExpr(expr.Channel, env), "receive from"))
+		return value
+	case ast.SendExpr:
+		channel := in.expectChannel(in.evalExpr(expr.Channel, env), "send to")
+		in.send(channel, in.evalExpr(expr.Value, env))
+		return nil
+	default:
+		panic(fmt.Sprintf("interpreter: unsupported expression %T", expr))
+	}
+}
+
+// evalArguments 按从左到右的顺序计算调用的实参，返回位置实参和命名实参。展开实参 ...xs 会被展开为多个位置实参。
+func (in *Interpreter) evalArguments(call ast.CallExpr, env *Environment) ([]Value, map[string]Value) {
+	args := make([]Value, 0, len(call.Arguments))
+	for _, arg := range call.Arguments {
+		spread, ok := arg.(ast.SpreadExpr)
+		if !ok {
+			args = append(args, in.evalExpr(arg, env))
+			continue
+		}
+
+		value := in.evalExpr(spread.Argument, env)
+		array, ok := value.(*Array)
+		if !ok {
+			in.throwf("cannot spread %s", TypeName(value))
+		}
+		args = append(args, array.Elements...)
+	}
+
+	var named map[string]Value
+	if len(call.NamedArguments) > 0 {
+		named = make(map[string]Value, len(call.NamedArguments))
+		for _, arg := range call.NamedArguments {
+			named[arg.Name] = in.evalExpr(arg.Value, env)
+		}
+	}
+
+	return args, named
+}
+
+func (in *Interpreter) expectNumber(v Value, what string) float64 {
+	number, ok := v.(float64)
+	if !ok {
+		in.throwf("%s must be a number, not %s", what, TypeName(v))
+	}
+
+	return number
+}
+
+func (in *Interpreter) expectString(v Value, what string) string {
+	s, ok := v.(string)
+	if !ok {
+		in.throwf("%s must be a string, not %s", what, TypeName(v))
+	}
+
+	return s
+}
+
+// evalRangeBounds 求值范围 lower..upper 的上下界。
+func (in *Interpreter) evalRangeBounds(expr ast.RangeExpr, env *Environment) (lower, upper float64) {
+	lower = in.expectNumber(in.evalExpr(expr.Lower, env), "range bound")
+	upper = in.expectNumber(in.evalExpr(expr.Upper, env), "range bound")
+	return lower, upper
+}
+
+func (in *Interpreter) evalPrefixExpr(expr ast.PrefixExpr, env *Environment) Value {
+	operand := in.evalExpr(expr.Right, env)
+
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolDash:
+		return -in.expectNumber(operand, "operand of unary -")
+	case lexer.TokenTypeSymbolNot:
+		return !Truthy(operand)
+	case lexer.TokenTypeKeywordTypeof:
+		return in.typeOf(operand)
+	default:
+		panic(fmt.Sprintf("interpreter: unsupported prefix operator %s", lexer.TokenKindString(expr.Operator.Kind)))
+	}
+}
+
+func (in *Interpreter) evalBinaryExpr(expr ast.BinaryExpr, env *Environment) Value {
+	left := in.evalExpr(expr.Left, env)
+
+	switch expr.Operator.Kind {
+	case lexer.TokenTypeSymbolAnd:
+		return Truthy(left) && Truthy(in.evalExpr(expr.Right, env))
+	case lexer.TokenTypeSymbolOr:
+		return Truthy(left) || Truthy(in.evalExpr(expr.Right, env))
+	}
+
+	return in.binary(expr.Operator.Kind, left, in.evalExpr(expr.Right, env))
+}
+
+// binary 计算二元运算 left op right。+ 的任意一侧为字符串时进行字符串拼接；
+// 比较运算可用于两个数字或两个字符串。
+func (in *Interpreter) binary(op lexer.TokenKind, left, right Value) Value {
+	operator := lexer.TokenKindString(op)
+
+	switch op {
+	case lexer.TokenTypeSymbolEqual:
+		return Equal(left, right)
+	case lexer.TokenTypeSymbolNotEqual:
+		return !Equal(left, right)
+	case lexer.TokenTypeSymbolPlus, lexer.TokenTypeSymbolPlusEqual:
+		_, leftString := left.(string)
+		_, rightString := right.(string)
+		if leftString || rightString {
+			result := Stringify(left) + Stringify(right)
+			in.Allocate(len(result))
+			return result
+		}
+	}
+
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch op {
+			case lexer.TokenTypeSymbolLT:
+				return l < r
+			case lexer.TokenTypeSymbolLTEQ:
+				return l <= r
+			case lexer.TokenTypeSymbolGT:
+				return l > r
+			case lexer.TokenTypeSymbolGTEQ:
+				return l >= r
+			}
+		}
+	}
+
+	l := in.expectNumber(left, "left operand of "+operator)
+	r := in.expectNumber(right, "right operand of "+operator)
+
+	switch op {
+	case lexer.TokenTypeSymbolPlus, lexer.TokenTypeSymbolPlusEqual:
+		return l + r
+	case lexer.TokenTypeSymbolDash, lexer.TokenTypeSymbolDashEqual:
+		return l - r
+	case lexer.TokenTypeSymbolStar, lexer.TokenTypeSymbolStarEqual:
+		return l * r
+	case lexer.TokenTypeSymbolSlash, lexer.TokenTypeSymbolSlashEqual:
+		return l / r
+	case lexer.TokenTypeSymbolPercent, lexer.TokenTypeSymbolPercentEqual:
+		return math.Mod(l, r)
+	case lexer.TokenTypeSymbolStarStar:
+		return math.Pow(l, r)
+	case lexer.TokenTypeSymbolLT:
+		return l < r
+	case lexer.TokenTypeSymbolLTEQ:
+		return l <= r
+	case lexer.TokenTypeSymbolGT:
+		return l > r
+	case lexer.TokenTypeSymbolGTEQ:
+		return l >= r
+	default:
+		panic(fmt.Sprintf("interpreter: unsupported binary operator %s", operator))
+	}
+}
+
+// evalAssignmentExpr 执行赋值，返回赋给目标的值。复合赋值 x op= y 按 x = x op y 计算，x 只求值一次。
+func (in *Interpreter) evalAssignmentExpr(expr ast.AssignmentExpr, env *Environment) Value {
+	compound := expr.Operator.Kind != lexer.TokenTypeSymbolAssignment
+
+	switch target := expr.Assigne.(type) {
+	case ast.SymbolExpr:
+		b := env.resolve(target.Value)
+		if b == nil {
+			in.throwf("undefined: %s", target.Value)
+		}
+		if b.constant {
+			in.throwf("cannot assign to constant %s", target.Value)
+		}
+
+		value := in.evalExpr(expr.AssignedValue, env)
+		if compound {
+			value = in.binary(expr.Operator.Kind, b.value, value)
+		}
+		b.value = value
+		return value
+	case ast.MemberExpr:
+		object := in.evalExpr(target.Member, env)
+		value := in.evalExpr(expr.AssignedValue, env)
+		if compound {
+			value = in.binary(expr.Operator.Kind, in.member(object, target.Property), value)
+		}
+		in.setMember(object, target.Property, value)
+		return value
+	case ast.ComputedExpr:
+		object := in.evalExpr(target.Member, env)
+		key := in.evalExpr(target.Property, env)
+		value := in.evalExpr(expr.AssignedValue, env)
+		if compound {
+			value = in.binary(expr.Operator.Kind, in.index(object, key), value)
+		}
+		in.setIndex(object, key, value)
+		return value
+	default:
+		in.throwf("invalid assignment target")
+		return nil
+	}
+}
+
+// member 返回 object.name 的值。实例的方法会绑定到该实例，列表、Set 和 Map 的内置方法会绑定到 object。
+func (in *Interpreter) member(object Value, name string) Value {
+	if object == nil {
+		in.throwf("cannot read property %s of null", name)
+	}
+
+	value, exists := in.lookupMember(object, name)
+	if !exists {
+		in.throwf("%s has no member %s", TypeName(object), name)
+	}
+
+	return value
+}
+
+// lookupMember 返回 object.name，object 没有该成员时 exists 为 false。字典的任意键都视为存在，不存在的键的值为 null。
+func (in *Interpreter) lookupMember(object Value, name string) (value Value, exists bool) {
+	switch object := object.(type) {
+	case *Instance:
+		if value, exists := object.Fields[name]; exists {
+			return value, true
+		}
+		if method, exists := object.Class.Methods[name]; exists {
+			return method.bind(object), true
+		}
+	case *ErrorValue:
+		switch name {
+		case "message":
+			return object.Message, true
+		case "value":
+			return object.Value, true
+		case "stack":
+			stack := make([]Value, len(object.Stack))
+			for i, frame := range object.Stack {
+				stack[i] = frame
+			}
+			return &Array{Elements: stack}, true
+		}
+	case *Enum:
+		return object.Members.Get(name)
+	case *Map:
+		value, _ := object.Get(name)
+		return value, true
+	case *Module:
+		value, exists := object.Members[name]
+		return value, exists
+	case *Array:
+		return in.memberOfList(object, name)
+	case *Set:
+		return in.memberOfSet(object, name)
+	case *HashMap:
+		return in.memberOfHashMap(object, name)
+	case *Regex:
+		return in.memberOfRegex(object, name)
+	case *TypeInfo:
+		return in.memberOfType(object, name)
+	}
+
+	return nil, false
+}
+
+func (in *Interpreter) setMember(object Value, name string, value Value) {
+	switch object := object.(type) {
+	case *Instance:
+		if _, exists := object.Fields[name]; exists {
+			object.Fields[name] = value
+			return
+		}
+	case *Map:
+		in.setEntry(object, name, value)
+		return
+	case nil:
+		in.throwf("cannot set property %s of null", name)
+	}
+
+	in.throwf("cannot assign to member %s of %s", name, TypeName(object))
+}
+
+// index 返回 object[key] 的值。列表和字符串的下标越界时抛出错误，字典中不存在的键返回 null。
+func (in *Interpreter) index(object Value, key Value) Value {
+	switch object := object.(type) {
+	case *Array:
+		return object.Elements[in.checkIndex(key, len(object.Elements))]
+	case string:
+		runes := []rune(object)
+		return string(runes[in.checkIndex(key, len(runes))])
+	case *Map:
+		value, _ := object.Get(key)
+		return value
+	}
+
+	in.throwf("cannot index %s", TypeName(object))
+	return nil
+}
+
+func (in *Interpreter) setIndex(object Value, key Value, value Value) {
+	switch object := object.(type) {
+	case *Array:
+		object.Elements[in.checkIndex(key, len(object.Elements))] = value
+	case *Map:
+		in.setEntry(object, key, value)
+	default:
+		in.throwf("cannot assign to index of %s", TypeName(object))
+	}
+}
+
+// setEntry 设置字典 m 中键 key 的值，新增的键计入内存分配。
+func (in *Interpreter) setEntry(m *Map, key Value, value Value) {
+	if _, exists := m.Get(key); !exists {
+		in.Allocate(entrySize)
+	}
+
+	m.Set(key, value)
+}
+
+func (in *Interpreter) checkIndex(key Value, length int) int {
+	number := in.expectNumber(key, "index")
+	i := int(number)
+
+	if float64(i) != number || i < 0 || i >= length {
+		in.throwf("index %s out of range [0, %d)", formatNumber(number), length)
+	}
+
+	return i
+}
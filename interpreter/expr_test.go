@@ -0,0 +1,35 @@
+package interpreter_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dreamlang/interpreter"
+	"dreamlang/parser"
+)
+
+func TestExponent(t *testing.T) {
+	result, err := interpreter.New().EvalContext(context.Background(), parser.Parse(`[2 ** 3 ** 2, -2 ** 2, 2 ** -1, 2 * 3 ** 2];`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := interpreter.Stringify(result), "[512, -4, 0.5, 18]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	_, err = interpreter.New().EvalContext(context.Background(), parser.Parse(`"a" ** 2;`))
+	if err == nil || !strings.Contains(err.Error(), "left operand of **") {
+		t.Fatalf("err = %v, want an error about the left operand of **", err)
+	}
+}
+
+func TestSubtractionPrecedence(t *testing.T) {
+	result, err := interpreter.New().EvalContext(context.Background(), parser.Parse(`let xs = [5]; [10 - 2 * 3, 10 - xs[0], 10 - 4 - 3];`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := interpreter.Stringify(result), "[4, 5, 3]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
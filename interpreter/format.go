@@ -0,0 +1,102 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sprintf 按 printf 风格的格式 format 格式化 args，格式动词与 Go 的 fmt 相同，支持标志、宽度和精度:
+//   - %v、%s: 值的字符串形式；%q: 加引号的字符串
+//   - %d、%b、%o、%x、%X、%c、%U: 整数（实参必须是整数）
+//   - %e、%E、%f、%F、%g、%G: 浮点数；%t: bool
+//   - %%: 百分号
+//
+// 实参的个数与格式中的动词不一致、或实参的类型不符合动词时抛出错误。
+func (in *Interpreter) Sprintf(format string, args []Value) string {
+	var sb strings.Builder
+	next := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sb.WriteByte(format[i])
+			continue
+		}
+
+		// 动词之前的标志、宽度和精度原样交给 fmt。
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			in.throwf("format: missing verb at end of %q", format)
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		if verb == '%' {
+			sb.WriteByte('%')
+			continue
+		}
+
+		if next >= len(args) {
+			in.throwf("format: missing argument for %%%c", verb)
+		}
+		sb.WriteString(fmt.Sprintf(format[start:i+1], in.formatArg(verb, args[next])))
+		next++
+	}
+
+	if next < len(args) {
+		in.throwf("format: %d extra arguments", len(args)-next)
+	}
+
+	return sb.String()
+}
+
+// formatArg 把实参 v 转换为 fmt 可以按动词 verb 格式化的 Go 的值。
+func (in *Interpreter) formatArg(verb rune, v Value) any {
+	switch verb {
+	case 'v', 's':
+		return Stringify(v)
+	case 'q':
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return Stringify(v)
+	case 'd', 'b', 'o', 'x', 'X', 'c', 'U':
+		if s, ok := v.(string); ok && (verb == 'x' || verb == 'X') {
+			return s
+		}
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+			in.throwf("format: %%%c expects an integer, not %s", verb, describe(v))
+		}
+		return int64(n)
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		n, ok := v.(float64)
+		if !ok {
+			in.throwf("format: %%%c expects a number, not %s", verb, describe(v))
+		}
+		return n
+	case 't':
+		b, ok := v.(bool)
+		if !ok {
+			in.throwf("format: %%t expects a bool, not %s", describe(v))
+		}
+		return b
+	}
+
+	in.throwf("format: unknown verb %%%c", verb)
+	return nil
+}
+
+// describe 描述值 v，用于错误信息：数字显示其值，其它值显示类型名。
+func describe(v Value) string {
+	if n, ok := v.(float64); ok {
+		return formatNumber(n)
+	}
+
+	return TypeName(v)
+}
@@ -0,0 +1,396 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"dreamlang/ast"
+)
+
+// mainFrame 是顶层代码在调用栈中的名称，goroutineFrame 是 spawn 创建的 goroutine 的调用栈的底部。
+const (
+	mainFrame      = "<main>"
+	goroutineFrame = "<goroutine>"
+)
+
+// Interpreter 是 DreamLang 的树遍历解释器。它直接遍历 parser.Parse 得到的抽象语法树执行程序。
+//
+// throw 语句和运行时错误都以 *ErrorValue 为值触发 Go 的 panic，
+// 由 try 语句或 Run 通过 recover 捕获；函数调用在退出（包括因 panic 退出）时弹出自己的栈帧。
+// return 语句则作为 exec 的返回值逐层向外传递。
+//
+// 每个 goroutine 使用自己的 Interpreter 保存调用栈，它们共享全局作用域、调度器 sched，
+// 以及宿主程序提供的模块和能力 host。
+type Interpreter struct {
+	globals *Environment
+	frames  []string
+	steps   int
+	sched   *scheduler
+	host    *host
+}
+
+// New 创建一个解释器，全局作用域中预先定义了内置的 Error、Set、Map、Regex、RegexMatch 和 Type 类，
+// 以及内置函数 close、print、println、printf 和 readLine。标准输出和标准输入默认为进程的 os.Stdout 和 os.Stdin。
+func New() *Interpreter {
+	in := &Interpreter{
+		globals: NewEnvironment(nil),
+		frames:  []string{mainFrame},
+		sched:   &scheduler{},
+		host: &host{
+			modules: make(map[string]*Module),
+			regexps: make(map[string]*Regex),
+			stdout:  os.Stdout,
+			stdin:   newInput(os.Stdin),
+		},
+	}
+
+	in.globals.Define("Error", &Class{Name: "Error", Construct: newError}, true)
+	in.globals.Define("Set", &Class{Name: "Set", Construct: newSet}, true)
+	in.globals.Define("Map", &Class{Name: "Map", Construct: newHashMap}, true)
+	in.globals.Define("Regex", &Class{Name: "Regex", Construct: newRegex}, true)
+	in.globals.Define("RegexMatch", regexMatchClass, true)
+	in.globals.Define("Type", typeClass, true)
+	in.globals.Define("close", &Builtin{Name: "close", Fn: closeChannel}, true)
+	in.globals.Define("print", &Builtin{Name: "print", Fn: printValues}, true)
+	in.globals.Define("println", &Builtin{Name: "println", Fn: printLine}, true)
+	in.globals.Define("printf", &Builtin{Name: "printf", Fn: printFormatted}, true)
+	in.globals.Define("readLine", &Builtin{Name: "readLine", Fn: readLine}, true)
+
+	return in
+}
+
+// Define 在全局作用域中定义一个常量，例如由宿主程序提供的内置函数。
+func (in *Interpreter) Define(name string, value Value) {
+	in.globals.Define(name, value, true)
+}
+
+// Lookup 返回全局作用域中名为 name 的值。
+func (in *Interpreter) Lookup(name string) (Value, bool) {
+	return in.globals.Lookup(name)
+}
+
+// Run 在全局作用域中执行整个程序，等价于以 context.Background() 调用 RunContext。
+func (in *Interpreter) Run(program ast.BlockStmt) error {
+	return in.RunContext(context.Background(), program)
+}
+
+// RunContext 在全局作用域中执行整个程序。与 Go 相同，顶层代码执行完毕时程序结束，
+// 仍在运行的 goroutine 会被取消；RunContext 在全部 goroutine 停止之后才返回。
+//
+// 参数:
+//   - ctx: 取消 ctx 会停止程序中的全部 goroutine。
+//   - program: parser.Parse 返回的顶层代码块。
+//
+// 返回值:
+//   - error: 程序正常结束时为 nil；存在未被捕获的错误时（包括 spawn 创建的 goroutine 中的错误）为对应的 *ErrorValue，
+//     其中包含抛出时的调用栈；发生死锁时为 ErrDeadlock；超过 SetLimits 设置的限制时为 *LimitError；
+//     ctx 被取消时为 context.Cause(ctx)。
+func (in *Interpreter) RunContext(ctx context.Context, program ast.BlockStmt) error {
+	_, err := in.EvalContext(ctx, program)
+	return err
+}
+
+// EvalContext 与 RunContext 相同，但程序的最后一条语句是表达式语句时返回该表达式的值，否则返回 null。
+// 同一个解释器可以依次执行多个程序，之前的程序声明的全局变量和函数在之后的程序中仍然可用。
+func (in *Interpreter) EvalContext(ctx context.Context, program ast.BlockStmt) (result Value, err error) {
+	err = in.run(ctx, func() {
+		body := program.Body
+		in.hoist(body, in.globals)
+
+		for i, stmt := range body {
+			if expr, ok := stmt.(ast.ExpressionStmt); ok && i == len(body)-1 {
+				in.tick()
+				result = in.evalExpr(expr.Expression, in.globals)
+			} else {
+				in.execStmt(stmt, in.globals)
+			}
+		}
+	})
+
+	return result, err
+}
+
+// CallContext 以位置实参 args 调用 callee 并返回其返回值，callee 通常是通过 Lookup 取得的全局函数。
+// 调用与 RunContext 一样作为一次完整的运行：调用中 spawn 的 goroutine 会在调用返回时被取消。
+//
+// args 没有经过类型检查，因此 callee 是源代码中定义的函数时，会先按它的声明检查实参的个数和类型（见 checkArguments）。
+func (in *Interpreter) CallContext(ctx context.Context, callee Value, args []Value) (result Value, err error) {
+	err = in.run(ctx, func() {
+		if function, ok := callee.(*Function); ok {
+			in.checkArguments(function, args)
+		}
+		result = in.call(callee, args, nil)
+	})
+
+	return result, err
+}
+
+// run 在当前 goroutine 中执行 body 作为一次运行，负责启动和停止调度器，并把未被捕获的错误转换为返回值。
+func (in *Interpreter) run(ctx context.Context, body func()) (err error) {
+	s := in.sched
+	s.begin(ctx)
+	s.gil.Lock()
+
+	defer func() {
+		r := recover()
+		cause := context.Cause(s.ctx)
+
+		s.cancel(nil)
+		s.gil.Unlock()
+		s.wg.Wait()
+
+		switch r := r.(type) {
+		case nil:
+			err = cause
+		case *ErrorValue:
+			err = r
+		case *fatal:
+			err = r.err
+		default:
+			panic(r)
+		}
+	}()
+
+	body()
+	return nil
+}
+
+// Call 供内置函数回调 DreamLang 中的函数，例如宿主程序注册的函数接收的回调参数。
+// 它只能在运行期间、由调用内置函数的 in 使用；被调用的函数抛出的错误会继续向外传递。
+func (in *Interpreter) Call(callee Value, args ...Value) Value {
+	return in.call(callee, args, nil)
+}
+
+// Member 供内置函数读取 object.name，与 DreamLang 中的成员访问相同，例如取得列表或 Regex 的方法。
+func (in *Interpreter) Member(object Value, name string) Value {
+	return in.member(object, name)
+}
+
+// Throwf 供内置函数抛出运行时错误，与 throw 语句抛出的错误一样可以被 try 语句捕获。
+func (in *Interpreter) Throwf(format string, args ...any) {
+	in.throwf(format, args...)
+}
+
+// ThrowError 供内置函数抛出信息为 message 的错误，catch 子句中可以通过 e.value 取得 value，例如错误的位置。
+func (in *Interpreter) ThrowError(message string, value Value) {
+	panic(&ErrorValue{Message: message, Value: value, Stack: in.stack()})
+}
+
+// Context 返回当前运行的 context。运行被取消（包括发生死锁）时它也会被取消，
+// 内置函数中耗时的操作应当在它被取消时尽快返回。
+func (in *Interpreter) Context() context.Context {
+	return in.sched.ctx
+}
+
+// Block 供内置函数执行可能长时间阻塞的操作，例如等待计时器、网络或标准输入。执行 fn 期间释放解释器锁，
+// 使其它 goroutine 可以继续执行，因此 fn 中不能访问 DreamLang 的值。fn 返回时程序已被取消的话，当前 goroutine 停止。
+//
+// 执行 fn 的 goroutine 不算作阻塞在通道上，死锁检测会认为它终将继续执行。
+func (in *Interpreter) Block(fn func()) {
+	s := in.sched
+	s.gil.Unlock()
+	func() {
+		defer s.gil.Lock()
+		fn()
+	}()
+
+	in.checkCancelled()
+}
+
+// stack 返回当前调用栈的快照，最内层的函数在前。
+func (in *Interpreter) stack() []string {
+	stack := make([]string, len(in.frames))
+	for i, frame := range in.frames {
+		stack[len(in.frames)-1-i] = frame
+	}
+
+	return stack
+}
+
+// throw 抛出值 v。v 不是 Error 时会被包装为 Error，调用栈记录为当前位置。
+func (in *Interpreter) throw(v Value) {
+	if err, ok := v.(*ErrorValue); ok {
+		panic(err)
+	}
+
+	panic(&ErrorValue{Message: Stringify(v), Value: v, Stack: in.stack()})
+}
+
+// throwf 抛出一个运行时错误，例如调用非函数的值或下标越界。
+// 与 throw 语句抛出的错误一样，运行时错误也可以被 try 语句捕获。
+func (in *Interpreter) throwf(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	panic(&ErrorValue{Message: message, Value: message, Stack: in.stack()})
+}
+
+// newError 实现内置类 Error 的构造函数：new Error(message)。
+func newError(in *Interpreter, args []Value) Value {
+	var value Value
+	if len(args) > 0 {
+		value = args[0]
+	}
+
+	return &ErrorValue{Message: Stringify(value), Value: value, Stack: in.stack()}
+}
+
+// call 以位置实参 args 和命名实参 named 调用 callee，返回函数的返回值；没有 return 语句的函数返回 null。
+func (in *Interpreter) call(callee Value, args []Value, named map[string]Value) Value {
+	switch callee := callee.(type) {
+	case *Function:
+		in.frames = append(in.frames, callee.Name)
+		defer func() { in.frames = in.frames[:len(in.frames)-1] }()
+
+		// 调用栈的底部是 <main> 或 <goroutine>，不计入深度。
+		if depth := in.sched.limits.maxDepth(); len(in.frames)-1 > depth {
+			in.exceed(ErrStackOverflow, "maximum call depth of %d exceeded", depth)
+		}
+
+		env := NewEnvironment(callee.Closure)
+		if callee.This != nil {
+			env.Define("this", callee.This, true)
+		}
+		in.bindParameters(callee, args, named, env)
+
+		if result := in.execStatements(callee.Body, env); result != nil {
+			return result.value
+		}
+		return nil
+	case *Builtin:
+		if len(named) > 0 {
+			in.throwf("%s does not accept named arguments", callee.Name)
+		}
+
+		in.frames = append(in.frames, callee.Name)
+		defer func() { in.frames = in.frames[:len(in.frames)-1] }()
+
+		return callee.Fn(in, args)
+	case *Class:
+		in.throwf("class %s must be instantiated with new", callee.Name)
+	default:
+		in.throwf("cannot call non-function %s", TypeName(callee))
+	}
+
+	return nil
+}
+
+// bindParameters 在函数的环境 env 中绑定参数。
+//
+// 参数依次取对应的位置实参，没有位置实参时取同名的命名实参；两者都没有时计算默认值，没有默认值则为 null。
+// 默认值在函数的环境中按参数顺序计算，因此可以引用前面的参数。剩余参数绑定多出的位置实参组成的列表。
+func (in *Interpreter) bindParameters(callee *Function, args []Value, named map[string]Value, env *Environment) {
+	used := 0
+
+	for i, param := range callee.Parameters {
+		if param.Rest {
+			rest := []Value{}
+			if i < len(args) {
+				in.Allocate((len(args) - i) * valueSize)
+				rest = append(rest, args[i:]...)
+			}
+			in.bindPattern(param.Pattern, &Array{Elements: rest}, env, false)
+			continue
+		}
+
+		var arg Value
+		provided := false
+
+		if i < len(args) {
+			arg, provided = args[i], true
+		} else if identifier, ok := param.Pattern.(ast.IdentifierPattern); ok {
+			if value, exists := named[identifier.Name]; exists {
+				arg, provided = value, true
+				used++
+			}
+		}
+
+		if !provided && param.Default != nil {
+			arg = in.evalExpr(param.Default, env)
+		}
+
+		in.bindPattern(param.Pattern, arg, env, false)
+	}
+
+	if used < len(named) {
+		in.throwf("unknown or duplicate named argument in call to %s", callee.Name)
+	}
+}
+
+// checkArguments 检查位置实参 args 是否符合函数 callee 的声明，不符合时抛出错误：
+// 实参不能多于参数（有剩余参数时除外），没有默认值的参数必须有实参，实参必须属于参数的类型。
+// 类型参数按其约束检查，没有约束时可以是任意值。
+func (in *Interpreter) checkArguments(callee *Function, args []Value) {
+	env := NewEnvironment(callee.Closure)
+	for _, typeParam := range callee.TypeParameters {
+		constraint := typeParam.Constraint
+		if constraint == nil {
+			constraint = ast.SymbolType{Value: "any"}
+		}
+		env.defineType(typeParam.Name, &typeDecl{alias: constraint, env: callee.Closure})
+	}
+
+	for i, param := range callee.Parameters {
+		if param.Rest {
+			if param.Type == nil || i >= len(args) {
+				return
+			}
+			if rest := (&Array{Elements: args[i:]}); !in.matchesType(rest, param.Type, env) {
+				in.throwf("rest arguments of %s must be %s", callee.Name, typeString(param.Type))
+			}
+			return
+		}
+
+		if i >= len(args) {
+			if param.Default == nil {
+				in.throwf("missing argument %s in call to %s", parameterName(param, i), callee.Name)
+			}
+			continue
+		}
+		if param.Type != nil && !in.matchesType(args[i], param.Type, env) {
+			in.throwf("argument %s of %s must be %s, not %s", parameterName(param, i), callee.Name, typeString(param.Type), TypeName(args[i]))
+		}
+	}
+
+	if len(args) > len(callee.Parameters) {
+		in.throwf("too many arguments in call to %s: got %d, want %d", callee.Name, len(args), len(callee.Parameters))
+	}
+}
+
+// parameterName 返回第 i 个参数 param 在错误信息中的名称：参数为标识符时为它的名称，否则为它的序号。
+func parameterName(param ast.Parameter, i int) string {
+	if identifier, ok := param.Pattern.(ast.IdentifierPattern); ok {
+		return identifier.Name
+	}
+
+	return fmt.Sprintf("%d", i+1)
+}
+
+// instantiate 创建类 class 的实例：先按声明顺序计算字段的初始值，再调用构造函数。
+func (in *Interpreter) instantiate(class *Class, args []Value, named map[string]Value) Value {
+	if class.Construct != nil {
+		if len(named) > 0 {
+			in.throwf("%s does not accept named arguments", class.Name)
+		}
+		return class.Construct(in, args)
+	}
+
+	in.Allocate(len(class.Fields) * entrySize)
+	instance := &Instance{Class: class, Fields: make(map[string]Value, len(class.Fields))}
+
+	env := NewEnvironment(class.Closure)
+	env.Define("this", instance, true)
+
+	for _, field := range class.Fields {
+		var value Value
+		if field.Initializer != nil {
+			value = in.evalExpr(field.Initializer, env)
+		}
+		instance.Fields[field.Name] = value
+	}
+
+	if class.Constructor != nil {
+		in.call(class.Constructor.bind(instance), args, named)
+	}
+
+	return instance
+}
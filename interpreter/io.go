@@ -0,0 +1,104 @@
+package interpreter
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+)
+
+// input 是程序的标准输入。readLine 在单独的 Go goroutine 中读取，mu 保证同一时刻只有一个读取。
+type input struct {
+	mu     sync.Mutex
+	reader *bufio.Reader
+}
+
+func newInput(r io.Reader) *input {
+	return &input{reader: bufio.NewReader(r)}
+}
+
+// SetOutput 设置 print、println 和 printf 的输出，例如宿主程序可以用 bytes.Buffer 捕获程序的输出。
+// 写入发生在持有解释器锁时，因此 w 不会被并发地写入。
+func (in *Interpreter) SetOutput(w io.Writer) {
+	in.host.stdout = w
+}
+
+// SetInput 设置 readLine 读取的输入。
+func (in *Interpreter) SetInput(r io.Reader) {
+	in.host.stdin = newInput(r)
+}
+
+// printValues 实现内置函数 print(...values)：输出各个值的字符串形式，以空格分隔，不换行。
+func printValues(in *Interpreter, args []Value) Value {
+	in.write("print", joinValues(args))
+	return nil
+}
+
+// printLine 实现内置函数 println(...values)：与 print 相同，但在最后输出换行符。
+func printLine(in *Interpreter, args []Value) Value {
+	in.write("println", joinValues(args)+"\n")
+	return nil
+}
+
+// printFormatted 实现内置函数 printf(format, ...args)：按 Sprintf 格式化并输出，不会自动换行。
+func printFormatted(in *Interpreter, args []Value) Value {
+	format := in.expectString(argument(args, 0), "printf format")
+	in.write("printf", in.Sprintf(format, args[1:]))
+	return nil
+}
+
+// write 把 s 写入标准输出，写入失败时抛出错误。
+func (in *Interpreter) write(name, s string) {
+	if _, err := io.WriteString(in.host.stdout, s); err != nil {
+		in.throwf("%s: %s", name, err)
+	}
+}
+
+func joinValues(values []Value) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = Stringify(v)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// inputLine 是一次读取的结果。
+type inputLine struct {
+	text string
+	err  error
+}
+
+// readLine 实现内置函数 readLine()：从标准输入读取一行，返回不包括行尾的 "\n" 或 "\r\n" 的内容，输入结束时返回 null。
+//
+// 等待输入时释放解释器锁，其它 goroutine 可以继续执行；运行被取消时 readLine 立即停止，正在读取的行会被丢弃。
+func readLine(in *Interpreter, args []Value) Value {
+	stdin := in.host.stdin
+	result := make(chan inputLine, 1)
+	go func() {
+		stdin.mu.Lock()
+		defer stdin.mu.Unlock()
+
+		text, err := stdin.reader.ReadString('\n')
+		result <- inputLine{text: text, err: err}
+	}()
+
+	ctx := in.Context()
+	var line inputLine
+	in.Block(func() {
+		select {
+		case line = <-result:
+		case <-ctx.Done():
+		}
+	})
+
+	switch {
+	case line.err == io.EOF && line.text == "":
+		return nil
+	case line.err != nil && line.err != io.EOF:
+		in.throwf("readLine: %s", line.err)
+	}
+
+	in.Allocate(len(line.text))
+	return strings.TrimSuffix(strings.TrimSuffix(line.text, "\n"), "\r")
+}
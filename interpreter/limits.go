@@ -0,0 +1,133 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrBudgetExceeded 在程序执行的语句数或分配的内存超过 Limits 中的限制时，作为 *LimitError 的 Err 返回。
+	ErrBudgetExceeded = errors.New("budget exceeded")
+	// ErrStackOverflow 在函数调用的嵌套深度超过 Limits 中的限制时，作为 *LimitError 的 Err 返回。
+	ErrStackOverflow = errors.New("stack overflow")
+)
+
+// DefaultMaxDepth 是 Limits.MaxDepth 为 0 时函数调用的最大嵌套深度。
+// 没有限制的递归会耗尽 Go 的栈并使宿主进程崩溃，因此深度总是有限制的。
+const DefaultMaxDepth = 10000
+
+// DefaultMaxMemory 是 Limits.MaxMemory 为 0 时最多分配的内存的估算值（字节）。
+// 没有限制时，一个很大的范围或不断增长的列表会耗尽宿主进程的内存，因此默认总是有限制的。
+const DefaultMaxMemory = 1 << 30
+
+// 估算内存分配时使用的大小（字节）：valueSize 为列表中的一个元素，entrySize 为字典或实例中的一个条目。
+const (
+	valueSize = 16
+	entrySize = 48
+)
+
+// Limits 限制一次运行（RunContext、EvalContext 或 CallContext）可以使用的资源，超过限制时运行以 *LimitError 结束。
+// 限制由程序的全部 goroutine 共同计算，每次运行开始时重新计数。
+//
+// 字段:
+//   - MaxSteps: 最多执行的语句数（包括每次循环），为 0 时不限制。
+//   - MaxMemory: 最多分配的内存的估算值（字节），为 0 时使用 DefaultMaxMemory，为负数时不限制。
+//     它累计列表、字典、字符串、实例和通道等值的分配，不会因为值被回收而减少，
+//     因此限制的是分配的总量而不是某一时刻占用的内存，长时间运行的程序可能需要设置更大的值或者不限制。
+//   - MaxDepth: 函数调用的最大嵌套深度，为 0 时使用 DefaultMaxDepth。每个 goroutine 分别计算。
+type Limits struct {
+	MaxSteps  int64
+	MaxMemory int64
+	MaxDepth  int
+}
+
+func (l Limits) maxDepth() int {
+	if l.MaxDepth > 0 {
+		return l.MaxDepth
+	}
+
+	return DefaultMaxDepth
+}
+
+func (l Limits) maxMemory() int64 {
+	if l.MaxMemory != 0 {
+		return l.MaxMemory
+	}
+
+	return DefaultMaxMemory
+}
+
+// LimitError 是超过 Limits 中的限制时运行返回的错误。
+//
+// Err 为 ErrBudgetExceeded 或 ErrStackOverflow，可以用 errors.Is 判断；Detail 说明超过的是哪一项限制；
+// Stack 为超过限制时的 DreamLang 调用栈，最内层的函数在前。
+type LimitError struct {
+	Err    error
+	Detail string
+	Stack  []string
+}
+
+// maxPrintedFrames 是 LimitError.Error 最多列出的栈帧数，栈溢出时调用栈可能非常长。
+const maxPrintedFrames = 20
+
+func (e *LimitError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error() + ": " + e.Detail)
+
+	omitted := max(len(e.Stack)-maxPrintedFrames, 0)
+	for i, frame := range e.Stack {
+		if omitted > 0 && i >= maxPrintedFrames/2 && i < maxPrintedFrames/2+omitted {
+			if i == maxPrintedFrames/2 {
+				fmt.Fprintf(&sb, "\n    ... %d more frames", omitted)
+			}
+			continue
+		}
+		sb.WriteString("\n    at " + frame)
+	}
+
+	return sb.String()
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// SetLimits 设置之后的运行使用的资源限制。
+func (in *Interpreter) SetLimits(limits Limits) {
+	in.sched.limits = limits
+}
+
+// Allocate 记录内置函数分配了大约 bytes 字节的内存，例如创建新的列表或字符串；超过 Limits.MaxMemory 时停止程序。
+func (in *Interpreter) Allocate(bytes int) {
+	s := in.sched
+	s.allocated += int64(bytes)
+
+	if limit := s.limits.maxMemory(); limit > 0 && s.allocated > limit {
+		in.exceed(ErrBudgetExceeded, "memory limit of %d bytes exceeded", limit)
+	}
+}
+
+// MemoryAvailable 返回在超过内存限制之前还可以分配的字节数的估算值，不限制内存时为 math.MaxInt64。
+// 内置函数读取长度未知的数据（例如网络响应）时应当用它限制读取的长度，而不是读取全部数据之后再调用 Allocate。
+func (in *Interpreter) MemoryAvailable() int64 {
+	s := in.sched
+	limit := s.limits.maxMemory()
+	if limit < 0 {
+		return math.MaxInt64
+	}
+
+	return max(limit-s.allocated, 0)
+}
+
+// NewList 创建由 elements 组成的列表，并把它计入内存分配，供内置函数返回新的列表。
+func (in *Interpreter) NewList(elements []Value) *Array {
+	in.Allocate(len(elements) * valueSize)
+	return &Array{Elements: elements}
+}
+
+// exceed 以超过限制的错误停止程序。与死锁一样，它不能被 try 语句捕获。
+func (in *Interpreter) exceed(err error, format string, args ...any) {
+	panic(&fatal{err: &LimitError{Err: err, Detail: fmt.Sprintf(format, args...), Stack: in.stack()}})
+}
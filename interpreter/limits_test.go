@@ -0,0 +1,93 @@
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"dreamlang/interpreter"
+	"dreamlang/parser"
+)
+
+func TestRangeLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits interpreter.Limits
+		source string
+		detail string
+	}{
+		{
+			name:   "foreach over a range counts steps",
+			limits: interpreter.Limits{MaxSteps: 1_000_000},
+			source: `foreach x in 0..100000000 { }`,
+			detail: "step limit of 1000000 exceeded",
+		},
+		{
+			name:   "creating a range counts steps",
+			limits: interpreter.Limits{MaxSteps: 1_000_000, MaxMemory: -1},
+			source: `let xs = 0..100000000;`,
+			detail: "step limit of 1000000 exceeded",
+		},
+		{
+			name:   "creating a range is limited by the default memory ceiling",
+			source: `let xs = 0..100000000;`,
+			detail: "memory limit of 1073741824 bytes exceeded",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			in := interpreter.New()
+			in.SetLimits(test.limits)
+			err := run(ctx, in, test.source)
+
+			var limitError *interpreter.LimitError
+			if !errors.As(err, &limitError) || !errors.Is(err, interpreter.ErrBudgetExceeded) || !strings.Contains(limitError.Detail, test.detail) {
+				t.Fatalf("err = %v, want %q", err, test.detail)
+			}
+		})
+	}
+}
+
+func TestForeachRange(t *testing.T) {
+	source := `
+		let values: []number = [];
+		foreach i, x in 3..6 {
+			if x != 5 {
+				values.push(i * 10 + x);
+			}
+		}
+		values;`
+
+	result, err := interpreter.New().EvalContext(context.Background(), parser.Parse(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := interpreter.Stringify(result); got != "[3, 14, 36]" {
+		t.Fatalf("got %s, want [3, 14, 36]", got)
+	}
+}
+
+func TestCyclicValues(t *testing.T) {
+	source := `
+		let a: []any = [1];
+		a.push(a);
+		let m: map[string]any = {"list": a};
+		m["self"] = m;
+		[` + "`${a}`, `${m}`" + `, typeof a];`
+
+	result, err := interpreter.New().EvalContext(context.Background(), parser.Parse(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `["[1, [...]]", "{\"list\": [1, [...]], \"self\": [...]}", []any]`
+	if got := interpreter.Stringify(result); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
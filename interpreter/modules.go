@@ -0,0 +1,130 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"dreamlang/ast"
+)
+
+// Module 是可以通过 import 语句导入的模块，例如标准库模块或宿主程序注册的模块。
+// 导入后模块作为常量绑定到 import 语句中的名称，通过 name.member 访问其成员。
+//
+// 字段:
+//   - Name: 模块名，即 import 语句中 from 之后的名称（省略 from 时与绑定的名称相同）。
+//   - Capability: 导入模块需要的能力，为空字符串时不需要任何能力。
+//   - Members: 成员名到成员值的映射。
+type Module struct {
+	Name       string
+	Capability string
+	Members    map[string]Value
+}
+
+// Capability 是宿主程序授予程序的一项能力，例如 fs.read:/data、net、env 或 time。
+//
+// 字段:
+//   - Name: 能力名。能力名按 . 分层，授予 fs 同时授予 fs.read 和 fs.write；授予 * 授予全部能力。
+//   - Scope: 能力的范围，为空字符串时不限制范围。访问的资源等于 Scope，或以 Scope 加 / 或 : 开头时属于该范围，
+//     例如 fs.read:/data 允许读取 /data/users.json，但不允许读取 /database。
+type Capability struct {
+	Name  string
+	Scope string
+}
+
+// ParseCapability 解析 name 或 name:scope 形式的能力。
+func ParseCapability(s string) (Capability, error) {
+	name, scope, _ := strings.Cut(strings.TrimSpace(s), ":")
+	if name == "" {
+		return Capability{}, fmt.Errorf("invalid capability %q", s)
+	}
+
+	return Capability{Name: name, Scope: scope}, nil
+}
+
+func (c Capability) String() string {
+	if c.Scope == "" {
+		return c.Name
+	}
+
+	return c.Name + ":" + c.Scope
+}
+
+// covers 判断能力 c 是否包含名为 name 的能力。
+func (c Capability) covers(name string) bool {
+	return c.Name == "*" || c.Name == name || strings.HasPrefix(name, c.Name+".")
+}
+
+// contains 判断资源 resource 是否在能力 c 的范围之内。
+func (c Capability) contains(resource string) bool {
+	if c.Scope == "" || resource == c.Scope {
+		return true
+	}
+
+	scope := strings.TrimRight(c.Scope, "/:")
+	return strings.HasPrefix(resource, scope+"/") || strings.HasPrefix(resource, scope+":")
+}
+
+// host 保存宿主程序提供的模块、授予的能力和标准输入输出，由解释器和它创建的全部 goroutine 共享。
+// regexps 按标志和正则表达式缓存正则表达式字面量编译的结果，由 gil 保护。
+type host struct {
+	modules      map[string]*Module
+	capabilities []Capability
+	regexps      map[string]*Regex
+	stdout       io.Writer
+	stdin        *input
+}
+
+// RegisterModule 注册模块 m，之后的程序可以通过 import 语句导入它。同名的模块会被覆盖。
+func (in *Interpreter) RegisterModule(m *Module) {
+	in.host.modules[m.Name] = m
+}
+
+// Grant 授予程序 capabilities 中的能力。解释器创建时没有任何能力。
+func (in *Interpreter) Grant(capabilities ...Capability) {
+	in.host.capabilities = append(in.host.capabilities, capabilities...)
+}
+
+// Allowed 判断程序是否可以使用能力 name 访问资源 resource。resource 为空字符串时不检查范围。
+func (in *Interpreter) Allowed(name, resource string) bool {
+	for _, granted := range in.host.capabilities {
+		if granted.covers(name) && (resource == "" || granted.contains(resource)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Require 供内置函数检查能力：程序不能使用能力 name 访问资源 resource 时抛出错误，错误可以被 try 语句捕获。
+func (in *Interpreter) Require(name, resource string) {
+	if !in.Allowed(name, resource) {
+		in.throwf("permission denied: %s", Capability{Name: name, Scope: resource})
+	}
+}
+
+// execImport 执行 import 语句，把模块绑定到 env 中。
+// 模块需要的能力只要授予了它或者它的任意一项子能力（例如模块需要 fs，授予了 fs.read:/data）就可以导入，
+// 具体的资源由模块的函数在访问时检查。
+func (in *Interpreter) execImport(stmt ast.ImportStmt, env *Environment) {
+	m, exists := in.host.modules[stmt.From]
+	if !exists {
+		in.throwf("module %s not found", stmt.From)
+	}
+
+	if m.Capability != "" && !in.grantsAny(m.Capability) {
+		in.throwf("permission denied: import %s requires capability %s", m.Name, m.Capability)
+	}
+
+	env.Define(stmt.Name, m, true)
+}
+
+func (in *Interpreter) grantsAny(name string) bool {
+	for _, granted := range in.host.capabilities {
+		if granted.covers(name) || strings.HasPrefix(granted.Name, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
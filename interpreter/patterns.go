@@ -0,0 +1,40 @@
+package interpreter
+
+import "dreamlang/ast"
+
+// bindPattern 在环境 env 中声明模式 pattern 绑定的全部变量。
+//
+// 列表的元素少于列表模式中 ...rest 之前的元素时抛出错误，...rest 绑定剩余元素组成的新列表（可以为空）；
+// 对象模式按成员名读取实例的字段或方法、字典中以该名称为键的值。
+func (in *Interpreter) bindPattern(pattern ast.Pattern, value Value, env *Environment, constant bool) {
+	switch pattern := pattern.(type) {
+	case ast.IdentifierPattern:
+		env.Define(pattern.Name, value, constant)
+	case ast.ArrayPattern:
+		array, ok := value.(*Array)
+		if !ok {
+			in.throwf("cannot destructure %s as a list", TypeName(value))
+		}
+
+		if len(array.Elements) < len(pattern.Elements) {
+			in.throwf("cannot destructure a list of %d element(s) into %d variable(s)", len(array.Elements), len(pattern.Elements))
+		}
+
+		for i, element := range pattern.Elements {
+			in.bindPattern(element, array.Elements[i], env, constant)
+		}
+
+		if pattern.Rest != nil {
+			rest := []Value{}
+			if len(pattern.Elements) < len(array.Elements) {
+				in.Allocate((len(array.Elements) - len(pattern.Elements)) * valueSize)
+				rest = append(rest, array.Elements[len(pattern.Elements):]...)
+			}
+			in.bindPattern(pattern.Rest, &Array{Elements: rest}, env, constant)
+		}
+	case ast.ObjectPattern:
+		for _, property := range pattern.Properties {
+			in.bindPattern(property.Value, in.member(value, property.Key), env, constant)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package interpreter_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dreamlang/interpreter"
+	"dreamlang/parser"
+)
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "list",
+			source: `let [a, b] = [1, 2]; [a, b];`,
+			want:   `[1, 2]`,
+		},
+		{
+			name:   "rest",
+			source: `let [a, ...rest] = [1, 2, 3]; [a, rest];`,
+			want:   `[1, [2, 3]]`,
+		},
+		{
+			name:   "empty rest",
+			source: `let xs: []number = [1]; let [a, ...rest] = xs; [a, rest];`,
+			want:   `[1, []]`,
+		},
+		{
+			name:   "object",
+			source: `class P { let x: number = 1; let y: number = 2; } let {x, y: alias} = new P(); [x, alias];`,
+			want:   `[1, 2]`,
+		},
+		{
+			name:   "map",
+			source: `let m: map[string]number = {"x": 1}; let {x} = m; x;`,
+			want:   `1`,
+		},
+		{
+			name:   "nested",
+			source: `let [{x}, [y, ...zs]] = [{"x": 1}, [2, 3]]; [x, y, zs];`,
+			want:   `[1, 2, [3]]`,
+		},
+		{
+			name:   "parameters",
+			source: `fn f([a, b]: []number, {x}: map[string]number): number { return a + b + x; } f([1, 2], {"x": 3});`,
+			want:   `6`,
+		},
+		{
+			name:   "foreach",
+			source: `let out: []number = []; foreach i, [a, b] in [[1, 2], [3, 4]] { out.push(i + a * b); } out;`,
+			want:   `[2, 13]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := interpreter.New().EvalContext(context.Background(), parser.Parse(test.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := interpreter.Stringify(result); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{
+			name:   "short list",
+			source: `let xs: []number = [1]; let [a, b] = xs;`,
+			err:    "cannot destructure a list of 1 element(s) into 2 variable(s)",
+		},
+		{
+			name:   "short list with rest",
+			source: `let xs: []number = [1]; let [a, b, ...rest] = xs;`,
+			err:    "cannot destructure a list of 1 element(s) into 2 variable(s)",
+		},
+		{
+			name:   "short list in foreach",
+			source: `let xss: [][]number = [[1, 2], [3]]; foreach [a, b] in xss { }`,
+			err:    "cannot destructure a list of 1 element(s) into 2 variable(s)",
+		},
+		{
+			name:   "not a list",
+			source: `let x: any = 1; let [a] = x;`,
+			err:    "cannot destructure number as a list",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := interpreter.New().EvalContext(context.Background(), parser.Parse(test.source))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("err = %v, want %q", err, test.err)
+			}
+		})
+	}
+}
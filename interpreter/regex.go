@@ -0,0 +1,205 @@
+package interpreter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+// Regex 是内置类 Regex 的实例，即编译后的正则表达式。它是不可变的，同一个正则表达式字面量每次求值得到同一个 Regex。
+//
+// 字段:
+//   - Source: 正则表达式（RE2 语法），不包括标志。
+//   - Flags: 标志，见 lexer.RegexFlags。
+//   - Compiled: 以内联标志 (?flags) 开头编译的 Source。
+type Regex struct {
+	Source   string
+	Flags    string
+	Compiled *regexp.Regexp
+}
+
+// regexMatchClass 是内置类 RegexMatch，它的实例由 Regex 的 find 和 findAll 等方法创建，字段的类型见 checker.RegexMatchClass。
+var regexMatchClass = &Class{
+	Name:   "RegexMatch",
+	Fields: []Field{{Name: "text"}, {Name: "index"}, {Name: "groups"}, {Name: "named"}},
+	Construct: func(in *Interpreter, args []Value) Value {
+		in.throwf("RegexMatch cannot be constructed directly")
+		return nil
+	},
+}
+
+// newRegex 实现内置类 Regex 的构造函数：new Regex(pattern, flags?)。
+func newRegex(in *Interpreter, args []Value) Value {
+	pattern, ok := argument(args, 0).(string)
+	if !ok {
+		in.throwf("Regex pattern must be a string, not %s", TypeName(argument(args, 0)))
+	}
+
+	flags, ok := argument(args, 1).(string)
+	if !ok && argument(args, 1) != nil {
+		in.throwf("Regex flags must be a string, not %s", TypeName(args[1]))
+	}
+
+	return in.CompileRegex(pattern, flags)
+}
+
+// CompileRegex 以标志 flags 编译正则表达式 pattern。pattern 不合法时抛出错误，
+// 错误的 value 为 {message, column}，column 为 pattern 中出错的位置（以字符计，从 1 开始）。
+func (in *Interpreter) CompileRegex(pattern, flags string) *Regex {
+	for i, flag := range flags {
+		if !strings.ContainsRune(lexer.RegexFlags, flag) || strings.ContainsRune(flags[:i], flag) {
+			in.throwf("invalid regular expression flag %q", flag)
+		}
+	}
+
+	source := pattern
+	if flags != "" {
+		source = "(?" + flags + ")" + pattern
+	}
+
+	compiled, err := regexp.Compile(source)
+	if err != nil {
+		message := lexer.RegexErrorMessage(err)
+		column := 1
+		if offset, _, ok := lexer.RegexErrorPosition(pattern, err); ok {
+			column = utf8.RuneCountInString(pattern[:offset]) + 1
+		}
+
+		details := NewMap()
+		details.Set("message", message)
+		details.Set("column", float64(column))
+		in.ThrowError(fmt.Sprintf("invalid regular expression at column %d: %s", column, message), details)
+	}
+
+	return &Regex{Source: pattern, Flags: flags, Compiled: compiled}
+}
+
+// evalRegexExpr 求值正则表达式字面量。字面量在词法分析时已经验证过，编译的结果按字面量缓存。
+func (in *Interpreter) evalRegexExpr(expr ast.RegexExpr) *Regex {
+	key := expr.Flags + "/" + expr.Pattern
+	if re, exists := in.host.regexps[key]; exists {
+		return re
+	}
+
+	re := in.CompileRegex(expr.Pattern, expr.Flags)
+	in.host.regexps[key] = re
+	return re
+}
+
+// newRegexMatch 创建 s 中位置为 loc（regexp 的 FindStringSubmatchIndex 的结果）的匹配。
+func (in *Interpreter) newRegexMatch(re *Regex, s string, loc []int) *Instance {
+	names := re.Compiled.SubexpNames()
+	in.Allocate(loc[1] - loc[0] + (len(names)+len(regexMatchClass.Fields))*valueSize)
+
+	groups := make([]Value, len(names)-1)
+	named := NewMap()
+	for i := 1; i < len(names); i++ {
+		var group Value
+		if loc[2*i] >= 0 {
+			group = s[loc[2*i]:loc[2*i+1]]
+		}
+		groups[i-1] = group
+		if names[i] != "" {
+			in.setEntry(named, names[i], group)
+		}
+	}
+
+	return &Instance{Class: regexMatchClass, Fields: map[string]Value{
+		"text":   s[loc[0]:loc[1]],
+		"index":  float64(utf8.RuneCountInString(s[:loc[0]])),
+		"groups": &Array{Elements: groups},
+		"named":  named,
+	}}
+}
+
+// memberOfRegex 返回 Regex 的成员 name，方法的签名见 checker.RegexClass。
+func (in *Interpreter) memberOfRegex(re *Regex, name string) (Value, bool) {
+	switch name {
+	case "source":
+		return re.Source, true
+	case "flags":
+		return re.Flags, true
+	case "test", "match":
+		return method("Regex."+name, func(in *Interpreter, args []Value) Value {
+			return re.Compiled.MatchString(in.expectString(argument(args, 0), name+" argument"))
+		}), true
+	case "find":
+		return method("Regex.find", func(in *Interpreter, args []Value) Value {
+			s := in.expectString(argument(args, 0), "find argument")
+			loc := re.Compiled.FindStringSubmatchIndex(s)
+			if loc == nil {
+				return nil
+			}
+			return in.newRegexMatch(re, s, loc)
+		}), true
+	case "findAll":
+		return method("Regex.findAll", func(in *Interpreter, args []Value) Value {
+			s := in.expectString(argument(args, 0), "findAll argument")
+			matches := []Value{}
+			for _, loc := range re.Compiled.FindAllStringSubmatchIndex(s, in.regexLimit(argument(args, 1))) {
+				matches = append(matches, in.newRegexMatch(re, s, loc))
+			}
+			return in.NewList(matches)
+		}), true
+	case "replace":
+		return method("Regex.replace", func(in *Interpreter, args []Value) Value {
+			s := in.expectString(argument(args, 0), "replace argument")
+			var result string
+			if replacement, ok := argument(args, 1).(string); ok {
+				result = re.Compiled.ReplaceAllString(s, replacement)
+			} else {
+				result = in.replaceRegexFunc(re, s, argument(args, 1))
+			}
+			in.Allocate(len(result))
+			return result
+		}), true
+	case "split":
+		return method("Regex.split", func(in *Interpreter, args []Value) Value {
+			s := in.expectString(argument(args, 0), "split argument")
+			parts := re.Compiled.Split(s, in.regexLimit(argument(args, 1)))
+			elements := make([]Value, len(parts))
+			for i, part := range parts {
+				elements[i] = part
+			}
+			return in.NewList(elements)
+		}), true
+	}
+
+	return nil, false
+}
+
+// replaceRegexFunc 把 s 中 re 的每个匹配替换为以该匹配调用 f 的返回值。
+func (in *Interpreter) replaceRegexFunc(re *Regex, s string, f Value) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range re.Compiled.FindAllStringSubmatchIndex(s, -1) {
+		replacement, ok := in.call(f, []Value{in.newRegexMatch(re, s, loc)}, nil).(string)
+		if !ok {
+			in.throwf("replace callback must return a string")
+		}
+		sb.WriteString(s[last:loc[0]])
+		sb.WriteString(replacement)
+		last = loc[1]
+	}
+	sb.WriteString(s[last:])
+
+	return sb.String()
+}
+
+// regexLimit 返回 findAll 和 split 的参数 n，省略时为 -1，表示不限制。
+func (in *Interpreter) regexLimit(n Value) int {
+	if n == nil {
+		return -1
+	}
+
+	limit := in.expectNumber(n, "n")
+	if limit != float64(int(limit)) {
+		in.throwf("n must be an integer, not %s", formatNumber(limit))
+	}
+
+	return int(limit)
+}
@@ -0,0 +1,219 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrDeadlock 在程序的全部 goroutine 都阻塞在通道操作上、且没有任何一个能够继续执行时由 Run 返回。
+var ErrDeadlock = errors.New("all goroutines are asleep - deadlock!")
+
+// yieldInterval 是 goroutine 每执行多少条语句让出一次解释器锁，并检查程序是否已被取消。
+const yieldInterval = 1024
+
+// fatal 是导致整个程序停止的错误，例如死锁、取消或其它 goroutine 中未被捕获的错误。
+// 它与 *ErrorValue 一样以 panic 的形式传递，但不能被 try 语句捕获，finally 代码块也不会执行。
+type fatal struct {
+	err error
+}
+
+// waiter 表示一个阻塞在通道操作上的 goroutine。
+//
+// 其它 goroutine 唤醒它时会把 woken 置为 true、代它从调度器的计数中移除，并向 wake 发送一个令牌，
+// 使它即使还没有开始在 Go 通道上等待，也一定会从 select 中返回并重新尝试。
+type waiter struct {
+	ops   []channelOp
+	wake  chan struct{}
+	woken bool
+}
+
+// scheduler 协调一次运行中的全部 goroutine。
+//
+// 解释器锁 gil 保证同一时刻只有一个 goroutine 在执行 DreamLang 代码，因此环境、列表和字典不需要各自加锁；
+// goroutine 阻塞在通道上、或每执行 yieldInterval 条语句时会释放 gil。
+//
+// mu 保护 goroutine 的计数和每个通道上等待的 goroutine。通道操作总是先在持有 mu 时进行非阻塞的尝试，
+// 失败后才登记为阻塞（parked）并在 Go 通道上等待。完成一次通道操作的 goroutine 会唤醒该通道上等待另一方向操作的
+// 全部 goroutine（多余的唤醒只会让它们重新尝试），因此登记为阻塞的 goroutine 要么确实在等待，要么与另一个阻塞的
+// goroutine 的操作互相匹配。当全部存活的 goroutine 都已阻塞、且没有任何一对操作能够匹配时，程序发生了死锁，
+// 这一判断不依赖于超时，结果是确定的。
+type scheduler struct {
+	gil    sync.Mutex
+	mu     sync.Mutex
+	live   int
+	parked map[*waiter]struct{}
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	// limits 为资源限制，steps 和 allocated 为本次运行中全部 goroutine 已执行的语句数和已分配的内存，由 gil 保护。
+	limits    Limits
+	steps     int64
+	allocated int64
+}
+
+// begin 为一次新的运行初始化调度器，此时只有执行顶层代码的 goroutine。
+func (s *scheduler) begin(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancelCause(ctx)
+	s.live = 1
+	s.parked = make(map[*waiter]struct{})
+	s.steps = 0
+	s.allocated = 0
+}
+
+// yield 暂时让出解释器锁，使其它 goroutine 有机会执行。
+func (s *scheduler) yield() {
+	s.gil.Unlock()
+	runtime.Gosched()
+	s.gil.Lock()
+}
+
+// park 把等待 ops 的当前 goroutine 登记为阻塞，并检查是否因此发生了死锁。调用方持有 mu。
+func (s *scheduler) park(ops []channelOp) *waiter {
+	w := &waiter{ops: ops, wake: make(chan struct{}, 1)}
+	s.parked[w] = struct{}{}
+
+	for _, op := range ops {
+		if op.send {
+			op.channel.senders = append(op.channel.senders, w)
+		} else {
+			op.channel.receivers = append(op.channel.receivers, w)
+		}
+	}
+
+	s.checkDeadlock()
+	return w
+}
+
+// unpark 取消 w 的阻塞登记。调用方持有 mu。
+func (s *scheduler) unpark(w *waiter) {
+	delete(s.parked, w)
+
+	for _, op := range w.ops {
+		op.channel.senders = removeWaiter(op.channel.senders, w)
+		op.channel.receivers = removeWaiter(op.channel.receivers, w)
+	}
+}
+
+// wake 唤醒 waiters 中的全部 goroutine。调用方持有 mu。
+func (s *scheduler) wake(waiters []*waiter) {
+	for _, w := range append([]*waiter(nil), waiters...) {
+		if w.woken {
+			continue
+		}
+
+		w.woken = true
+		s.unpark(w)
+		w.wake <- struct{}{}
+	}
+}
+
+// completed 在通道操作 op 完成后调用，唤醒在同一通道上等待另一方向操作的 goroutine。调用方持有 mu。
+func (s *scheduler) completed(op channelOp) {
+	if op.send {
+		s.wake(op.channel.receivers)
+	} else {
+		s.wake(op.channel.senders)
+	}
+}
+
+// checkDeadlock 在 goroutine 阻塞或退出之后调用，发生死锁时以 ErrDeadlock 结束程序。调用方持有 mu。
+func (s *scheduler) checkDeadlock() {
+	if s.live == 0 || len(s.parked) < s.live {
+		return
+	}
+
+	for w := range s.parked {
+		for _, op := range w.ops {
+			if op.ready(w) {
+				return
+			}
+		}
+	}
+
+	s.cancel(ErrDeadlock)
+}
+
+func removeWaiter(waiters []*waiter, w *waiter) []*waiter {
+	kept := waiters[:0]
+	for _, candidate := range waiters {
+		if candidate != w {
+			kept = append(kept, candidate)
+		}
+	}
+
+	return kept
+}
+
+// checkCancelled 在程序已被取消（包括发生死锁和其它 goroutine 出错）时停止当前 goroutine。
+func (in *Interpreter) checkCancelled() {
+	if in.sched.ctx.Err() != nil {
+		panic(&fatal{err: context.Cause(in.sched.ctx)})
+	}
+}
+
+// tick 在每条语句执行之前和每次循环时调用，检查语句数的限制，并定期让出解释器锁和检查取消。
+func (in *Interpreter) tick() {
+	s := in.sched
+	s.steps++
+	if s.limits.MaxSteps > 0 && s.steps > s.limits.MaxSteps {
+		in.exceed(ErrBudgetExceeded, "step limit of %d exceeded", s.limits.MaxSteps)
+	}
+
+	in.steps++
+	if in.steps%yieldInterval == 0 {
+		s.yield()
+		in.checkCancelled()
+	}
+}
+
+// spawn 在新的 goroutine 中以 args 和 named 调用 callee。
+// 新的 goroutine 有自己的调用栈，与当前 goroutine 共享全局作用域和调度器。
+func (in *Interpreter) spawn(callee Value, args []Value, named map[string]Value) {
+	switch callee.(type) {
+	case *Function, *Builtin:
+	default:
+		in.throwf("cannot spawn non-function %s", TypeName(callee))
+	}
+
+	s := in.sched
+	s.mu.Lock()
+	s.live++
+	s.mu.Unlock()
+	s.wg.Add(1)
+
+	child := &Interpreter{globals: in.globals, frames: []string{goroutineFrame}, sched: s, host: in.host}
+	go child.runGoroutine(callee, args, named)
+}
+
+// runGoroutine 是新 goroutine 的入口。goroutine 中未被捕获的错误和超过限制的错误会结束整个程序，并作为 Run 的返回值。
+func (in *Interpreter) runGoroutine(callee Value, args []Value, named map[string]Value) {
+	s := in.sched
+	s.gil.Lock()
+
+	defer func() {
+		switch r := recover().(type) {
+		case nil:
+		case *ErrorValue:
+			s.cancel(r)
+		case *fatal:
+			// 程序已被取消时 cancel 不会改变原因。
+			s.cancel(r.err)
+		default:
+			panic(r)
+		}
+
+		s.mu.Lock()
+		s.live--
+		s.checkDeadlock()
+		s.mu.Unlock()
+
+		s.gil.Unlock()
+		s.wg.Done()
+	}()
+
+	in.checkCancelled()
+	in.call(callee, args, named)
+}
@@ -0,0 +1,126 @@
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"dreamlang/interpreter"
+	"dreamlang/parser"
+)
+
+// run 解析并以 ctx 执行 source。
+func run(ctx context.Context, in *interpreter.Interpreter, source string) error {
+	return in.RunContext(ctx, parser.Parse(source))
+}
+
+func TestDeadlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "receive with no sender",
+			source: `let ch = chan<number>(); <-ch;`,
+		},
+		{
+			name:   "send with no receiver",
+			source: `let ch = chan<number>(); ch <- 1;`,
+		},
+		{
+			name: "goroutines waiting on each other and select",
+			source: `
+				let a = chan<number>();
+				let b = chan<number>();
+				let done = chan<bool>();
+				fn relay(source: chan<number>, target: chan<number>) { let v = <-source; target <- v; }
+				spawn relay(a, b);
+				spawn relay(b, a);
+				select {
+					case <-done { }
+					case let v = <-b { }
+				}`,
+		},
+		{
+			name: "goroutine exits without sending",
+			source: `
+				let ch = chan<number>();
+				fn worker(out: chan<number>) { }
+				spawn worker(ch);
+				<-ch;`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := run(context.Background(), interpreter.New(), test.source); !errors.Is(err, interpreter.ErrDeadlock) {
+				t.Fatalf("err = %v, want ErrDeadlock", err)
+			}
+		})
+	}
+}
+
+func TestNoDeadlock(t *testing.T) {
+	source := `
+		let ch = chan<number>();
+		let done = chan<bool>();
+		fn worker(out: chan<number>) { out <- 1; out <- 2; close(out); }
+		spawn worker(ch);
+		let sum = 0;
+		foreach v in ch { sum += v; }
+		select {
+			case <-done { }
+			default { }
+		}`
+
+	if err := run(context.Background(), interpreter.New(), source); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
+
+func TestCancelDuringReceive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := interpreter.New()
+	// hold 阻塞到运行被取消为止，它不算作阻塞在通道上，因此主 goroutine 的接收不会被判断为死锁。
+	in.Define("hold", &interpreter.Builtin{Name: "hold", Fn: func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		in.Block(func() { <-in.Context().Done() })
+		return nil
+	}})
+	// receiving 在主 goroutine 开始接收之前通知测试取消运行。
+	receiving := make(chan struct{})
+	in.Define("receiving", &interpreter.Builtin{Name: "receiving", Fn: func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		close(receiving)
+		return nil
+	}})
+	go func() {
+		<-receiving
+		cancel()
+	}()
+
+	err := run(ctx, in, `let ch = chan<number>(); spawn hold(); receiving(); <-ch;`)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestUncaughtErrorInGoroutine(t *testing.T) {
+	source := `
+		let ch = chan<number>();
+		fn fail() { throw "worker failed"; }
+		spawn fail();
+		<-ch;`
+
+	err := run(context.Background(), interpreter.New(), source)
+	var errorValue *interpreter.ErrorValue
+	if !errors.As(err, &errorValue) {
+		t.Fatalf("err = %v, want *ErrorValue", err)
+	}
+	if errorValue.Message != "worker failed" {
+		t.Fatalf("message = %q, want %q", errorValue.Message, "worker failed")
+	}
+	if len(errorValue.Stack) == 0 || errorValue.Stack[len(errorValue.Stack)-1] != "<goroutine>" {
+		t.Fatalf("stack = %v, want it to end with <goroutine>", errorValue.Stack)
+	}
+}
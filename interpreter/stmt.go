@@ -0,0 +1,288 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"dreamlang/ast"
+)
+
+// completion 表示 return 语句的执行结果。exec 返回 nil 时语句正常执行完毕，
+// 返回非 nil 时表示遇到了 return，外层语句应停止执行并把它继续向外传递，直到函数调用处。
+type completion struct {
+	value Value
+}
+
+// execStatements 在环境 env 中依次执行 stmts。
+// 与类型检查器一致，同一代码块中的函数、类和枚举会先被声明，因此可以在声明之前使用。
+func (in *Interpreter) execStatements(stmts []ast.Stmt, env *Environment) *completion {
+	in.hoist(stmts, env)
+
+	for _, stmt := range stmts {
+		if result := in.execStmt(stmt, env); result != nil {
+			return result
+		}
+	}
+
+	return nil
+}
+
+func (in *Interpreter) hoist(stmts []ast.Stmt, env *Environment) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case ast.FunctionDeclarationStmt:
+			env.Define(stmt.Name, &Function{Name: stmt.Name, TypeParameters: stmt.TypeParameters, Parameters: stmt.Parameters, Body: stmt.Body, Closure: env}, true)
+		case ast.ClassDeclarationStmt:
+			env.Define(stmt.Name, newClass(stmt, env), true)
+		case ast.EnumDeclarationStmt:
+			env.Define(stmt.Name, in.newEnum(stmt, env), true)
+		case ast.InterfaceDeclarationStmt:
+			env.defineType(stmt.Name, &typeDecl{iface: &stmt, env: env})
+		case ast.TypeAliasStmt:
+			env.defineType(stmt.Name, &typeDecl{alias: stmt.Type, env: env})
+		}
+	}
+}
+
+func newClass(stmt ast.ClassDeclarationStmt, env *Environment) *Class {
+	class := &Class{Name: stmt.Name, Methods: make(map[string]*Function), Closure: env}
+	for _, typeParam := range stmt.TypeParameters {
+		class.TypeParameters = append(class.TypeParameters, typeParam.Name)
+	}
+
+	for _, member := range stmt.Body {
+		switch member := member.(type) {
+		case ast.VarDeclarationStmt:
+			if field, ok := member.Pattern.(ast.IdentifierPattern); ok {
+				class.Fields = append(class.Fields, Field{Name: field.Name, Type: member.ExplicitType, Initializer: member.AssignedValue})
+			}
+		case ast.FunctionDeclarationStmt:
+			typeParams := append(append([]ast.TypeParameter{}, stmt.TypeParameters...), member.TypeParameters...)
+			method := &Function{Name: stmt.Name + "." + member.Name, TypeParameters: typeParams, Parameters: member.Parameters, Body: member.Body, Closure: env}
+			if member.Name == "constructor" {
+				class.Constructor = method
+			} else {
+				class.Methods[member.Name] = method
+			}
+		}
+	}
+
+	return class
+}
+
+// newEnum 计算枚举成员的值。没有显式给出值的成员取前一个数字成员的值加一，第一个成员默认为 0。
+func (in *Interpreter) newEnum(stmt ast.EnumDeclarationStmt, env *Environment) *Enum {
+	enum := &Enum{Name: stmt.Name, Members: NewMap()}
+	next := 0.0
+
+	for _, member := range stmt.Members {
+		var value Value = next
+		if member.Value != nil {
+			value = in.evalExpr(member.Value, env)
+		}
+
+		if number, ok := value.(float64); ok {
+			next = number + 1
+		}
+
+		enum.Members.Set(member.Name, value)
+	}
+
+	return enum
+}
+
+func (in *Interpreter) execStmt(stmt ast.Stmt, env *Environment) *completion {
+	in.tick()
+
+	switch stmt := stmt.(type) {
+	case ast.BlockStmt:
+		return in.execStatements(stmt.Body, NewEnvironment(env))
+	case ast.ExpressionStmt:
+		in.evalExpr(stmt.Expression, env)
+	case ast.VarDeclarationStmt:
+		in.bindPattern(stmt.Pattern, in.evalInitializer(stmt, env), env, stmt.Constant)
+	case ast.IfStmt:
+		if Truthy(in.evalExpr(stmt.Condition, env)) {
+			return in.execStmt(stmt.Consequent, env)
+		}
+		if stmt.Alternate != nil {
+			return in.execStmt(stmt.Alternate, env)
+		}
+	case ast.ForeachStmt:
+		return in.execForeach(stmt, env)
+	case ast.ReturnStmt:
+		var value Value
+		if stmt.Value != nil {
+			value = in.evalExpr(stmt.Value, env)
+		}
+		return &completion{value: value}
+	case ast.ThrowStmt:
+		in.throw(in.evalExpr(stmt.Value, env))
+	case ast.TryStmt:
+		return in.execTry(stmt, env)
+	case ast.SpawnStmt:
+		callee := in.evalExpr(stmt.Call.Method, env)
+		args, named := in.evalArguments(stmt.Call, env)
+		in.spawn(callee, args, named)
+	case ast.SelectStmt:
+		return in.execSelect(stmt, env)
+	case ast.SwitchStmt:
+		return in.execSwitch(stmt, env)
+	case ast.ImportStmt:
+		in.execImport(stmt, env)
+	case ast.FunctionDeclarationStmt, ast.ClassDeclarationStmt, ast.EnumDeclarationStmt,
+		ast.TypeAliasStmt, ast.InterfaceDeclarationStmt:
+		// 函数、类、枚举、接口和类型别名已在 hoist 中声明。
+	default:
+		panic(fmt.Sprintf("interpreter: unsupported statement %T", stmt))
+	}
+
+	return nil
+}
+
+// execForeach 执行 foreach 循环。遍历列表和字符串时下标为从 0 开始的数字，遍历字典时下标为键。
+// 循环开始前会先取出全部元素，因此在循环体中修改被遍历的列表或字典不会影响遍历。
+// 遍历通道时每次循环接收一个值，直到通道被关闭。遍历范围 lower..upper 时不创建列表，而是依次产生其中的数字。
+func (in *Interpreter) execForeach(stmt ast.ForeachStmt, env *Environment) *completion {
+	if expr, ok := stmt.Iterable.(ast.RangeExpr); ok {
+		lower, upper := in.evalRangeBounds(expr, env)
+		for i := lower; i <= upper; i++ {
+			in.tick()
+			loopEnv := NewEnvironment(env)
+			if stmt.Index != "" {
+				loopEnv.Define(stmt.Index, i-lower, false)
+			}
+			in.bindPattern(stmt.Value, i, loopEnv, false)
+			if result := in.execStatements(stmt.Body, loopEnv); result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+
+	var indexes, elements []Value
+
+	switch iterable := in.evalExpr(stmt.Iterable, env).(type) {
+	case *Channel:
+		for {
+			in.tick()
+			element, ok := in.receive(iterable)
+			if !ok {
+				return nil
+			}
+
+			loopEnv := NewEnvironment(env)
+			in.bindPattern(stmt.Value, element, loopEnv, false)
+			if result := in.execStatements(stmt.Body, loopEnv); result != nil {
+				return result
+			}
+		}
+	case *Array:
+		for i, element := range iterable.Elements {
+			indexes = append(indexes, float64(i))
+			elements = append(elements, element)
+		}
+	case *Map:
+		for _, key := range iterable.Keys {
+			indexes = append(indexes, key)
+			elements = append(elements, iterable.Values[key])
+		}
+	case string:
+		for i, char := range []rune(iterable) {
+			indexes = append(indexes, float64(i))
+			elements = append(elements, string(char))
+		}
+	default:
+		in.throwf("cannot iterate over %s", TypeName(iterable))
+	}
+
+	for i, element := range elements {
+		in.tick()
+		loopEnv := NewEnvironment(env)
+		if stmt.Index != "" {
+			loopEnv.Define(stmt.Index, indexes[i], false)
+		}
+		in.bindPattern(stmt.Value, element, loopEnv, false)
+
+		if result := in.execStatements(stmt.Body, loopEnv); result != nil {
+			return result
+		}
+	}
+
+	return nil
+}
+
+// execSwitch 执行 switch 语句。被比较的值只求值一次，值分支中的值按顺序求值，直到找到相等的值为止。
+func (in *Interpreter) execSwitch(stmt ast.SwitchStmt, env *Environment) *completion {
+	value := in.evalExpr(stmt.Value, env)
+
+	for _, switchCase := range stmt.Cases {
+		matches := switchCase.Type != nil && in.matchesType(value, switchCase.Type, env)
+		for _, candidate := range switchCase.Values {
+			if matches {
+				break
+			}
+			matches = Equal(value, in.evalExpr(candidate, env))
+		}
+
+		if matches {
+			return in.execStatements(switchCase.Body, NewEnvironment(env))
+		}
+	}
+
+	if stmt.Default != nil {
+		return in.execStatements(stmt.Default, NewEnvironment(env))
+	}
+
+	return nil
+}
+
+// execTry 执行 try 语句。
+//
+// try 代码块抛出的错误由 catch 子句处理；无论 try 和 catch 代码块是正常结束、return 还是抛出错误，
+// finally 代码块都会执行。finally 代码块中的 return 会覆盖之前的 return 或尚未处理的错误。
+func (in *Interpreter) execTry(stmt ast.TryStmt, env *Environment) (result *completion) {
+	if stmt.Finally != nil {
+		defer func() {
+			r := recover()
+			if _, ok := r.(*ErrorValue); r != nil && !ok {
+				panic(r)
+			}
+
+			if final := in.execStatements(stmt.Finally, NewEnvironment(env)); final != nil {
+				result = final
+				return
+			}
+
+			if r != nil {
+				panic(r)
+			}
+		}()
+	}
+
+	return in.execTryCatch(stmt, env)
+}
+
+func (in *Interpreter) execTryCatch(stmt ast.TryStmt, env *Environment) (result *completion) {
+	if stmt.Catch != nil {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			thrown, ok := r.(*ErrorValue)
+			if !ok {
+				panic(r)
+			}
+
+			catchEnv := NewEnvironment(env)
+			if stmt.Catch.Name != "" {
+				catchEnv.Define(stmt.Catch.Name, thrown, false)
+			}
+
+			result = in.execStatements(stmt.Catch.Body, catchEnv)
+		}()
+	}
+
+	return in.execStatements(stmt.Body, NewEnvironment(env))
+}
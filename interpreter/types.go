@@ -0,0 +1,331 @@
+package interpreter
+
+import (
+	"sort"
+
+	"dreamlang/ast"
+)
+
+// TypeInfo 是内置类 Type 的实例，即 typeof 表达式的结果，描述一个值的运行时类型。字段的含义见 checker.TypeClass。
+//
+// 字段:
+//   - Name: 类型名，例如 number、[]string、map、User 或 class User。
+//   - Kind: 类型的种类，为 null、number、string、bool、list、map、function、instance、class、enum、channel、module 或 any 之一。
+//   - Fields、Methods: 实例的字段名（按声明顺序）和方法名（按字母顺序），其它值为空。
+//   - Element: 列表的元素类型，所有元素的类型名相同时为该类型，否则（包括空列表）为 any；其它值为 nil。
+//
+// TypeInfo 与字符串比较时比较 Name，与另一个 TypeInfo 比较时比较 Name 和 Kind。
+type TypeInfo struct {
+	Name    string
+	Kind    string
+	Fields  []string
+	Methods []string
+	Element *TypeInfo
+}
+
+// typeClass 是内置类 Type，它的实例只能由 typeof 创建。
+var typeClass = &Class{
+	Name: "Type",
+	Construct: func(in *Interpreter, args []Value) Value {
+		in.throwf("Type cannot be constructed directly, use typeof")
+		return nil
+	},
+}
+
+// builtinMembers 是内置类的实例的字段名和方法名，与 checker 中对应的类一致。
+var builtinMembers = map[string]struct{ fields, methods []string }{
+	"Error": {fields: []string{"message", "value", "stack"}},
+	"Set":   {fields: []string{"size"}, methods: []string{"add", "clear", "delete", "has", "values"}},
+	"Map":   {fields: []string{"size"}, methods: []string{"clear", "delete", "entries", "get", "has", "keys", "set", "values"}},
+	"Regex": {fields: []string{"source", "flags"}, methods: []string{"find", "findAll", "replace", "split", "test"}},
+	"Type":  {fields: []string{"name", "kind", "fields", "methods", "element"}},
+}
+
+var anyType = &TypeInfo{Name: "any", Kind: "any", Fields: []string{}, Methods: []string{}}
+
+func (t *TypeInfo) equals(v Value) bool {
+	switch v := v.(type) {
+	case string:
+		return t.Name == v
+	case *TypeInfo:
+		return t.Name == v.Name && t.Kind == v.Kind
+	}
+
+	return false
+}
+
+// typeOf 实现 typeof 表达式，返回值 v 的运行时类型。
+func (in *Interpreter) typeOf(v Value) *TypeInfo {
+	return in.typeOfValue(v, map[*Array]bool{})
+}
+
+// typeOfValue 实现 typeOf，visiting 为正在计算元素类型的列表。
+// 列表包含它自身时，这一处的元素类型按 any 计算，因此 typeof 对引用了自身的列表也会结束。
+func (in *Interpreter) typeOfValue(v Value, visiting map[*Array]bool) *TypeInfo {
+	in.Allocate(entrySize)
+	info := &TypeInfo{Name: TypeName(v), Fields: []string{}, Methods: []string{}}
+
+	switch v := v.(type) {
+	case nil, float64, string, bool:
+		info.Kind = info.Name
+	case *Array:
+		info.Kind = "list"
+		info.Element = anyType
+		if visiting[v] {
+			info.Name = "[]any"
+			break
+		}
+		visiting[v] = true
+		defer delete(visiting, v)
+
+		for i, element := range v.Elements {
+			elementType := in.typeOfValue(element, visiting)
+			if i > 0 && elementType.Name != info.Element.Name {
+				info.Element = anyType
+				break
+			}
+			info.Element = elementType
+		}
+		info.Name = "[]" + info.Element.Name
+	case *Map:
+		info.Kind = "map"
+	case *Function, *Builtin:
+		info.Kind = "function"
+	case *Class:
+		info.Kind = "class"
+	case *Enum:
+		info.Kind = "enum"
+	case *Channel:
+		info.Kind = "channel"
+	case *Module:
+		info.Kind = "module"
+	case *Instance:
+		info.Kind = "instance"
+		for _, field := range v.Class.Fields {
+			info.Fields = append(info.Fields, field.Name)
+		}
+		for name := range v.Class.Methods {
+			info.Methods = append(info.Methods, name)
+		}
+		sort.Strings(info.Methods)
+	default:
+		info.Kind = "instance"
+		if members, exists := builtinMembers[info.Name]; exists {
+			info.Fields = append(info.Fields, members.fields...)
+			info.Methods = append(info.Methods, members.methods...)
+		}
+	}
+
+	return info
+}
+
+// memberOfType 返回 TypeInfo 的成员 name。
+func (in *Interpreter) memberOfType(t *TypeInfo, name string) (Value, bool) {
+	list := func(names []string) Value {
+		elements := make([]Value, len(names))
+		for i, name := range names {
+			elements[i] = name
+		}
+		return in.NewList(elements)
+	}
+
+	switch name {
+	case "name":
+		return t.Name, true
+	case "kind":
+		return t.Kind, true
+	case "fields":
+		return list(t.Fields), true
+	case "methods":
+		return list(t.Methods), true
+	case "element":
+		if t.Element == nil {
+			return nil, true
+		}
+		return t.Element, true
+	}
+
+	return nil, false
+}
+
+// typeDecl 是接口或类型别名在运行时的表示，只用于 is 和 instanceof 检查。env 为声明所在的环境，用于解析其中的类型名。
+type typeDecl struct {
+	iface *ast.InterfaceDeclarationStmt
+	alias ast.Type
+	env   *Environment
+}
+
+// evalTypeTestExpr 求值 value is T 和 value instanceof T。
+func (in *Interpreter) evalTypeTestExpr(expr ast.TypeTestExpr, env *Environment) bool {
+	return in.matchesType(in.evalExpr(expr.Value, env), expr.Type, env)
+}
+
+// matchesType 判断 value 在运行时是否属于类型 t，env 为 t 所在的环境。
+//
+// 列表、元组和字典会逐个检查元素；类的实例按类判断，泛型类的类型实参不检查；
+// 接口按结构判断：值是类的实例（包括内置类），且拥有接口的全部字段和方法，字段的值属于字段的类型。
+// 函数类型只检查值是否为函数，通道类型只检查值是否为通道。
+func (in *Interpreter) matchesType(value Value, t ast.Type, env *Environment) bool {
+	switch t := t.(type) {
+	case ast.SymbolType:
+		return in.matchesNamedType(value, t.Value, env)
+	case ast.GenericType:
+		return in.matchesNamedType(value, t.Name, env)
+	case ast.OptionalType:
+		return value == nil || in.matchesType(value, t.Underlying, env)
+	case ast.UnionType:
+		for _, member := range t.Members {
+			if in.matchesType(value, member, env) {
+				return true
+			}
+		}
+		return false
+	case ast.ListType:
+		list, ok := value.(*Array)
+		if !ok {
+			return false
+		}
+		for _, element := range list.Elements {
+			if !in.matchesType(element, t.Underlying, env) {
+				return false
+			}
+		}
+		return true
+	case ast.TupleType:
+		list, ok := value.(*Array)
+		if !ok || len(list.Elements) != len(t.Members) {
+			return false
+		}
+		for i, member := range t.Members {
+			if !in.matchesType(list.Elements[i], member, env) {
+				return false
+			}
+		}
+		return true
+	case ast.MapType:
+		m, ok := value.(*Map)
+		if !ok {
+			return false
+		}
+		for _, key := range m.Keys {
+			if !in.matchesType(key, t.Key, env) || !in.matchesType(m.Values[key], t.Value, env) {
+				return false
+			}
+		}
+		return true
+	case ast.ChannelType:
+		_, ok := value.(*Channel)
+		return ok
+	case ast.FunctionType:
+		switch value.(type) {
+		case *Function, *Builtin:
+			return true
+		}
+		return false
+	}
+
+	return false
+}
+
+// matchesNamedType 判断 value 是否属于名为 name 的类型：基本类型、接口、类型别名、类或枚举。
+func (in *Interpreter) matchesNamedType(value Value, name string, env *Environment) bool {
+	switch name {
+	case "any":
+		return true
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool", "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null", "void":
+		return value == nil
+	}
+
+	if decl, exists := env.lookupType(name); exists {
+		if decl.iface != nil {
+			return in.implements(value, decl.iface, decl.env)
+		}
+		return in.matchesType(value, decl.alias, decl.env)
+	}
+
+	switch named, _ := env.Lookup(name); named := named.(type) {
+	case *Class:
+		return isInstance(value, named)
+	case *Enum:
+		for _, key := range named.Members.Keys {
+			if Equal(named.Members.Values[key], value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isInstance 判断 value 是否为类 class 的实例。内置类的实例是对应的 Go 类型，例如 Set 的实例是 *Set。
+func isInstance(value Value, class *Class) bool {
+	if class.Construct != nil {
+		switch value.(type) {
+		case *ErrorValue:
+			return class.Name == "Error"
+		case *Set:
+			return class.Name == "Set"
+		case *HashMap:
+			return class.Name == "Map"
+		case *Regex:
+			return class.Name == "Regex"
+		case *TypeInfo:
+			return class.Name == "Type"
+		}
+	}
+
+	instance, ok := value.(*Instance)
+	return ok && instance.Class == class
+}
+
+// implements 判断 value 是否满足接口 iface。
+func (in *Interpreter) implements(value Value, iface *ast.InterfaceDeclarationStmt, env *Environment) bool {
+	switch value.(type) {
+	case *Instance, *ErrorValue, *Set, *HashMap, *Regex, *TypeInfo:
+	default:
+		return false
+	}
+
+	for _, field := range iface.Fields {
+		fieldValue, exists := in.lookupMember(value, field.Name)
+		if !exists || !in.matchesType(fieldValue, field.Type, env) {
+			return false
+		}
+	}
+
+	for _, method := range iface.Methods {
+		if _, exists := in.lookupMember(value, method.Name); !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// defineType 在当前作用域中声明接口或类型别名 name。
+func (e *Environment) defineType(name string, decl *typeDecl) {
+	if e.types == nil {
+		e.types = make(map[string]*typeDecl)
+	}
+	e.types[name] = decl
+}
+
+// lookupType 从当前作用域开始逐层向外查找接口或类型别名。
+func (e *Environment) lookupType(name string) (*typeDecl, bool) {
+	for env := e; env != nil; env = env.parent {
+		if decl, exists := env.types[name]; exists {
+			return decl, true
+		}
+	}
+
+	return nil, false
+}
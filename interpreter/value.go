@@ -0,0 +1,306 @@
+package interpreter
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"dreamlang/ast"
+)
+
+// Value 表示 DreamLang 程序运行时的一个值。
+//
+// 不同类型的值在 Go 中的表示:
+//   - null: nil
+//   - number: float64
+//   - string: string
+//   - bool: bool
+//   - 列表和元组: *Array
+//   - 字典: *Map
+//   - 函数: *Function（源代码中定义的函数）或 *Builtin（内置函数）
+//   - 类: *Class；类的实例: *Instance
+//   - 枚举: *Enum（枚举的成员值为 number 或 string）
+//   - 通道: *Channel
+//   - 模块: *Module
+//   - Error 的实例: *ErrorValue
+//   - Set 和 Map 的实例: *Set 和 *HashMap
+type Value any
+
+// Array 是列表和元组的运行时表示。多个变量可以引用同一个 Array。
+type Array struct {
+	Elements []Value
+}
+
+// Map 是字典的运行时表示，遍历时按键的插入顺序进行。
+type Map struct {
+	Keys   []Value
+	Values map[Value]Value
+}
+
+func NewMap() *Map {
+	return &Map{Values: make(map[Value]Value)}
+}
+
+func (m *Map) Get(key Value) (Value, bool) {
+	value, exists := m.Values[key]
+	return value, exists
+}
+
+func (m *Map) Set(key Value, value Value) {
+	if _, exists := m.Values[key]; !exists {
+		m.Keys = append(m.Keys, key)
+	}
+
+	m.Values[key] = value
+}
+
+// Function 是源代码中定义的函数。Closure 为函数定义处的环境；
+// This 不为 nil 时函数是绑定到某个实例的方法，调用时函数体中的 this 指向该实例。
+// TypeParameters 为泛型函数的类型参数（方法还包括所在类的类型参数），只在检查宿主程序传入的实参时使用。
+type Function struct {
+	Name           string
+	TypeParameters []ast.TypeParameter
+	Parameters     []ast.Parameter
+	Body           []ast.Stmt
+	Closure        *Environment
+	This           Value
+}
+
+// bind 返回 this 绑定到 instance 的方法。
+func (f *Function) bind(instance Value) *Function {
+	bound := *f
+	bound.This = instance
+	return &bound
+}
+
+// Builtin 是用 Go 实现的内置函数。
+//
+// Decode 为 true 时，它的返回值的类型由上下文决定：带类型注解的变量声明以对它的调用为初始值时，
+// 返回值会按声明的类型解码并验证（见 Interpreter.decode），例如 let u: User = json.parse(text)。
+type Builtin struct {
+	Name   string
+	Fn     func(in *Interpreter, args []Value) Value
+	Decode bool
+}
+
+// Field 是类中声明的一个字段。Type 为字段的类型注解，Initializer 为字段的初始值表达式，没有时为 nil。
+type Field struct {
+	Name        string
+	Type        ast.Type
+	Initializer ast.Expr
+}
+
+// Class 是类的运行时表示。Construct 不为 nil 时为内置类，new 表达式直接调用 Construct 创建实例。
+// TypeParameters 为泛型类的类型参数名，只在解码时使用。
+type Class struct {
+	Name           string
+	TypeParameters []string
+	Fields         []Field
+	Methods        map[string]*Function
+	Constructor    *Function
+	Closure        *Environment
+	Construct      func(in *Interpreter, args []Value) Value
+}
+
+// Instance 是类的实例。
+type Instance struct {
+	Class  *Class
+	Fields map[string]Value
+}
+
+// Enum 是枚举的运行时表示，Members 按声明顺序保存成员名到成员值的映射。
+type Enum struct {
+	Name    string
+	Members *Map
+}
+
+// ErrorValue 是内置类 Error 的实例，也是 throw 语句在运行时传递的值。
+//
+// Message 为错误信息；Value 为 throw 语句抛出的原始值（抛出的不是 Error 时，该值会被包装为 Error）；
+// Stack 为错误创建时的调用栈，最内层的函数在前。
+//
+// 未被捕获的 ErrorValue 会作为 Run 的返回值，因此它实现了 error 接口。
+type ErrorValue struct {
+	Message string
+	Value   Value
+	Stack   []string
+}
+
+func (e *ErrorValue) Error() string {
+	var sb strings.Builder
+	sb.WriteString("Error: " + e.Message)
+
+	for _, frame := range e.Stack {
+		sb.WriteString("\n    at " + frame)
+	}
+
+	return sb.String()
+}
+
+// TypeName 返回值 v 在 DreamLang 中的类型名，用于运行时错误信息。
+func TypeName(v Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case *Array:
+		return "list"
+	case *Map:
+		return "map"
+	case *Function, *Builtin:
+		return "function"
+	case *Class:
+		return "class " + v.Name
+	case *Instance:
+		return v.Class.Name
+	case *Enum:
+		return "enum " + v.Name
+	case *ErrorValue:
+		return "Error"
+	case *Channel:
+		return "chan"
+	case *Module:
+		return "module " + v.Name
+	case *Set:
+		return "Set"
+	case *HashMap:
+		return "Map"
+	case *Regex:
+		return "Regex"
+	case *TypeInfo:
+		return "Type"
+	}
+
+	return "unknown"
+}
+
+// Truthy 判断值 v 作为条件时是否为真。null、false、0 和空字符串为假，其它值都为真。
+func Truthy(v Value) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+
+	return true
+}
+
+// Equal 判断两个值是否相等。基本类型按值比较，列表、字典、函数和实例等按引用比较；
+// typeof 得到的 Type 与字符串比较时比较类型名，因此 typeof x == "string" 成立。
+func Equal(a, b Value) bool {
+	if t, ok := a.(*TypeInfo); ok {
+		return t.equals(b)
+	}
+	if t, ok := b.(*TypeInfo); ok {
+		return t.equals(a)
+	}
+
+	return a == b
+}
+
+// Stringify 返回值 v 的字符串形式，用于字符串拼接、模板字符串和错误信息。
+// 列表、字典或实例引用了包含它自身的值时，这一处引用显示为 [...]。
+func Stringify(v Value) string {
+	return stringify(v, map[Value]bool{})
+}
+
+// stringify 实现 Stringify，visiting 为正在显示的列表、字典、实例和集合。
+func stringify(v Value, visiting map[Value]bool) string {
+	switch v.(type) {
+	case *Array, *Map, *Instance, *Set, *HashMap:
+		if visiting[v] {
+			return "[...]"
+		}
+		visiting[v] = true
+		defer delete(visiting, v)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return formatNumber(v)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case *Array:
+		parts := make([]string, len(v.Elements))
+		for i, element := range v.Elements {
+			parts[i] = quote(element, visiting)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case *Map:
+		parts := make([]string, len(v.Keys))
+		for i, key := range v.Keys {
+			parts[i] = quote(key, visiting) + ": " + quote(v.Values[key], visiting)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case *Function:
+		return "fn " + v.Name
+	case *Builtin:
+		return "fn " + v.Name
+	case *Class:
+		return "class " + v.Name
+	case *Instance:
+		parts := make([]string, 0, len(v.Class.Fields))
+		for _, field := range v.Class.Fields {
+			parts = append(parts, field.Name+": "+quote(v.Fields[field.Name], visiting))
+		}
+		return v.Class.Name + " {" + strings.Join(parts, ", ") + "}"
+	case *Enum:
+		return "enum " + v.Name
+	case *ErrorValue:
+		return "Error: " + v.Message
+	case *Channel:
+		return "chan"
+	case *Module:
+		return "module " + v.Name
+	case *Set:
+		parts := make([]string, len(v.Entries.Keys))
+		for i, element := range v.Entries.Keys {
+			parts[i] = quote(element, visiting)
+		}
+		return "Set {" + strings.Join(parts, ", ") + "}"
+	case *HashMap:
+		return "Map " + stringify(v.Entries, visiting)
+	case *Regex:
+		return "/" + v.Source + "/" + v.Flags
+	case *TypeInfo:
+		return v.Name
+	}
+
+	return "<unknown>"
+}
+
+// quote 与 stringify 相同，但字符串会加上引号，用于显示列表和字典中的元素。
+func quote(v Value, visiting map[Value]bool) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+
+	return stringify(v, visiting)
+}
+
+func formatNumber(n float64) string {
+	switch {
+	case math.IsInf(n, 1):
+		return "Infinity"
+	case math.IsInf(n, -1):
+		return "-Infinity"
+	case math.IsNaN(n):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
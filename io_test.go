@@ -0,0 +1,63 @@
+package dreamlang_test
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/io")
+
+// TestGoldenIO 执行 testdata/io 中的每个 .dl 程序，把它的输出与同名的 .golden 文件比较。
+// 同名的 .in 文件存在时作为程序的标准输入，否则标准输入为空。以 -update 运行时重新生成 .golden 文件。
+func TestGoldenIO(t *testing.T) {
+	programs, err := filepath.Glob(filepath.Join("testdata", "io", "*.dl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Fatal("no programs in testdata/io")
+	}
+
+	for _, program := range programs {
+		base := strings.TrimSuffix(program, ".dl")
+		t.Run(filepath.Base(base), func(t *testing.T) {
+			src, err := os.ReadFile(program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stdin, err := os.ReadFile(base + ".in")
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			rt := dreamlang.NewRuntime(dreamlang.Options{Stdout: &stdout, Stdin: bytes.NewReader(stdin)})
+			if _, err := rt.Eval(context.Background(), string(src)); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := base + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, stdout.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stdout.String(); got != string(want) {
+				t.Fatalf("output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"dreamlang/lexer"
+)
+
+// tokenize 对 source 做词法分析，返回除 EOF 以外的全部标记，或者 Tokenize 以 panic 报告的 *LexError。
+func tokenize(t *testing.T, source string) (tokens []lexer.Token, lexError *lexer.LexError) {
+	t.Helper()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(*lexer.LexError)
+			if !ok {
+				panic(r)
+			}
+			tokens, lexError = nil, err
+		}
+	}()
+
+	tokens = lexer.Tokenize(source)
+	return tokens[:len(tokens)-1], nil
+}
+
+// mustTokenize 与 tokenize 相同，但词法分析出错时使测试失败。
+func mustTokenize(t *testing.T, source string) []lexer.Token {
+	t.Helper()
+
+	tokens, err := tokenize(t, source)
+	if err != nil {
+		t.Fatalf("Tokenize(%q): %v", source, err)
+	}
+
+	return tokens
+}
@@ -0,0 +1,129 @@
+package lexer
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// RegexFlags 是正则表达式字面量可以使用的标志，与 Go 正则表达式的内联标志相同:
+//   - i: 不区分大小写
+//   - m: 多行模式，^ 和 $ 匹配行首和行尾
+//   - s: . 匹配换行符
+//   - U: 非贪婪模式，交换 x* 和 x*? 等的含义
+const RegexFlags = "imsU"
+
+// regexAllowed 判断当前位置的 "/" 能否开始正则表达式字面量。
+// 上一个标记能够结束一个表达式（例如标识符、字面量、")"、"]" 和 "}"）时 "/" 是除法运算符，否则开始正则表达式字面量，
+// 因此 a / b / c 是两次除法，而 x = /a+/ 和 f(/a/i) 中是正则表达式。
+func (l *Lexer) regexAllowed() bool {
+	switch l.last {
+	case TokenTypeValIdentifier, TokenTypeValNumber, TokenTypeValString, TokenTypeValNull, TokenTypeValTrue, TokenTypeValFalse,
+		TokenTypeValTemplateTail, TokenTypeValRegex, TokenTypeSymbolRParen, TokenTypeSymbolRBracket, TokenTypeSymbolRBrance,
+		TokenTypeSymbolPlusPlus, TokenTypeSymbolMinusMinus:
+		return false
+	}
+
+	return true
+}
+
+// scanRegex 扫描形如 /pattern/flags 的正则表达式字面量，返回的标记的值为 pattern/flags（不包括开头的 "/"）。
+//
+// pattern 中的 "\/" 和字符类 [...] 中的 "/" 不会结束字面量。字面量不能跨行。
+// flags 只能包含 RegexFlags 中的字符且不能重复；pattern 必须是合法的 Go（RE2）正则表达式，
+// 否则以 *LexError 触发 panic，Span 指向 pattern 中出错的部分。
+func (l *Lexer) scanRegex(start Position) Token {
+	var pattern strings.Builder
+	l.advance(1)
+	patternStart := l.position()
+
+	inClass := false
+	for {
+		if l.index >= len(l.input) || l.input[l.index] == '\n' {
+			panic(l.errorf(start, "unterminated regular expression literal"))
+		}
+
+		c := l.input[l.index]
+		if c == '/' && !inClass {
+			break
+		}
+
+		switch c {
+		case '\\':
+			pattern.WriteRune(c)
+			l.advance(1)
+			if l.index < len(l.input) && l.input[l.index] != '\n' {
+				pattern.WriteRune(l.input[l.index])
+				l.advance(1)
+			}
+			continue
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		}
+
+		pattern.WriteRune(c)
+		l.advance(1)
+	}
+	l.advance(1)
+
+	flagsStart := l.position()
+	var flags strings.Builder
+	for l.index < len(l.input) && isIdentContinue(l.input[l.index]) {
+		c := l.input[l.index]
+		if !strings.ContainsRune(RegexFlags, c) || strings.ContainsRune(flags.String(), c) {
+			l.advance(1)
+			panic(l.errorf(flagsStart, "invalid regular expression flag %q", c))
+		}
+		flags.WriteRune(c)
+		l.advance(1)
+	}
+
+	if _, err := regexp.Compile(pattern.String()); err != nil {
+		end := l.position()
+		errStart, errEnd := patternStart, end
+		if offset, length, ok := RegexErrorPosition(pattern.String(), err); ok {
+			errStart = shift(patternStart, pattern.String()[:offset])
+			errEnd = shift(errStart, pattern.String()[offset:offset+length])
+		}
+		panic(&LexError{Message: "invalid regular expression: " + RegexErrorMessage(err), Span: Span{Start: errStart, End: errEnd}})
+	}
+
+	return l.token(TokenTypeValRegex, pattern.String()+"/"+flags.String(), start)
+}
+
+// RegexErrorPosition 返回编译 pattern 时的错误 err 所指的部分在 pattern 中的字节偏移和长度，无法确定时 ok 为 false。
+func RegexErrorPosition(pattern string, err error) (offset, length int, ok bool) {
+	var syntaxError *syntax.Error
+	if !errors.As(err, &syntaxError) || syntaxError.Expr == "" {
+		return 0, 0, false
+	}
+
+	offset = strings.Index(pattern, syntaxError.Expr)
+	if offset < 0 {
+		return 0, 0, false
+	}
+
+	return offset, len(syntaxError.Expr), true
+}
+
+// RegexErrorMessage 返回编译正则表达式时的错误 err 的描述，不包括 Go 的 "error parsing regexp:" 前缀。
+func RegexErrorMessage(err error) string {
+	var syntaxError *syntax.Error
+	if errors.As(err, &syntaxError) {
+		return syntaxError.Code.String() + ": `" + syntaxError.Expr + "`"
+	}
+
+	return err.Error()
+}
+
+// shift 返回位置 p 之后经过不含换行符的文本 text 的位置。
+func shift(p Position, text string) Position {
+	p.Offset += len(text)
+	p.Column += len(text)
+	p.RuneColumn += utf8.RuneCountInString(text)
+	return p
+}
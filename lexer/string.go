@@ -0,0 +1,140 @@
+package lexer
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// scanString 扫描以 quote（双引号或单引号）包围的字符串字面量，并解析其中的转义序列。
+//
+// 支持的转义序列:
+//   - \n \t \r \0 \\ \" \' \` \$
+//   - \xNN: 两位十六进制数表示的单个字节
+//   - \u{...}: 1 到 6 位十六进制数表示的 Unicode 码点
+//
+// 字符串在行尾或文件末尾仍未闭合时，以覆盖整个字面量的 Span 触发 *LexError。
+func (l *Lexer) scanString(quote rune, start Position) Token {
+	var sb strings.Builder
+	l.advance(1)
+
+	for {
+		if l.index >= len(l.input) || l.input[l.index] == '\n' {
+			panic(l.errorf(start, "unterminated string literal"))
+		}
+
+		c := l.input[l.index]
+		if c == quote {
+			l.advance(1)
+			break
+		}
+
+		if c == '\\' {
+			l.scanEscape(&sb)
+			continue
+		}
+
+		sb.WriteRune(c)
+		l.advance(1)
+	}
+
+	return l.token(TokenTypeValString, sb.String(), start)
+}
+
+func (l *Lexer) scanEscape(sb *strings.Builder) {
+	start := l.position()
+	l.advance(1)
+
+	if l.index >= len(l.input) {
+		panic(l.errorf(start, "unterminated escape sequence"))
+	}
+
+	c := l.input[l.index]
+	l.advance(1)
+
+	switch c {
+	case 'n':
+		sb.WriteByte('\n')
+	case 't':
+		sb.WriteByte('\t')
+	case 'r':
+		sb.WriteByte('\r')
+	case '0':
+		sb.WriteByte(0)
+	case '\\', '"', '\'', '`', '$':
+		sb.WriteRune(c)
+	case 'x':
+		if l.index+2 > len(l.input) || !hexChar[l.input[l.index]] || !hexChar[l.input[l.index+1]] {
+			panic(l.errorf(start, "invalid \\x escape: expected two hex digits"))
+		}
+		value, _ := strconv.ParseUint(string(l.input[l.index:l.index+2]), 16, 8)
+		l.advance(2)
+		sb.WriteByte(byte(value))
+	case 'u':
+		if l.peek(0) != '{' {
+			panic(l.errorf(start, "invalid \\u escape: expected '{'"))
+		}
+		l.advance(1)
+
+		digits := l.index
+		for l.index < len(l.input) && hexChar[l.input[l.index]] {
+			l.advance(1)
+		}
+		hex := string(l.input[digits:l.index])
+
+		if l.peek(0) != '}' || len(hex) == 0 || len(hex) > 6 {
+			panic(l.errorf(start, "invalid \\u escape: expected 1 to 6 hex digits followed by '}'"))
+		}
+		l.advance(1)
+
+		value, _ := strconv.ParseUint(hex, 16, 32)
+		if !utf8.ValidRune(rune(value)) {
+			panic(l.errorf(start, "invalid \\u escape: U+%X is not a valid code point", value))
+		}
+		sb.WriteRune(rune(value))
+	default:
+		panic(l.errorf(start, "unknown escape sequence \\%c", c))
+	}
+}
+
+// scanTemplate 扫描反引号字符串中的一段原始文本（不解析转义序列），直到遇到闭合的反引号或 "${"。
+//
+// 参数:
+//   - start: 当前这段文本所属标记的起始位置。
+//   - templateStart: 整个模板字符串起始反引号的位置，用于报告未闭合的模板字符串。
+//   - head: 是否为模板字符串的第一段。
+//
+// 返回值:
+//   - 不含插值的反引号字符串返回 TokenTypeValString；
+//   - 第一段以 "${" 结束时返回 TokenTypeValTemplateHead，后续段返回 TokenTypeValTemplateMiddle；
+//   - 以反引号结束的后续段返回 TokenTypeValTemplateTail。
+func (l *Lexer) scanTemplate(start Position, templateStart Position, head bool) Token {
+	var sb strings.Builder
+
+	for {
+		if l.index >= len(l.input) {
+			panic(l.errorf(templateStart, "unterminated template string"))
+		}
+
+		c := l.input[l.index]
+		if c == '`' {
+			l.advance(1)
+			if head {
+				return l.token(TokenTypeValString, sb.String(), start)
+			}
+			return l.token(TokenTypeValTemplateTail, sb.String(), start)
+		}
+
+		if c == '$' && l.peek(1) == '{' {
+			l.advance(2)
+			l.templates = append(l.templates, templateFrame{Start: templateStart})
+			if head {
+				return l.token(TokenTypeValTemplateHead, sb.String(), start)
+			}
+			return l.token(TokenTypeValTemplateMiddle, sb.String(), start)
+		}
+
+		sb.WriteRune(c)
+		l.advance(1)
+	}
+}
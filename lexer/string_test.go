@@ -0,0 +1,107 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"dreamlang/lexer"
+)
+
+func TestStringLiterals(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"double quotes", `"hello"`, "hello"},
+		{"single quotes", `'say "hi"'`, `say "hi"`},
+		{"simple escapes", `"a\n\t\r\\\"\'\$"`, "a\n\t\r\\\"'$"},
+		{"nul", `"\0"`, "\x00"},
+		{"hex escape", `"\x41\x7e"`, "A~"},
+		{"unicode escape", `"\u{4e2d}\u{6587}"`, "中文"},
+		{"unicode escape outside the BMP", `"\u{1F600}"`, "😀"},
+		{"unicode escape with one digit", `"\u{41}"`, "A"},
+		{"raw backtick string", "`a\\nb`", `a\nb`},
+		{"multi-byte text", `"你好"`, "你好"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := mustTokenize(t, test.source)
+			if len(tokens) != 1 || tokens[0].Kind != lexer.TokenTypeValString {
+				t.Fatalf("tokens = %v, want one string", tokens)
+			}
+			if tokens[0].Value != test.want {
+				t.Fatalf("value = %q, want %q", tokens[0].Value, test.want)
+			}
+		})
+	}
+}
+
+func TestTemplateTokens(t *testing.T) {
+	tokens := mustTokenize(t, "`Hello ${user.name}, ${ {a: 1}.a }!`")
+
+	want := []struct {
+		kind  lexer.TokenKind
+		value string
+	}{
+		{lexer.TokenTypeValTemplateHead, "Hello "},
+		{lexer.TokenTypeValIdentifier, "user"},
+		{lexer.TokenTypeSymbolDot, "."},
+		{lexer.TokenTypeValIdentifier, "name"},
+		{lexer.TokenTypeValTemplateMiddle, ", "},
+		{lexer.TokenTypeSymbolLBrance, "{"},
+		{lexer.TokenTypeValIdentifier, "a"},
+		{lexer.TokenTypeSymbolColon, ":"},
+		{lexer.TokenTypeValNumber, "1"},
+		{lexer.TokenTypeSymbolRBrance, "}"},
+		{lexer.TokenTypeSymbolDot, "."},
+		{lexer.TokenTypeValIdentifier, "a"},
+		{lexer.TokenTypeValTemplateTail, "!"},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %v", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		if tokens[i].Kind != w.kind || tokens[i].Value != w.value {
+			t.Errorf("token %d = %s %q, want %s %q", i, lexer.TokenKindString(tokens[i].Kind), tokens[i].Value, lexer.TokenKindString(w.kind), w.value)
+		}
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		message string
+		span    string
+	}{
+		{"unterminated string", `x = "abc`, "unterminated string literal", "1:5-1:9"},
+		{"string ends at the end of the line", "'abc\nd'", "unterminated string literal", "1:1-1:5"},
+		{"unterminated template", "`abc", "unterminated template string", "1:1-1:5"},
+		{"unterminated interpolation", "a = `x ${y", "unterminated template string", "1:5-1:11"},
+		{"unterminated template after an interpolation", "`${y} z", "unterminated template string", "1:1-1:8"},
+		{"unknown escape", `"a\q"`, "unknown escape sequence \\q", "1:3-1:5"},
+		{"short hex escape", `"\x4"`, "invalid \\x escape", "1:2-1:4"},
+		{"unicode escape without braces", `"\u41"`, "invalid \\u escape: expected '{'", "1:2-1:4"},
+		{"empty unicode escape", `"\u{}"`, "expected 1 to 6 hex digits", "1:2-1:5"},
+		{"too many digits", `"\u{1234567}"`, "expected 1 to 6 hex digits", "1:2-1:12"},
+		{"surrogate", `"\u{D800}"`, "U+D800 is not a valid code point", "1:2-1:10"},
+		{"column counts runes", `"中文\q"`, "unknown escape sequence", "1:4-1:6"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := tokenize(t, test.source)
+			if err == nil {
+				t.Fatalf("Tokenize(%q) succeeded, want %q", test.source, test.message)
+			}
+			if !strings.Contains(err.Message, test.message) {
+				t.Errorf("message = %q, want %q", err.Message, test.message)
+			}
+			if got := err.Span.String(); got != test.span {
+				t.Errorf("span = %s, want %s", got, test.span)
+			}
+		})
+	}
+}
@@ -12,6 +12,10 @@ const (
 	TokenTypeValNumber
 	TokenTypeValString
 	TokenTypeValIdentifier
+	TokenTypeValTemplateHead
+	TokenTypeValTemplateMiddle
+	TokenTypeValTemplateTail
+	TokenTypeValRegex
 
 	// Grouping & Braces
 	TokenTypeSymbolLBracket
@@ -72,6 +76,7 @@ const (
 	TokenTypeSymbolStar
 	TokenTypeSymbolSlash
 	TokenTypeSymbolPercent
+	TokenTypeSymbolStarStar
 
 	// Reserved Keywords
 	TokenTypeKeywordVar
@@ -99,42 +104,100 @@ const (
 	TokenTypeKeywordFor
 	TokenTypeKeywordWhile
 	TokenTypeKeywordExport
+	TokenTypeKeywordType
+	TokenTypeKeywordEnum
+	TokenTypeKeywordInterface
+	TokenTypeKeywordReturn
+	TokenTypeKeywordThrow
+	TokenTypeKeywordTry
+	TokenTypeKeywordCatch
+	TokenTypeKeywordFinally
+	TokenTypeKeywordSpawn
+	TokenTypeKeywordChan
+	TokenTypeKeywordSelect
+	TokenTypeKeywordTypeof
+	TokenTypeKeywordInstanceof
+	TokenTypeKeywordIs
 
 	// Misc
 	NUM_TOKENS
 )
 
 var reserved_lu map[string]TokenKind = map[string]TokenKind{
-	"var":      TokenTypeKeywordVar,
-	"let":      TokenTypeKeywordLet,
-	"val":      TokenTypeKeywordVal,
-	"class":    TokenTypeKeywordClass,
-	"new":      TokenTypeKeywordNew,
-	"public":   TokenTypeKeywordPublic,
-	"private":  TokenTypeKeywordPrivate,
-	"static":   TokenTypeKeywordStatic,
-	"final":    TokenTypeKeywordFinal,
-	"abstract": TokenTypeKeywordAbstract,
-	"override": TokenTypeKeywordOverride,
-	"import":   TokenTypeKeywordImport,
-	"from":     TokenTypeKeywordFrom,
-	"func":     TokenTypeKeywordFunc,
-	"if":       TokenTypeKeywordIf,
-	"else":     TokenTypeKeywordElse,
-	"elseif":   TokenTypeKeywordElseIf,
-	"switch":   TokenTypeKeywordSwitch,
-	"case":     TokenTypeKeywordCase,
-	"default":  TokenTypeKeywordDefault,
-	"foreach":  TokenTypeKeywordForeach,
-	"in":       TokenTypeKeywordIn,
-	"for":      TokenTypeKeywordFor,
-	"while":    TokenTypeKeywordWhile,
-	"export":   TokenTypeKeywordExport,
+	"var":        TokenTypeKeywordVar,
+	"let":        TokenTypeKeywordLet,
+	"val":        TokenTypeKeywordVal,
+	"class":      TokenTypeKeywordClass,
+	"new":        TokenTypeKeywordNew,
+	"public":     TokenTypeKeywordPublic,
+	"private":    TokenTypeKeywordPrivate,
+	"static":     TokenTypeKeywordStatic,
+	"final":      TokenTypeKeywordFinal,
+	"abstract":   TokenTypeKeywordAbstract,
+	"override":   TokenTypeKeywordOverride,
+	"import":     TokenTypeKeywordImport,
+	"from":       TokenTypeKeywordFrom,
+	"func":       TokenTypeKeywordFunc,
+	"fn":         TokenTypeKeywordFunc,
+	"if":         TokenTypeKeywordIf,
+	"else":       TokenTypeKeywordElse,
+	"elseif":     TokenTypeKeywordElseIf,
+	"switch":     TokenTypeKeywordSwitch,
+	"case":       TokenTypeKeywordCase,
+	"default":    TokenTypeKeywordDefault,
+	"foreach":    TokenTypeKeywordForeach,
+	"in":         TokenTypeKeywordIn,
+	"for":        TokenTypeKeywordFor,
+	"while":      TokenTypeKeywordWhile,
+	"export":     TokenTypeKeywordExport,
+	"type":       TokenTypeKeywordType,
+	"enum":       TokenTypeKeywordEnum,
+	"interface":  TokenTypeKeywordInterface,
+	"return":     TokenTypeKeywordReturn,
+	"throw":      TokenTypeKeywordThrow,
+	"try":        TokenTypeKeywordTry,
+	"catch":      TokenTypeKeywordCatch,
+	"finally":    TokenTypeKeywordFinally,
+	"spawn":      TokenTypeKeywordSpawn,
+	"chan":       TokenTypeKeywordChan,
+	"select":     TokenTypeKeywordSelect,
+	"typeof":     TokenTypeKeywordTypeof,
+	"instanceof": TokenTypeKeywordInstanceof,
+	"is":         TokenTypeKeywordIs,
+	"true":       TokenTypeValTrue,
+	"false":      TokenTypeValFalse,
+	"null":       TokenTypeValNull,
+}
+
+// Position 表示源代码中的一个位置。
+//
+// 字段:
+//   - Offset: 从源代码开头起的字节偏移，从 0 开始。
+//   - Line: 行号，从 1 开始。
+//   - Column: 以字节计的列号，从 1 开始。
+//   - RuneColumn: 以 rune（Unicode 码点）计的列号，从 1 开始。对于中文等多字节字符，它与编辑器中显示的列号一致。
+type Position struct {
+	Offset     int
+	Line       int
+	Column     int
+	RuneColumn int
+}
+
+// Span 表示源代码中的一段区间，Start 为起始位置，End 为结束位置（不包含）。
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// String 以 "行:列-行:列" 的形式返回区间，列号使用 rune 列号，与编辑器中显示的位置一致。
+func (s Span) String() string {
+	return fmt.Sprintf("%d:%d-%d:%d", s.Start.Line, s.Start.RuneColumn, s.End.Line, s.End.RuneColumn)
 }
 
 type Token struct {
 	Kind  TokenKind
 	Value string
+	Span  Span
 }
 
 func (tk Token) IsOneOfMany(expectedTokens ...TokenKind) bool {
@@ -148,7 +211,8 @@ func (tk Token) IsOneOfMany(expectedTokens ...TokenKind) bool {
 }
 
 func (token Token) Debug() {
-	if token.Kind == TokenTypeValIdentifier || token.Kind == TokenTypeValNumber || token.Kind == TokenTypeValString {
+	if token.IsOneOfMany(TokenTypeValIdentifier, TokenTypeValNumber, TokenTypeValString,
+		TokenTypeValTemplateHead, TokenTypeValTemplateMiddle, TokenTypeValTemplateTail, TokenTypeValRegex) {
 		fmt.Printf("%s(%s)\n", TokenKindString(token.Kind), token.Value)
 	} else {
 		fmt.Printf("%s()\n", TokenKindString(token.Kind))
@@ -171,6 +235,10 @@ func (token Token) Debug() {
 //  - TokenTypeValNumber: "number"
 //  - TokenTypeValString: "string"
 //  - TokenTypeValIdentifier: "identifier"
+//  - TokenTypeValTemplateHead: "template_head"
+//  - TokenTypeValTemplateMiddle: "template_middle"
+//  - TokenTypeValTemplateTail: "template_tail"
+//  - TokenTypeValRegex: "regex"
 //  - TokenTypeSymbolLBracket: "["
 //  - TokenTypeSymbolRBracket: "]"
 //  - TokenTypeSymbolLBrance: "{"
@@ -217,6 +285,7 @@ func (token Token) Debug() {
 //  - TokenTypeSymbolStar: "*"
 //  - TokenTypeSymbolSlash: "/"
 //  - TokenTypeSymbolPercent: "%"
+//  - TokenTypeSymbolStarStar: "**"
 //  - TokenTypeKeywordVar: "var"
 //  - TokenTypeKeywordLet: "let"
 //  - TokenTypeKeywordVal: "val"
@@ -236,6 +305,20 @@ func (token Token) Debug() {
 //  - TokenTypeKeywordWhile: "while"
 //  - TokenTypeKeywordExport: "export"
 //  - TokenTypeKeywordIn: "in"
+//  - TokenTypeKeywordType: "type"
+//  - TokenTypeKeywordEnum: "enum"
+//  - TokenTypeKeywordInterface: "interface"
+//  - TokenTypeKeywordReturn: "return"
+//  - TokenTypeKeywordThrow: "throw"
+//  - TokenTypeKeywordTry: "try"
+//  - TokenTypeKeywordCatch: "catch"
+//  - TokenTypeKeywordFinally: "finally"
+//  - TokenTypeKeywordSpawn: "spawn"
+//  - TokenTypeKeywordChan: "chan"
+//  - TokenTypeKeywordSelect: "select"
+//  - TokenTypeKeywordTypeof: "typeof"
+//  - TokenTypeKeywordInstanceof: "instanceof"
+//  - TokenTypeKeywordIs: "is"
 
 func TokenKindString(kind TokenKind) string {
 	switch kind {
@@ -253,6 +336,14 @@ func TokenKindString(kind TokenKind) string {
 		return "string"
 	case TokenTypeValIdentifier:
 		return "identifier"
+	case TokenTypeValTemplateHead:
+		return "template_head"
+	case TokenTypeValTemplateMiddle:
+		return "template_middle"
+	case TokenTypeValTemplateTail:
+		return "template_tail"
+	case TokenTypeValRegex:
+		return "regex"
 	case TokenTypeSymbolLBracket:
 		return "["
 	case TokenTypeSymbolRBracket:
@@ -345,6 +436,8 @@ func TokenKindString(kind TokenKind) string {
 		return "/"
 	case TokenTypeSymbolPercent:
 		return "%"
+	case TokenTypeSymbolStarStar:
+		return "**"
 	case TokenTypeKeywordVar:
 		return "var"
 	case TokenTypeKeywordLet:
@@ -395,13 +488,41 @@ func TokenKindString(kind TokenKind) string {
 		return "while"
 	case TokenTypeKeywordExport:
 		return "export"
+	case TokenTypeKeywordType:
+		return "type"
+	case TokenTypeKeywordEnum:
+		return "enum"
+	case TokenTypeKeywordInterface:
+		return "interface"
+	case TokenTypeKeywordReturn:
+		return "return"
+	case TokenTypeKeywordThrow:
+		return "throw"
+	case TokenTypeKeywordTry:
+		return "try"
+	case TokenTypeKeywordCatch:
+		return "catch"
+	case TokenTypeKeywordFinally:
+		return "finally"
+	case TokenTypeKeywordSpawn:
+		return "spawn"
+	case TokenTypeKeywordChan:
+		return "chan"
+	case TokenTypeKeywordSelect:
+		return "select"
+	case TokenTypeKeywordTypeof:
+		return "typeof"
+	case TokenTypeKeywordInstanceof:
+		return "instanceof"
+	case TokenTypeKeywordIs:
+		return "is"
 	default:
 		return fmt.Sprintf("unknown(%d)", kind)
 	}
 }
 
-func newUniqueToken(kind TokenKind, value string) Token {
+func newUniqueToken(kind TokenKind, value string, span Span) Token {
 	return Token{
-		kind, value,
+		kind, value, span,
 	}
 }
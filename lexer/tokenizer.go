@@ -4,40 +4,66 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+const byteOrderMark = '\uFEFF'
+
 var (
-	hexChar      = make(map[byte]bool)
-	specialChar  = make(map[byte]bool)
-	reservedChar = make(map[byte]bool)
+	hexChar      = make(map[rune]bool)
+	specialChar  = make(map[rune]bool)
+	reservedChar = make(map[rune]bool)
 )
 
 func init() {
 	for i := '0'; i <= '9'; i++ {
-		hexChar[byte(i)] = true
+		hexChar[i] = true
 	}
 	for i := 'a'; i <= 'f'; i++ {
-		hexChar[byte(i)] = true
+		hexChar[i] = true
 	}
 	for i := 'A'; i <= 'F'; i++ {
-		hexChar[byte(i)] = true
+		hexChar[i] = true
 	}
 
-	specialChars := "+-*/^%<>=!&|()[]{}.,;:\n\"'`"
-	for i := 0; i < len(specialChars); i++ {
-		specialChar[specialChars[i]] = true
+	for _, c := range "+-*/^%<>=!&|~?()[]{}.,;:\n\"'`" {
+		specialChar[c] = true
 	}
 
-	reservedChars := "#@$"
-	for i := 0; i < len(reservedChars); i++ {
-		reservedChar[reservedChars[i]] = true
+	for _, c := range "#@$" {
+		reservedChar[c] = true
 	}
 }
 
+// templateFrame 记录一个正在插值中的模板字符串：Depth 为插值表达式内尚未闭合的 "{" 数量，
+// Start 为模板字符串起始反引号的位置，用于报告未闭合的模板字符串。
+type templateFrame struct {
+	Depth int
+	Start Position
+}
+
+// Lexer 以 rune 为单位扫描源代码。index 为当前 rune 的下标，
+// offset、column 和 runeColumn 分别记录当前位置的字节偏移、字节列号和 rune 列号，
+// last 为上一个标记的类型，用于区分除法运算符和正则表达式字面量。
 type Lexer struct {
-	input []byte
-	index int
-	line  int
+	input      []rune
+	index      int
+	offset     int
+	line       int
+	column     int
+	runeColumn int
+	templates  []templateFrame
+	last       TokenKind
+}
+
+// LexError 表示词法分析阶段的错误，Span 指出出错的源代码区间。
+type LexError struct {
+	Message string
+	Span    Span
+}
+
+func (e *LexError) Error() string {
+	return fmt.Sprintf("%s at %s", e.Message, e.Span)
 }
 
 func isWhitespace(ch rune) bool {
@@ -48,19 +74,50 @@ func isDigit(ch rune) bool {
 	return ch >= '0' && ch <= '9'
 }
 
+// isLetter 判断 ch 能否作为标识符的首字符，遵循 UAX #31 的 ID_Start 定义，并额外允许 '_'。
+//
+// ID_Start = L + Nl + Other_ID_Start - Pattern_Syntax - Pattern_White_Space
 func isLetter(ch rune) bool {
-	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+	if ch < utf8.RuneSelf {
+		return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+	}
+
+	return unicode.In(ch, unicode.Letter, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.In(ch, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
 }
 
+// isIdentContinue 判断 ch 能否出现在标识符的非首位置，遵循 UAX #31 的 ID_Continue 定义。
+//
+// ID_Continue = ID_Start + Mn + Mc + Nd + Pc + Other_ID_Continue - Pattern_Syntax - Pattern_White_Space
+func isIdentContinue(ch rune) bool {
+	if ch < utf8.RuneSelf {
+		return isLetter(ch) || isDigit(ch)
+	}
+
+	return isLetter(ch) || (unicode.In(ch, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.In(ch, unicode.Pattern_Syntax, unicode.Pattern_White_Space))
+}
+
+// NewLexer 创建一个词法分析器。输入按 UTF-8 解码为 rune，开头的字节顺序标记（BOM）会被跳过：
+// 它计入 Offset，但不计入第一行的列号，与编辑器的显示保持一致。
 func NewLexer(input string) *Lexer {
-	return &Lexer{
-		input: []byte(input),
-		index: 0,
-		line:  1,
+	l := &Lexer{
+		input:      []rune(input),
+		index:      0,
+		line:       1,
+		column:     1,
+		runeColumn: 1,
 	}
+
+	if len(l.input) > 0 && l.input[0] == byteOrderMark {
+		l.index = 1
+		l.offset = utf8.RuneLen(byteOrderMark)
+	}
+
+	return l
 }
 
-func (l *Lexer) nextChar() *byte {
+func (l *Lexer) nextChar() *rune {
 	if l.index+1 < len(l.input) {
 		return &l.input[l.index+1]
 	}
@@ -77,43 +134,43 @@ func (l *Lexer) checkNumber(i int) (string, error) {
 	var sb strings.Builder
 	c := l.input[i]
 	if c == '-' {
-		sb.WriteByte(c)
+		sb.WriteRune(c)
 		i++
-	} else if !unicode.IsDigit(rune(c)) {
+	} else if !isDigit(c) {
 		return "", fmt.Errorf("not number at line %d", l.line)
 	}
-	sb.WriteByte(c)
+	sb.WriteRune(c)
 	i++
-	for i < len(l.input) && !(unicode.IsSpace(rune(l.input[i])) || specialChar[l.input[i]]) {
+	for i < len(l.input) && !(unicode.IsSpace(l.input[i]) || (specialChar[l.input[i]] && !l.isDecimalPoint(i, dot))) {
 		c = l.input[i]
-		if unicode.IsDigit(rune(c)) {
-			sb.WriteByte(c)
+		if isDigit(c) {
+			sb.WriteRune(c)
 		} else if c == '.' && dot == 0 {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			dot++
 		} else if c == 'x' && sb.Len() == 1 && sb.String()[0] == '0' {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			isHex = true
 		} else if c == 'b' && sb.Len() == 1 && sb.String()[0] == '0' {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			isBin = true
 		} else if c == 'd' && sb.Len() == 1 && sb.String()[0] == '0' {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			isDec = true
 		} else if c != 'b' && c != 'd' && sb.Len() == 1 && sb.String()[0] == '0' {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			isOct = true
 		} else if !isE && (c == 'e' || c == 'E') {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 			isE = true
 		} else if isHex && hexChar[c] {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 		} else if isBin && (c == '0' || c == '1') {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 		} else if isOct && (c >= '0' && c <= '7') {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 		} else if isDec && (c >= '0' && c <= '9') {
-			sb.WriteByte(c)
+			sb.WriteRune(c)
 		} else {
 			return "", fmt.Errorf("wrong number token at line %d", l.line)
 		}
@@ -122,17 +179,21 @@ func (l *Lexer) checkNumber(i int) (string, error) {
 	return sb.String(), nil
 }
 
+// isDecimalPoint 判断位置 i 上的 "." 是否为小数点，而不是成员访问或区间运算符 ".."。
+func (l *Lexer) isDecimalPoint(i int, dot int) bool {
+	return dot == 0 && l.input[i] == '.' && i+1 < len(l.input) && isDigit(l.input[i+1])
+}
+
 func (l *Lexer) checkIdent(i int) (string, error) {
 	var sb strings.Builder
 	c := l.input[i]
-	if reservedChar[c] {
+	if reservedChar[c] || !isLetter(c) {
 		return "", fmt.Errorf("syntax error at line %d", l.line)
 	}
-	sb.WriteByte(c)
+	sb.WriteRune(c)
 	i++
-	for i < len(l.input) && !(unicode.IsSpace(rune(l.input[i])) || specialChar[l.input[i]]) {
-		c = l.input[i]
-		sb.WriteByte(c)
+	for i < len(l.input) && isIdentContinue(l.input[i]) {
+		sb.WriteRune(l.input[i])
 		i++
 	}
 	return sb.String(), nil
@@ -156,8 +217,211 @@ func (l *Lexer) checkExgesis(i int) string {
 			sb.WriteString("*/")
 			break
 		}
-		sb.WriteByte(c)
+		sb.WriteRune(c)
 		i++
 	}
 	return sb.String()
 }
+
+type symbolPattern struct {
+	text string
+	kind TokenKind
+}
+
+// symbolPatterns 按长度从长到短排列，保证最长匹配优先。
+var symbolPatterns = []symbolPattern{
+	{"...", TokenTypeSymbolVarargs},
+	{"==", TokenTypeSymbolEqual},
+	{"**", TokenTypeSymbolStarStar},
+	{"!=", TokenTypeSymbolNotEqual},
+	{"<=", TokenTypeSymbolLTEQ},
+	{">=", TokenTypeSymbolGTEQ},
+	{"||", TokenTypeSymbolOr},
+	{"&&", TokenTypeSymbolAnd},
+	{"^^", TokenTypeSymbolXor},
+	{"^!", TokenTypeSymbolXorNot},
+	{"^~", TokenTypeSymbolBitXorNot},
+	{"<<", TokenTypeSymbolLShift},
+	{">>", TokenTypeSymbolRShift},
+	{"<-", TokenTypeSymbolLArrow},
+	{"->", TokenTypeSymbolRArrow},
+	{"..", TokenTypeSymbolConcat},
+	{"++", TokenTypeSymbolPlusPlus},
+	{"--", TokenTypeSymbolMinusMinus},
+	{"+=", TokenTypeSymbolPlusEqual},
+	{"-=", TokenTypeSymbolDashEqual},
+	{"*=", TokenTypeSymbolStarEqual},
+	{"/=", TokenTypeSymbolSlashEqual},
+	{"%=", TokenTypeSymbolPercentEqual},
+	{"[", TokenTypeSymbolLBracket},
+	{"]", TokenTypeSymbolRBracket},
+	{"{", TokenTypeSymbolLBrance},
+	{"}", TokenTypeSymbolRBrance},
+	{"(", TokenTypeSymbolLParen},
+	{")", TokenTypeSymbolRParen},
+	{"=", TokenTypeSymbolAssignment},
+	{"<", TokenTypeSymbolLT},
+	{">", TokenTypeSymbolGT},
+	{"!", TokenTypeSymbolNot},
+	{"|", TokenTypeSymbolBitOr},
+	{"&", TokenTypeSymbolBitAnd},
+	{"~", TokenTypeSymbolBitNot},
+	{"^", TokenTypeSymbolBitXor},
+	{".", TokenTypeSymbolDot},
+	{";", TokenTypeSymbolSemiColon},
+	{":", TokenTypeSymbolColon},
+	{"?", TokenTypeSymbolQuestion},
+	{",", TokenTypeSymbolComma},
+	{"+", TokenTypeSymbolPlus},
+	{"-", TokenTypeSymbolDash},
+	{"*", TokenTypeSymbolStar},
+	{"/", TokenTypeSymbolSlash},
+	{"%", TokenTypeSymbolPercent},
+}
+
+// Tokenize 将源代码字符串转换为标记列表，列表总是以 TokenTypeEOF 结尾。
+//
+// 参数:
+//   - source: 要进行词法分析的源代码。
+//
+// 返回值:
+//   - []Token: 按出现顺序排列的标记列表，每个标记都带有其在源代码中的 Span。
+//
+// 注意:
+//   - 遇到非法字符、非法数字、未知的转义序列或未闭合的字符串时，会以 *LexError 触发 panic。
+func Tokenize(source string) []Token {
+	l := NewLexer(source)
+	tokens := make([]Token, 0)
+
+	for {
+		token := l.nextToken()
+		tokens = append(tokens, token)
+		l.last = token.Kind
+
+		if token.Kind == TokenTypeEOF {
+			return tokens
+		}
+	}
+}
+
+func (l *Lexer) position() Position {
+	return Position{Offset: l.offset, Line: l.line, Column: l.column, RuneColumn: l.runeColumn}
+}
+
+func (l *Lexer) peek(offset int) rune {
+	if l.index+offset < len(l.input) {
+		return l.input[l.index+offset]
+	}
+	return 0
+}
+
+// advance 向前移动 n 个 rune，同时更新字节偏移、行号和两种列号。
+func (l *Lexer) advance(n int) {
+	for ; n > 0 && l.index < len(l.input); n-- {
+		c := l.input[l.index]
+		width := utf8.RuneLen(c)
+		if width < 0 {
+			width = 1
+		}
+
+		if c == '\n' {
+			l.line++
+			l.column = 1
+			l.runeColumn = 1
+		} else {
+			l.column += width
+			l.runeColumn++
+		}
+		l.offset += width
+		l.index++
+	}
+}
+
+func (l *Lexer) token(kind TokenKind, value string, start Position) Token {
+	return newUniqueToken(kind, value, Span{Start: start, End: l.position()})
+}
+
+func (l *Lexer) errorf(start Position, format string, args ...any) *LexError {
+	return &LexError{
+		Message: fmt.Sprintf(format, args...),
+		Span:    Span{Start: start, End: l.position()},
+	}
+}
+
+func (l *Lexer) skipWhitespaceAndComments() {
+	for l.index < len(l.input) {
+		c := l.input[l.index]
+		if isWhitespace(c) {
+			l.advance(1)
+		} else if c == '/' && (l.peek(1) == '/' || l.peek(1) == '*') {
+			l.advance(utf8.RuneCountInString(l.checkExgesis(l.index)))
+		} else {
+			return
+		}
+	}
+}
+
+func (l *Lexer) nextToken() Token {
+	l.skipWhitespaceAndComments()
+	start := l.position()
+
+	if l.index >= len(l.input) {
+		if len(l.templates) > 0 {
+			frame := l.templates[len(l.templates)-1]
+			panic(l.errorf(frame.Start, "unterminated template string"))
+		}
+		return l.token(TokenTypeEOF, "", start)
+	}
+
+	c := l.input[l.index]
+	switch {
+	case isDigit(c):
+		value, err := l.checkNumber(l.index)
+		if err != nil {
+			panic(l.errorf(start, "%s", err.Error()))
+		}
+		l.advance(len(value))
+		return l.token(TokenTypeValNumber, value, start)
+	case isLetter(c):
+		value, err := l.checkIdent(l.index)
+		if err != nil {
+			panic(l.errorf(start, "%s", err.Error()))
+		}
+		l.advance(utf8.RuneCountInString(value))
+		if kind, reserved := reserved_lu[value]; reserved {
+			return l.token(kind, value, start)
+		}
+		return l.token(TokenTypeValIdentifier, value, start)
+	case c == '"' || c == '\'':
+		return l.scanString(c, start)
+	case c == '`':
+		l.advance(1)
+		return l.scanTemplate(start, start, true)
+	case c == '/' && l.regexAllowed():
+		return l.scanRegex(start)
+	}
+
+	if len(l.templates) > 0 {
+		frame := &l.templates[len(l.templates)-1]
+		if c == '{' {
+			frame.Depth++
+		} else if c == '}' {
+			if frame.Depth == 0 {
+				l.templates = l.templates[:len(l.templates)-1]
+				l.advance(1)
+				return l.scanTemplate(start, frame.Start, false)
+			}
+			frame.Depth--
+		}
+	}
+
+	for _, pattern := range symbolPatterns {
+		if strings.HasPrefix(string(l.input[l.index:min(l.index+len(pattern.text), len(l.input))]), pattern.text) {
+			l.advance(len(pattern.text))
+			return l.token(pattern.kind, pattern.text, start)
+		}
+	}
+
+	l.advance(1)
+	panic(l.errorf(start, "syntax error: unexpected character %q at line %d", c, start.Line))
+}
@@ -0,0 +1,147 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"dreamlang/lexer"
+)
+
+func TestIdentifiers(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"ascii", "foo _bar baz9", []string{"foo", "_bar", "baz9"}},
+		{"chinese", "用户 名字2", []string{"用户", "名字2"}},
+		{"accented letters", "café naïve", []string{"café", "naïve"}},
+		{"combining mark", "e\u0301x", []string{"e\u0301x"}},
+		{"greek and cyrillic", "αβγ имя", []string{"αβγ", "имя"}},
+		{"letter number", "Ⅻ", []string{"Ⅻ"}},
+		{"non-ascii digits continue an identifier", "x٣", []string{"x٣"}},
+		{"followed by punctuation", "名字.长度", []string{"名字", ".", "长度"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := mustTokenize(t, test.source)
+			got := make([]string, len(tokens))
+			for i, token := range tokens {
+				got[i] = token.Value
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("got %q, want %q", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidIdentifierStart(t *testing.T) {
+	for _, source := range []string{"٣x", "\u0301", "€", "→"} {
+		t.Run(source, func(t *testing.T) {
+			if _, err := tokenize(t, source); err == nil {
+				t.Fatalf("Tokenize(%q) succeeded, want an error", source)
+			}
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		value  string
+		start  lexer.Position
+		end    lexer.Position
+	}{
+		{
+			name:   "ascii",
+			source: "let x",
+			value:  "x",
+			start:  lexer.Position{Offset: 4, Line: 1, Column: 5, RuneColumn: 5},
+			end:    lexer.Position{Offset: 5, Line: 1, Column: 6, RuneColumn: 6},
+		},
+		{
+			name:   "after multi-byte identifier",
+			source: "名字 = x",
+			value:  "x",
+			start:  lexer.Position{Offset: 9, Line: 1, Column: 10, RuneColumn: 6},
+			end:    lexer.Position{Offset: 10, Line: 1, Column: 11, RuneColumn: 7},
+		},
+		{
+			name:   "multi-byte identifier",
+			source: "a 名字",
+			value:  "名字",
+			start:  lexer.Position{Offset: 2, Line: 1, Column: 3, RuneColumn: 3},
+			end:    lexer.Position{Offset: 8, Line: 1, Column: 9, RuneColumn: 5},
+		},
+		{
+			name:   "after a comment",
+			source: "// 注释\n  y",
+			value:  "y",
+			start:  lexer.Position{Offset: 12, Line: 2, Column: 3, RuneColumn: 3},
+			end:    lexer.Position{Offset: 13, Line: 2, Column: 4, RuneColumn: 4},
+		},
+		{
+			name:   "string with multi-byte content",
+			source: `"中" z`,
+			value:  "z",
+			start:  lexer.Position{Offset: 6, Line: 1, Column: 7, RuneColumn: 5},
+			end:    lexer.Position{Offset: 7, Line: 1, Column: 8, RuneColumn: 6},
+		},
+		{
+			name:   "byte order mark",
+			source: "\ufefflet",
+			value:  "let",
+			start:  lexer.Position{Offset: 3, Line: 1, Column: 1, RuneColumn: 1},
+			end:    lexer.Position{Offset: 6, Line: 1, Column: 4, RuneColumn: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var found bool
+			for _, token := range mustTokenize(t, test.source) {
+				if token.Value != test.value {
+					continue
+				}
+				found = true
+				if token.Span.Start != test.start || token.Span.End != test.end {
+					t.Fatalf("span of %q = %+v-%+v, want %+v-%+v", test.value, token.Span.Start, token.Span.End, test.start, test.end)
+				}
+			}
+			if !found {
+				t.Fatalf("no token %q in %q", test.value, test.source)
+			}
+		})
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	t.Run("empty source", func(t *testing.T) {
+		if tokens := mustTokenize(t, "\ufeff"); len(tokens) != 0 {
+			t.Fatalf("tokens = %v, want none", tokens)
+		}
+	})
+
+	t.Run("only at the start", func(t *testing.T) {
+		if _, err := tokenize(t, "a \ufeff"); err == nil {
+			t.Fatal("a byte order mark after the start was accepted")
+		}
+	})
+}
+
+func TestSpanString(t *testing.T) {
+	_, err := tokenize(t, "名字 = \"abc")
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	if got, want := err.Error(), "unterminated string literal at 1:6-1:10"; got != want {
+		t.Fatalf("error = %q, want %q", got, want)
+	}
+}
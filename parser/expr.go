@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"dreamlang/ast"
 	"dreamlang/lexer"
@@ -58,12 +59,24 @@ func parse_prefix_expr(p *parser) ast.Expr {
 	}
 }
 
+// parse_type_test_expr 解析 value is Type 和 value instanceof Type，右侧是类型而不是表达式。
+func parse_type_test_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
+	operatorToken := p.advance()
+
+	return ast.TypeTestExpr{
+		Value:    left,
+		Operator: operatorToken,
+		Type:     parse_type(p, defalt_bp),
+	}
+}
+
 func parse_assignment_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
-	p.advance()
+	operatorToken := p.advance()
 	rhs := parse_expr(p, bp)
 
 	return ast.AssignmentExpr{
 		Assigne:       left,
+		Operator:      operatorToken,
 		AssignedValue: rhs,
 	}
 }
@@ -77,9 +90,22 @@ func parse_range_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
 	}
 }
 
+// parse_binary_expr 解析二元运算。右操作数只包含优先级更高的运算，因此运算符左结合，例如 a - b - c 等价于 (a - b) - c。
 func parse_binary_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
 	operatorToken := p.advance()
-	right := parse_expr(p, defalt_bp)
+	right := parse_expr(p, bp_lu[operatorToken.Kind])
+
+	return ast.BinaryExpr{
+		Left:     left,
+		Operator: operatorToken,
+		Right:    right,
+	}
+}
+
+// parse_exponent_expr 解析右结合的乘方 a ** b：右操作数以低一级的绑定优先级解析，使其可以继续包含 **。
+func parse_exponent_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
+	operatorToken := p.advance()
+	right := parse_expr(p, exponent-1)
 
 	return ast.BinaryExpr{
 		Left:     left,
@@ -90,89 +116,318 @@ func parse_binary_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
 
 func parse_primary_expr(p *parser) ast.Expr {
 	switch p.currentTokenKind() {
-	case lexer.NUMBER:
+	case lexer.TokenTypeValNumber:
 		number, _ := strconv.ParseFloat(p.advance().Value, 64)
 		return ast.NumberExpr{
 			Value: number,
 		}
-	case lexer.STRING:
+	case lexer.TokenTypeValString:
 		return ast.StringExpr{
 			Value: p.advance().Value,
 		}
-	case lexer.IDENTIFIER:
-		return ast.SymbolExpr{
+	case lexer.TokenTypeValIdentifier:
+		if p.nextToken().Kind == lexer.TokenTypeSymbolRArrow {
+			return parse_lambda_expr(p)
+		}
+		symbol := ast.SymbolExpr{
 			Value: p.advance().Value,
 		}
+		if p.currentTokenKind() == lexer.TokenTypeSymbolLT {
+			if typeArguments, ok := parse_type_arguments(p); ok {
+				return parse_generic_call_expr(p, symbol, typeArguments)
+			}
+		}
+		return symbol
+	case lexer.TokenTypeValTrue, lexer.TokenTypeValFalse:
+		return ast.BooleanExpr{
+			Value: p.advance().Kind == lexer.TokenTypeValTrue,
+		}
+	case lexer.TokenTypeValNull:
+		p.advance()
+		return ast.NullExpr{}
+	case lexer.TokenTypeValRegex:
+		literal := p.advance().Value
+		slash := strings.LastIndexByte(literal, '/')
+		return ast.RegexExpr{
+			Pattern: literal[:slash],
+			Flags:   literal[slash+1:],
+		}
 	default:
 		panic(fmt.Sprintf("Cannot create primary_expr from %s\n", lexer.TokenKindString(p.currentTokenKind())))
 	}
 }
 
 func parse_member_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
-	isComputed := p.advance().Kind == lexer.OPEN_BRACKET
+	isComputed := p.advance().Kind == lexer.TokenTypeSymbolLBracket
 
 	if isComputed {
 		rhs := parse_expr(p, bp)
-		p.expect(lexer.CLOSE_BRACKET)
+		p.expect(lexer.TokenTypeSymbolRBracket)
 		return ast.ComputedExpr{
 			Member:   left,
 			Property: rhs,
 		}
 	}
 
-	return ast.MemberExpr{
+	member := ast.MemberExpr{
 		Member:   left,
-		Property: p.expect(lexer.IDENTIFIER).Value,
+		Property: p.expect(lexer.TokenTypeValIdentifier).Value,
+	}
+	if p.currentTokenKind() == lexer.TokenTypeSymbolLT {
+		if typeArguments, ok := parse_type_arguments(p); ok {
+			return parse_generic_call_expr(p, member, typeArguments)
+		}
 	}
+	return member
 }
 
 func parse_array_literal_expr(p *parser) ast.Expr {
-	p.expect(lexer.OPEN_BRACKET)
+	p.expect(lexer.TokenTypeSymbolLBracket)
 	arrayContents := make([]ast.Expr, 0)
 
-	for p.hasTokens() && p.currentTokenKind() != lexer.CLOSE_BRACKET {
-		arrayContents = append(arrayContents, parse_expr(p, logical))
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBracket {
+		arrayContents = append(arrayContents, parse_expr(p, assignment))
 
-		if !p.currentToken().IsOneOfMany(lexer.EOF, lexer.CLOSE_BRACKET) {
-			p.expect(lexer.COMMA)
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeEOF, lexer.TokenTypeSymbolRBracket) {
+			p.expect(lexer.TokenTypeSymbolComma)
 		}
 	}
 
-	p.expect(lexer.CLOSE_BRACKET)
+	p.expect(lexer.TokenTypeSymbolRBracket)
 
 	return ast.ArrayLiteral{
 		Contents: arrayContents,
 	}
 }
 
+// parse_map_literal_expr 解析字典字面量，例如 { key: value, "quoted": v, [computed]: v }。
+//
+// "{" 只有出现在表达式位置（例如赋值右侧、调用参数）时才会进入该函数；
+// 出现在语句开头时，parse_stmt 会优先把它当作 ast.BlockStmt 解析。
+//
+// 键的三种写法：
+//   - 标识符 key 与字符串 "quoted" 都解析为 ast.StringExpr；
+//   - 数字键解析为 ast.NumberExpr；
+//   - [expr] 为计算键，保存 expr 本身，在运行时求值。
+func parse_map_literal_expr(p *parser) ast.Expr {
+	p.expect(lexer.TokenTypeSymbolLBrance)
+	entries := make([]ast.MapEntry, 0)
+
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		var key ast.Expr
+
+		switch p.currentTokenKind() {
+		case lexer.TokenTypeValIdentifier, lexer.TokenTypeValString:
+			key = ast.StringExpr{Value: p.advance().Value}
+		case lexer.TokenTypeValNumber:
+			key = parse_primary_expr(p)
+		case lexer.TokenTypeSymbolLBracket:
+			p.advance()
+			key = parse_expr(p, defalt_bp)
+			p.expect(lexer.TokenTypeSymbolRBracket)
+		default:
+			panic(fmt.Sprintf("Expected map key but recieved %s instead\n", lexer.TokenKindString(p.currentTokenKind())))
+		}
+
+		p.expect(lexer.TokenTypeSymbolColon)
+		entries = append(entries, ast.MapEntry{
+			Key:   key,
+			Value: parse_expr(p, assignment),
+		})
+
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeEOF, lexer.TokenTypeSymbolRBrance) {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+
+	p.expect(lexer.TokenTypeSymbolRBrance)
+
+	return ast.MapLiteral{
+		Entries: entries,
+	}
+}
+
 func parse_grouping_expr(p *parser) ast.Expr {
-	p.expect(lexer.OPEN_PAREN)
+	if p.isLambdaAhead() {
+		return parse_lambda_expr(p)
+	}
+
+	p.expect(lexer.TokenTypeSymbolLParen)
 	expr := parse_expr(p, defalt_bp)
-	p.expect(lexer.OPEN_PAREN)
+	p.expect(lexer.TokenTypeSymbolRParen)
 	return expr
 }
 
+// parse_call_expr 解析函数调用的实参列表。
+//
+// 实参可以是普通表达式、展开实参 ...xs 或命名实参 name: value；命名实参必须写在全部位置实参之后。
 func parse_call_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
 	p.advance()
 	arguments := make([]ast.Expr, 0)
+	namedArguments := make([]ast.NamedArgument, 0)
+
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+		if p.currentTokenKind() == lexer.TokenTypeValIdentifier && p.nextToken().Kind == lexer.TokenTypeSymbolColon {
+			name := p.advance().Value
+			p.advance()
+			namedArguments = append(namedArguments, ast.NamedArgument{
+				Name:  name,
+				Value: parse_expr(p, assignment),
+			})
+		} else if len(namedArguments) > 0 {
+			panic("Positional argument cannot follow named arguments.")
+		} else if p.currentTokenKind() == lexer.TokenTypeSymbolVarargs {
+			p.advance()
+			arguments = append(arguments, ast.SpreadExpr{Argument: parse_expr(p, assignment)})
+		} else {
+			arguments = append(arguments, parse_expr(p, assignment))
+		}
 
-	for p.hasTokens() && p.currentTokenKind() != lexer.CLOSE_PAREN {
-		arguments = append(arguments, parse_expr(p, assignment))
-
-		if !p.currentToken().IsOneOfMany(lexer.EOF, lexer.CLOSE_PAREN) {
-			p.expect(lexer.COMMA)
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeEOF, lexer.TokenTypeSymbolRParen) {
+			p.expect(lexer.TokenTypeSymbolComma)
 		}
 	}
 
-	p.expect(lexer.CLOSE_PAREN)
+	p.expect(lexer.TokenTypeSymbolRParen)
 	return ast.CallExpr{
-		Method:    left,
-		Arguments: arguments,
+		Method:         left,
+		Arguments:      arguments,
+		NamedArguments: namedArguments,
 	}
 }
 
+// parse_generic_call_expr 解析带显式类型实参的调用，例如 id<string>("a") 或 xs.map<string>(f)，
+// typeArguments 为已经由 parse_type_arguments 解析的类型实参，当前标记为实参列表开头的 "("。
+func parse_generic_call_expr(p *parser, callee ast.Expr, typeArguments []ast.Type) ast.Expr {
+	expr := ast.ExpectExpr[ast.CallExpr](parse_call_expr(p, callee, call))
+	expr.TypeArguments = typeArguments
+
+	return expr
+}
+
+// parse_new_expr 解析类的实例化表达式，例如 new Box(1) 或带显式类型实参的 new Box<number>(1)。
+// new 只作用于类名（可以是成员表达式）和紧随其后的实参列表，因此 new Box(1).value 中的 .value 作用于新创建的实例。
+func parse_new_expr(p *parser) ast.Expr {
+	p.advance()
+	var typeArguments []ast.Type
+
+	if p.currentTokenKind() == lexer.TokenTypeValIdentifier && p.nextToken().Kind == lexer.TokenTypeSymbolLT {
+		className := ast.SymbolExpr{Value: p.advance().Value}
+		typeArguments = parse_type_list(p, lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolGT)
+
+		if p.currentTokenKind() != lexer.TokenTypeSymbolLParen {
+			panic(fmt.Sprintf("Expected ( after type arguments of %s but recieved %s instead\n", className.Value, lexer.TokenKindString(p.currentTokenKind())))
+		}
+
+		return ast.NewExpr{
+			Instantiation: ast.ExpectExpr[ast.CallExpr](parse_call_expr(p, className, call)),
+			TypeArguments: typeArguments,
+		}
+	}
+
+	var class ast.Expr = ast.SymbolExpr{Value: p.expect(lexer.TokenTypeValIdentifier).Value}
+	for p.currentTokenKind() == lexer.TokenTypeSymbolDot {
+		p.advance()
+		class = ast.MemberExpr{Member: class, Property: p.expect(lexer.TokenTypeValIdentifier).Value}
+	}
+	if p.currentTokenKind() != lexer.TokenTypeSymbolLParen {
+		panic(fmt.Sprintf("Expected ( after class of new expression but recieved %s instead\n", lexer.TokenKindString(p.currentTokenKind())))
+	}
+
+	return ast.NewExpr{
+		Instantiation: ast.ExpectExpr[ast.CallExpr](parse_call_expr(p, class, call)),
+	}
+}
+
+// parse_channel_expr 解析通道的创建：chan<number>() 创建无缓冲通道，chan<number>(10) 创建容量为 10 的带缓冲通道。
+func parse_channel_expr(p *parser) ast.Expr {
+	p.advance()
+	typeArguments := parse_type_list(p, lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolGT)
+	if len(typeArguments) != 1 {
+		panic(fmt.Sprintf("Expected 1 type argument for chan but recieved %d instead\n", len(typeArguments)))
+	}
+
+	var capacity ast.Expr
+	p.expect(lexer.TokenTypeSymbolLParen)
+	if p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+		capacity = parse_expr(p, assignment)
+	}
+	p.expect(lexer.TokenTypeSymbolRParen)
+
+	return ast.ChannelExpr{
+		Element:  typeArguments[0],
+		Capacity: capacity,
+	}
+}
+
+func parse_receive_expr(p *parser) ast.Expr {
+	p.advance()
+
+	return ast.ReceiveExpr{
+		Channel: parse_expr(p, unary),
+	}
+}
+
+func parse_send_expr(p *parser, left ast.Expr, bp binding_power) ast.Expr {
+	p.advance()
+
+	return ast.SendExpr{
+		Channel: left,
+		Value:   parse_expr(p, assignment),
+	}
+}
+
+// parse_lambda_expr 解析箭头函数 x -> x * 2、(x, y) -> x + y 或 (x: number) -> { ... }。
+//
+// 参数的类型可以省略，由类型检查器根据期望的函数类型推断。箭头之后为 { 时函数体是代码块，
+// 否则函数体是一个表达式，相当于 { return 表达式; }。因此要返回字典字面量时需要加上括号：x -> ({"k": x})。
+// 箭头函数不能声明返回类型，返回类型由函数体推断。
+func parse_lambda_expr(p *parser) ast.Expr {
+	var params []ast.Parameter
+	if p.currentTokenKind() == lexer.TokenTypeValIdentifier {
+		params = []ast.Parameter{{Pattern: ast.IdentifierPattern{Name: p.advance().Value}}}
+	} else {
+		params = parse_params(p, false)
+	}
+
+	p.expect(lexer.TokenTypeSymbolRArrow)
+
+	if p.currentTokenKind() == lexer.TokenTypeSymbolLBrance {
+		return ast.FunctionExpr{
+			Parameters: params,
+			Body:       ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body,
+		}
+	}
+
+	return ast.FunctionExpr{
+		Parameters: params,
+		Body:       []ast.Stmt{ast.ReturnStmt{Value: parse_expr(p, assignment)}},
+	}
+}
+
+// isLambdaAhead 判断从当前的 ( 开始是否为箭头函数的参数列表，即与之匹配的 ) 之后紧跟 ->。
+func (p *parser) isLambdaAhead() bool {
+	depth := 0
+
+	for i := p.pos; i < len(p.tokens); i++ {
+		switch p.tokens[i].Kind {
+		case lexer.TokenTypeSymbolLParen, lexer.TokenTypeSymbolLBracket, lexer.TokenTypeSymbolLBrance:
+			depth++
+		case lexer.TokenTypeSymbolRParen, lexer.TokenTypeSymbolRBracket, lexer.TokenTypeSymbolRBrance:
+			depth--
+			if depth == 0 {
+				return i+1 < len(p.tokens) && p.tokens[i+1].Kind == lexer.TokenTypeSymbolRArrow
+			}
+		case lexer.TokenTypeEOF:
+			return false
+		}
+	}
+
+	return false
+}
+
 func parse_fn_expr(p *parser) ast.Expr {
-	p.expect(lexer.FN)
+	p.expect(lexer.TokenTypeKeywordFunc)
 	functionParams, returnType, functionBody := parse_fn_params_and_body(p)
 
 	return ast.FunctionExpr{
@@ -181,3 +436,37 @@ func parse_fn_expr(p *parser) ast.Expr {
 		Body:       functionBody,
 	}
 }
+
+// parse_template_expr 解析模板字符串，例如 `Hello ${user.name}!`。
+//
+// 词法分析器会把模板字符串拆分为 TemplateHead、若干 TemplateMiddle 和 TemplateTail 标记，
+// 插值表达式的标记夹在它们之间。该函数依次收集字面量片段和插值表达式，
+// 返回的 ast.TemplateExpr 中字面量片段为 ast.StringExpr，空的字面量片段会被省略。
+func parse_template_expr(p *parser) ast.Expr {
+	parts := make([]ast.Expr, 0)
+	head := p.expect(lexer.TokenTypeValTemplateHead)
+
+	if head.Value != "" {
+		parts = append(parts, ast.StringExpr{Value: head.Value})
+	}
+
+	for {
+		parts = append(parts, parse_expr(p, defalt_bp))
+		section := p.currentToken()
+
+		if !section.IsOneOfMany(lexer.TokenTypeValTemplateMiddle, lexer.TokenTypeValTemplateTail) {
+			panic(fmt.Sprintf("Expected end of template interpolation but recieved %s instead\n", lexer.TokenKindString(section.Kind)))
+		}
+
+		p.advance()
+		if section.Value != "" {
+			parts = append(parts, ast.StringExpr{Value: section.Value})
+		}
+
+		if section.Kind == lexer.TokenTypeValTemplateTail {
+			return ast.TemplateExpr{
+				Parts: parts,
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package parser_test
+
+import (
+	"reflect"
+	"testing"
+
+	"dreamlang/ast"
+	"dreamlang/parser"
+)
+
+func TestGenericCalls(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`id<string>("a");`, `id<string>("a")`},
+		{`pair<string, []number>(a, b);`, `pair<string, []number>(a, b)`},
+		{`id<Box<number>>(x);`, `id<Box<number>>(x)`},
+		{`id<string | null>(x);`, `id<string | null>(x)`},
+		{`xs.map<string>(f);`, `xs.map<string>(f)`},
+		{`id<string>("a").length;`, `id<string>("a").length`},
+		{`a + id<number>(1);`, `(+ a id<number>(1))`},
+		{`a < b;`, `(< a b)`},
+		{`a < b + c;`, `(< a (+ b c))`},
+		{`a < b > c;`, `(> (< a b) c)`},
+		{`f(a < b, c > d);`, `f((< a b), (> c d))`},
+		{`a < f(b) > (c);`, `(> (< a f(b)) c)`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			if got := parseExpr(t, test.source); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGenericDeclarations(t *testing.T) {
+	body := parser.Parse(`
+		fn first<T, U: Comparable>(xs: []T, u: U): T { return xs[0]; }
+		class Box<T> { let value: T; }
+		new Box<number>(1);`).Body
+	if len(body) != 3 {
+		t.Fatalf("parsed %d statements, want 3", len(body))
+	}
+
+	fn := body[0].(ast.FunctionDeclarationStmt)
+	want := []ast.TypeParameter{{Name: "T"}, {Name: "U", Constraint: ast.SymbolType{Value: "Comparable"}}}
+	if !reflect.DeepEqual(fn.TypeParameters, want) {
+		t.Errorf("function type parameters = %#v, want %#v", fn.TypeParameters, want)
+	}
+	if got := showType(fn.Parameters[0].Type); got != "[]T" {
+		t.Errorf("first parameter type = %s, want []T", got)
+	}
+
+	class := body[1].(ast.ClassDeclarationStmt)
+	if !reflect.DeepEqual(class.TypeParameters, []ast.TypeParameter{{Name: "T"}}) {
+		t.Errorf("class type parameters = %#v, want T", class.TypeParameters)
+	}
+
+	instantiation := body[2].(ast.ExpressionStmt).Expression.(ast.NewExpr)
+	if got := showTypes(instantiation.TypeArguments); got != "number" {
+		t.Errorf("new type arguments = %s, want number", got)
+	}
+}
@@ -7,15 +7,19 @@ import (
 
 type binding_power int
 
+// 绑定优先级从低到高排列。二元运算符左结合，&& 的优先级高于 ||，因此 a || b && c 等价于 a || (b && c)。
+// 乘方 ** 右结合，优先级高于一元运算符，因此 2 ** 3 ** 2 等价于 2 ** (3 ** 2)，-2 ** 2 等价于 -(2 ** 2)。
 const (
 	defalt_bp binding_power = iota
 	comma
 	assignment
 	logical
+	conjunction
 	relational
 	additive
 	multiplicative
 	unary
+	exponent
 	call
 	member
 	primary
@@ -40,8 +44,12 @@ func led(kind lexer.TokenKind, bp binding_power, led_fn led_handler) {
 	led_lu[kind] = led_fn
 }
 
+// nud 注册前缀解析函数。标记同时是中缀运算符时保留 led 注册的绑定优先级，
+// 否则 a - b * c 中的 - 会以 primary 的优先级解析右操作数，得到 (a - b) * c。
 func nud(kind lexer.TokenKind, bp binding_power, nud_fn nud_handler) {
-	bp_lu[kind] = primary
+	if _, infix := led_lu[kind]; !infix {
+		bp_lu[kind] = primary
+	}
 	nud_lu[kind] = nud_fn
 }
 
@@ -55,122 +63,164 @@ func stmt(kind lexer.TokenKind, stmt_fn stmt_handler) {
 // 具体包括以下几类：
 //
 // 1. 赋值操作符：
-//   - lexer.ASSIGNMENT
-//   - lexer.PLUS_EQUALS
-//   - lexer.MINUS_EQUALS
+//   - lexer.TokenTypeSymbolAssignment
+//   - lexer.TokenTypeSymbolPlusEqual
+//   - lexer.TokenTypeSymbolDashEqual
+//   - lexer.TokenTypeSymbolStarEqual
+//   - lexer.TokenTypeSymbolSlashEqual
+//   - lexer.TokenTypeSymbolPercentEqual
+//   - lexer.TokenTypeSymbolLArrow（向通道发送 ch <- v，与赋值的绑定优先级相同）
 //
-// 2. 逻辑操作符：
-//   - lexer.AND
-//   - lexer.OR
-//   - lexer.DOT_DOT
+// 2. 逻辑操作符（&& 的优先级高于 ||）：
+//   - lexer.TokenTypeSymbolAnd
+//   - lexer.TokenTypeSymbolOr
+//   - lexer.TokenTypeSymbolConcat
 //
 // 3. 关系操作符：
-//   - lexer.LESS
-//   - lexer.LESS_EQUALS
-//   - lexer.GREATER
-//   - lexer.GREATER_EQUALS
-//   - lexer.EQUALS
-//   - lexer.NOT_EQUALS
+//   - lexer.TokenTypeSymbolLT
+//   - lexer.TokenTypeSymbolLTEQ
+//   - lexer.TokenTypeSymbolGT
+//   - lexer.TokenTypeSymbolGTEQ
+//   - lexer.TokenTypeSymbolEqual
+//   - lexer.TokenTypeSymbolNotEqual
+//   - lexer.TokenTypeKeywordIs、lexer.TokenTypeKeywordInstanceof（右侧是类型）
 //
-// 4. 加法和乘法操作符：
-//   - lexer.PLUS
-//   - lexer.DASH
-//   - lexer.SLASH
-//   - lexer.STAR
-//   - lexer.PERCENT
+// 4. 加法、乘法和乘方操作符：
+//   - lexer.TokenTypeSymbolPlus
+//   - lexer.TokenTypeSymbolDash
+//   - lexer.TokenTypeSymbolSlash
+//   - lexer.TokenTypeSymbolStar
+//   - lexer.TokenTypeSymbolPercent
+//   - lexer.TokenTypeSymbolStarStar（右结合）
 //
 // 5. 字面量和符号：
-//   - lexer.NUMBER
-//   - lexer.STRING
-//   - lexer.IDENTIFIER
+//   - lexer.TokenTypeValNumber
+//   - lexer.TokenTypeValString
+//   - lexer.TokenTypeValIdentifier
+//   - lexer.TokenTypeValTrue
+//   - lexer.TokenTypeValFalse
+//   - lexer.TokenTypeValNull
+//   - lexer.TokenTypeValTemplateHead
+//   - lexer.TokenTypeValRegex
 //
 // 6. 一元/前缀操作符：
-//   - lexer.TYPEOF
-//   - lexer.DASH
-//   - lexer.NOT
-//   - lexer.OPEN_BRACKET
+//   - lexer.TokenTypeSymbolDash
+//   - lexer.TokenTypeSymbolNot
+//   - lexer.TokenTypeSymbolLBracket
+//   - lexer.TokenTypeSymbolLBrance（表达式位置的字典字面量）
+//   - lexer.TokenTypeSymbolLArrow（从通道接收 <-ch）
+//   - lexer.TokenTypeKeywordTypeof
 //
 // 7. 成员/计算/调用操作符：
-//   - lexer.DOT
-//   - lexer.OPEN_BRACKET
-//   - lexer.OPEN_PAREN
+//   - lexer.TokenTypeSymbolDot
+//   - lexer.TokenTypeSymbolLBracket
+//   - lexer.TokenTypeSymbolLParen
 //
-// 8. 分组表达式：
-//   - lexer.OPEN_PAREN
-//   - lexer.FN
-//   - lexer.NEW
+// 8. 分组表达式和函数字面量：
+//   - lexer.TokenTypeSymbolLParen（匹配的 ) 之后为 -> 时为箭头函数）
+//   - lexer.TokenTypeKeywordFunc
+//   - lexer.TokenTypeKeywordNew
+//   - lexer.TokenTypeKeywordChan（创建通道 chan<T>(容量)）
 //
 // 9. 语句：
-//   - lexer.OPEN_CURLY
-//   - lexer.LET
-//   - lexer.CONST
-//   - lexer.FN
-//   - lexer.IF
-//   - lexer.IMPORT
-//   - lexer.FOREACH
-//   - lexer.CLASS
+//   - lexer.TokenTypeSymbolLBrance（语句位置的代码块，注册在字典字面量之后，因此其绑定优先级为 defalt_bp）
+//   - lexer.TokenTypeKeywordVar
+//   - lexer.TokenTypeKeywordLet
+//   - lexer.TokenTypeKeywordVal
+//   - lexer.TokenTypeKeywordFunc
+//   - lexer.TokenTypeKeywordIf
+//   - lexer.TokenTypeKeywordImport
+//   - lexer.TokenTypeKeywordForeach
+//   - lexer.TokenTypeKeywordClass
+//   - lexer.TokenTypeKeywordType
+//   - lexer.TokenTypeKeywordEnum
+//   - lexer.TokenTypeKeywordInterface
+//   - lexer.TokenTypeKeywordReturn
+//   - lexer.TokenTypeKeywordThrow
+//   - lexer.TokenTypeKeywordTry
+//   - lexer.TokenTypeKeywordSpawn
+//   - lexer.TokenTypeKeywordSelect
 //
 // 该函数通过调用 led、nud 和 stmt 函数来为每种令牌类型注册相应的解析函数。
 func createTokenLookups() {
 	// Assignment
-	led(lexer.ASSIGNMENT, assignment, parse_assignment_expr)
-	led(lexer.PLUS_EQUALS, assignment, parse_assignment_expr)
-	led(lexer.MINUS_EQUALS, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolAssignment, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolPlusEqual, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolDashEqual, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolStarEqual, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolSlashEqual, assignment, parse_assignment_expr)
+	led(lexer.TokenTypeSymbolPercentEqual, assignment, parse_assignment_expr)
 
 	// Logical
-	led(lexer.AND, logical, parse_binary_expr)
-	led(lexer.OR, logical, parse_binary_expr)
-	led(lexer.DOT_DOT, logical, parse_range_expr)
+	led(lexer.TokenTypeSymbolAnd, conjunction, parse_binary_expr)
+	led(lexer.TokenTypeSymbolOr, logical, parse_binary_expr)
+	led(lexer.TokenTypeSymbolConcat, logical, parse_range_expr)
 
 	// Relational
-	led(lexer.LESS, relational, parse_binary_expr)
-	led(lexer.LESS_EQUALS, relational, parse_binary_expr)
-	led(lexer.GREATER, relational, parse_binary_expr)
-	led(lexer.GREATER_EQUALS, relational, parse_binary_expr)
-	led(lexer.EQUALS, relational, parse_binary_expr)
-	led(lexer.NOT_EQUALS, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolLT, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolLTEQ, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolGT, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolGTEQ, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolEqual, relational, parse_binary_expr)
+	led(lexer.TokenTypeSymbolNotEqual, relational, parse_binary_expr)
+	led(lexer.TokenTypeKeywordIs, relational, parse_type_test_expr)
+	led(lexer.TokenTypeKeywordInstanceof, relational, parse_type_test_expr)
 
 	// Additive & Multiplicitave
-	led(lexer.PLUS, additive, parse_binary_expr)
-	led(lexer.DASH, additive, parse_binary_expr)
-	led(lexer.SLASH, multiplicative, parse_binary_expr)
-	led(lexer.STAR, multiplicative, parse_binary_expr)
-	led(lexer.PERCENT, multiplicative, parse_binary_expr)
+	led(lexer.TokenTypeSymbolPlus, additive, parse_binary_expr)
+	led(lexer.TokenTypeSymbolDash, additive, parse_binary_expr)
+	led(lexer.TokenTypeSymbolSlash, multiplicative, parse_binary_expr)
+	led(lexer.TokenTypeSymbolStar, multiplicative, parse_binary_expr)
+	led(lexer.TokenTypeSymbolPercent, multiplicative, parse_binary_expr)
+	led(lexer.TokenTypeSymbolStarStar, exponent, parse_exponent_expr)
 
 	// Literals & Symbols
-	nud(lexer.NUMBER, primary, parse_primary_expr)
-	nud(lexer.STRING, primary, parse_primary_expr)
-	nud(lexer.IDENTIFIER, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValNumber, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValString, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValIdentifier, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValTrue, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValFalse, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValNull, primary, parse_primary_expr)
+	nud(lexer.TokenTypeValTemplateHead, primary, parse_template_expr)
+	nud(lexer.TokenTypeValRegex, primary, parse_primary_expr)
 
 	// Unary/Prefix
-	nud(lexer.TYPEOF, unary, parse_prefix_expr)
-	nud(lexer.DASH, unary, parse_prefix_expr)
-	nud(lexer.NOT, unary, parse_prefix_expr)
-	nud(lexer.OPEN_BRACKET, primary, parse_array_literal_expr)
+	nud(lexer.TokenTypeSymbolDash, unary, parse_prefix_expr)
+	nud(lexer.TokenTypeSymbolNot, unary, parse_prefix_expr)
+	nud(lexer.TokenTypeKeywordTypeof, unary, parse_prefix_expr)
+	nud(lexer.TokenTypeSymbolLBracket, primary, parse_array_literal_expr)
+	nud(lexer.TokenTypeSymbolLBrance, primary, parse_map_literal_expr)
+	nud(lexer.TokenTypeSymbolLArrow, unary, parse_receive_expr)
+	// 注册在接收之后，使 <- 作为中缀运算符时的绑定优先级为 assignment 而不是 nud 设置的 primary。
+	led(lexer.TokenTypeSymbolLArrow, assignment, parse_send_expr)
 
 	// Member / Computed // Call
-	led(lexer.DOT, member, parse_member_expr)
-	led(lexer.OPEN_BRACKET, member, parse_member_expr)
-	led(lexer.OPEN_PAREN, call, parse_call_expr)
+	led(lexer.TokenTypeSymbolDot, member, parse_member_expr)
+	led(lexer.TokenTypeSymbolLBracket, member, parse_member_expr)
+	led(lexer.TokenTypeSymbolLParen, call, parse_call_expr)
 
 	// Grouping Expr
-	nud(lexer.OPEN_PAREN, defalt_bp, parse_grouping_expr)
-	nud(lexer.FN, defalt_bp, parse_fn_expr)
-	nud(lexer.NEW, defalt_bp, func(p *parser) ast.Expr {
-		p.advance()
-		classInstantiation := parse_expr(p, defalt_bp)
-
-		return ast.NewExpr{
-			Instantiation: ast.ExpectExpr[ast.CallExpr](classInstantiation),
-		}
-	})
-
-	stmt(lexer.OPEN_CURLY, parse_block_stmt)
-	stmt(lexer.LET, parse_var_decl_stmt)
-	stmt(lexer.CONST, parse_var_decl_stmt)
-	stmt(lexer.FN, parse_fn_declaration)
-	stmt(lexer.IF, parse_if_stmt)
-	stmt(lexer.IMPORT, parse_import_stmt)
-	stmt(lexer.FOREACH, parse_foreach_stmt)
-	stmt(lexer.CLASS, parse_class_declaration_stmt)
+	nud(lexer.TokenTypeSymbolLParen, defalt_bp, parse_grouping_expr)
+	nud(lexer.TokenTypeKeywordFunc, defalt_bp, parse_fn_expr)
+	nud(lexer.TokenTypeKeywordNew, defalt_bp, parse_new_expr)
+	nud(lexer.TokenTypeKeywordChan, defalt_bp, parse_channel_expr)
+
+	stmt(lexer.TokenTypeSymbolLBrance, parse_block_stmt)
+	stmt(lexer.TokenTypeKeywordVar, parse_var_decl_stmt)
+	stmt(lexer.TokenTypeKeywordLet, parse_var_decl_stmt)
+	stmt(lexer.TokenTypeKeywordVal, parse_var_decl_stmt)
+	stmt(lexer.TokenTypeKeywordFunc, parse_fn_declaration)
+	stmt(lexer.TokenTypeKeywordIf, parse_if_stmt)
+	stmt(lexer.TokenTypeKeywordImport, parse_import_stmt)
+	stmt(lexer.TokenTypeKeywordForeach, parse_foreach_stmt)
+	stmt(lexer.TokenTypeKeywordClass, parse_class_declaration_stmt)
+	stmt(lexer.TokenTypeKeywordType, parse_type_alias_stmt)
+	stmt(lexer.TokenTypeKeywordEnum, parse_enum_declaration_stmt)
+	stmt(lexer.TokenTypeKeywordInterface, parse_interface_declaration_stmt)
+	stmt(lexer.TokenTypeKeywordReturn, parse_return_stmt)
+	stmt(lexer.TokenTypeKeywordThrow, parse_throw_stmt)
+	stmt(lexer.TokenTypeKeywordTry, parse_try_stmt)
+	stmt(lexer.TokenTypeKeywordSpawn, parse_spawn_stmt)
+	stmt(lexer.TokenTypeKeywordSelect, parse_select_stmt)
+	stmt(lexer.TokenTypeKeywordSwitch, parse_switch_stmt)
 }
@@ -17,7 +17,7 @@ func (p *parser) advance() lexer.Token {
 }
 
 func (p *parser) hasTokens() bool {
-	return p.pos < len(p.tokens) && p.currentTokenKind() != lexer.EOF
+	return p.pos < len(p.tokens) && p.currentTokenKind() != lexer.TokenTypeEOF
 }
 
 func (p *parser) nextToken() lexer.Token {
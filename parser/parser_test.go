@@ -0,0 +1,89 @@
+package parser_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"dreamlang/ast"
+	"dreamlang/parser"
+)
+
+// parseExpr 解析只包含一条表达式语句的 source，返回该表达式的 S 表达式形式。
+func parseExpr(t *testing.T, source string) string {
+	t.Helper()
+
+	body := parser.Parse(source).Body
+	if len(body) != 1 {
+		t.Fatalf("parsed %d statements, want 1", len(body))
+	}
+	stmt, ok := body[0].(ast.ExpressionStmt)
+	if !ok {
+		t.Fatalf("parsed %T, want an expression statement", body[0])
+	}
+
+	return show(stmt.Expression)
+}
+
+// show 以 S 表达式的形式显示 expr，例如 (+ a (* b c))，使测试只比较语法树的结构。
+func show(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case ast.SymbolExpr:
+		return expr.Value
+	case ast.NumberExpr:
+		return fmt.Sprint(expr.Value)
+	case ast.StringExpr:
+		return fmt.Sprintf("%q", expr.Value)
+	case ast.BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", expr.Operator.Value, show(expr.Left), show(expr.Right))
+	case ast.AssignmentExpr:
+		return fmt.Sprintf("(%s %s %s)", expr.Operator.Value, show(expr.Assigne), show(expr.AssignedValue))
+	case ast.PrefixExpr:
+		return fmt.Sprintf("(%s %s)", expr.Operator.Value, show(expr.Right))
+	case ast.MemberExpr:
+		return show(expr.Member) + "." + expr.Property
+	case ast.CallExpr:
+		var sb strings.Builder
+		sb.WriteString(show(expr.Method))
+		if expr.TypeArguments != nil {
+			sb.WriteString("<" + showTypes(expr.TypeArguments) + ">")
+		}
+		args := make([]string, len(expr.Arguments))
+		for i, arg := range expr.Arguments {
+			args[i] = show(arg)
+		}
+		sb.WriteString("(" + strings.Join(args, ", ") + ")")
+		return sb.String()
+	}
+
+	return fmt.Sprintf("<%T>", expr)
+}
+
+// showType 显示类型 t 的源代码形式。
+func showType(t ast.Type) string {
+	switch t := t.(type) {
+	case ast.SymbolType:
+		return t.Value
+	case ast.ListType:
+		return "[]" + showType(t.Underlying)
+	case ast.GenericType:
+		return t.Name + "<" + showTypes(t.Arguments) + ">"
+	case ast.UnionType:
+		members := make([]string, len(t.Members))
+		for i, member := range t.Members {
+			members[i] = showType(member)
+		}
+		return strings.Join(members, " | ")
+	}
+
+	return fmt.Sprintf("<%T>", t)
+}
+
+func showTypes(types []ast.Type) string {
+	shown := make([]string, len(types))
+	for i, t := range types {
+		shown[i] = showType(t)
+	}
+
+	return strings.Join(shown, ", ")
+}
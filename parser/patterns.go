@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+
+	"dreamlang/ast"
+	"dreamlang/lexer"
+)
+
+// parse_pattern 解析变量声明、函数参数和 foreach 头部中的绑定模式。
+//
+// 支持的模式:
+//   - 变量名: x
+//   - 列表模式: [a, b, ...rest]，...rest 只能出现在最后，绑定剩余元素组成的列表。
+//   - 对象模式: {x, y: alias}，{x} 是 {x: x} 的简写。
+//
+// 列表模式和对象模式可以相互嵌套，例如 [{x}, [a, b]]。
+func parse_pattern(p *parser) ast.Pattern {
+	switch p.currentTokenKind() {
+	case lexer.TokenTypeSymbolLBracket:
+		return parse_array_pattern(p)
+	case lexer.TokenTypeSymbolLBrance:
+		return parse_object_pattern(p)
+	case lexer.TokenTypeValIdentifier:
+		return ast.IdentifierPattern{Name: p.advance().Value}
+	default:
+		panic(fmt.Sprintf("Expected variable name or destructuring pattern but recieved %s instead\n",
+			lexer.TokenKindString(p.currentTokenKind())))
+	}
+}
+
+func parse_array_pattern(p *parser) ast.Pattern {
+	pattern := ast.ArrayPattern{Elements: []ast.Pattern{}}
+
+	p.expect(lexer.TokenTypeSymbolLBracket)
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBracket {
+		if p.currentTokenKind() == lexer.TokenTypeSymbolVarargs {
+			p.advance()
+			pattern.Rest = parse_pattern(p)
+			break
+		}
+
+		pattern.Elements = append(pattern.Elements, parse_pattern(p))
+
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRBracket, lexer.TokenTypeEOF) {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+
+	if pattern.Rest != nil && p.currentTokenKind() != lexer.TokenTypeSymbolRBracket {
+		panic("Rest element must be the last element of a list pattern\n")
+	}
+
+	p.expect(lexer.TokenTypeSymbolRBracket)
+	return pattern
+}
+
+func parse_object_pattern(p *parser) ast.Pattern {
+	pattern := ast.ObjectPattern{Properties: []ast.PropertyPattern{}}
+
+	p.expect(lexer.TokenTypeSymbolLBrance)
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		key := p.expect(lexer.TokenTypeValIdentifier).Value
+		var value ast.Pattern = ast.IdentifierPattern{Name: key}
+
+		if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+			p.advance()
+			value = parse_pattern(p)
+		}
+
+		pattern.Properties = append(pattern.Properties, ast.PropertyPattern{Key: key, Value: value})
+
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRBrance, lexer.TokenTypeEOF) {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+
+	p.expect(lexer.TokenTypeSymbolRBrance)
+	return pattern
+}
@@ -0,0 +1,42 @@
+package parser_test
+
+import "testing"
+
+func TestPrecedence(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`a - b - c;`, `(- (- a b) c)`},
+		{`a - b * c;`, `(- a (* b c))`},
+		{`a * b - c;`, `(- (* a b) c)`},
+		{`a - b.c;`, `(- a b.c)`},
+		{`a - f(b);`, `(- a f(b))`},
+		{`-a - -b;`, `(- (- a) (- b))`},
+		{`a / b / c;`, `(/ (/ a b) c)`},
+		{`a + b * c;`, `(+ a (* b c))`},
+		{`a * b + c;`, `(+ (* a b) c)`},
+		{`2 ** 3 ** 2;`, `(** 2 (** 3 2))`},
+		{`a * b ** c;`, `(* a (** b c))`},
+		{`a ** b * c;`, `(* (** a b) c)`},
+		{`-a ** b;`, `(- (** a b))`},
+		{`a ** -b;`, `(** a (- b))`},
+		{`a.b ** f(c);`, `(** a.b f(c))`},
+		{`a || b && c;`, `(|| a (&& b c))`},
+		{`a && b || c;`, `(|| (&& a b) c)`},
+		{`a < b == c;`, `(== (< a b) c)`},
+		{`a = b;`, `(= a b)`},
+		{`a = b = c;`, `(= a (= b c))`},
+		{`a = b += c;`, `(= a (+= b c))`},
+		{`a.x = b.y = c + d;`, `(= a.x (= b.y (+ c d)))`},
+		{`a = b || c;`, `(= a (|| b c))`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			if got := parseExpr(t, test.source); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ func parse_stmt(p *parser) ast.Stmt {
 
 func parse_expression_stmt(p *parser) ast.ExpressionStmt {
 	expression := parse_expr(p, defalt_bp)
-	p.expect(lexer.SEMI_COLON)
+	p.expect(lexer.TokenTypeSymbolSemiColon)
 
 	return ast.ExpressionStmt{
 		Expression: expression,
@@ -27,14 +27,14 @@ func parse_expression_stmt(p *parser) ast.ExpressionStmt {
 }
 
 func parse_block_stmt(p *parser) ast.Stmt {
-	p.expect(lexer.OPEN_CURLY)
+	p.expect(lexer.TokenTypeSymbolLBrance)
 	body := []ast.Stmt{}
 
-	for p.hasTokens() && p.currentTokenKind() != lexer.CLOSE_CURLY {
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
 		body = append(body, parse_stmt(p))
 	}
 
-	p.expect(lexer.CLOSE_CURLY)
+	p.expect(lexer.TokenTypeSymbolRBrance)
 	return ast.BlockStmt{
 		Body: body,
 	}
@@ -43,8 +43,8 @@ func parse_block_stmt(p *parser) ast.Stmt {
 // parse_var_decl_stmt 解析变量声明语句，并返回一个 ast.Stmt 类型的节点。
 //
 // 该函数处理以下几种情况：
-// 1. 常量声明：以 `const` 关键字开头。
-// 2. 变量声明：以 `var` 关键字开头。
+// 1. 常量声明：以 `val` 关键字开头。
+// 2. 变量声明：以 `var` 或 `let` 关键字开头。
 //
 // 参数：
 // - p: *parser 类型的指针，用于解析输入的 token。
@@ -54,40 +54,46 @@ func parse_block_stmt(p *parser) ast.Stmt {
 //
 // 解析过程：
 // 1. 获取当前 token 的类型，判断是否为常量声明。
-// 2. 期望下一个 token 为标识符（变量名），否则抛出错误。
+// 2. 解析被声明的变量：变量名，或者 [a, b] 和 {x, y} 这样的解构模式。
 // 3. 如果下一个 token 为冒号（`:`），则解析变量的显式类型。
 // 4. 如果下一个 token 不是分号（`;`），则期望为赋值操作符（`=`），并解析赋值表达式。
-// 5. 如果没有显式类型且没有赋值表达式，则抛出错误。
+// 5. 如果没有显式类型且没有赋值表达式，或者解构模式没有赋值表达式，则抛出错误。
 // 6. 期望下一个 token 为分号（`;`），表示声明语句结束。
 // 7. 如果是常量声明但没有赋值表达式，则抛出错误。
 //
 // 返回的 ast.VarDeclarationStmt 包含以下字段：
 // - Constant: 是否为常量声明。
-// - Identifier: 变量名。
+// - Pattern: 变量名或解构模式。
 // - AssignedValue: 赋值表达式（如果有的话）。
 // - ExplicitType: 显式类型（如果有的话）。
 func parse_var_decl_stmt(p *parser) ast.Stmt {
 	var explicitType ast.Type
 	startToken := p.advance().Kind
-	isConstant := startToken == lexer.CONST
-	symbolName := p.expectError(lexer.IDENTIFIER,
-		fmt.Sprintf("Following %s expected variable name however instead recieved %s instead\n",
+	isConstant := startToken == lexer.TokenTypeKeywordVal
+	if !p.currentToken().IsOneOfMany(lexer.TokenTypeValIdentifier, lexer.TokenTypeSymbolLBracket, lexer.TokenTypeSymbolLBrance) {
+		panic(fmt.Sprintf("Following %s expected variable name however instead recieved %s instead\n",
 			lexer.TokenKindString(startToken), lexer.TokenKindString(p.currentTokenKind())))
+	}
+	pattern := parse_pattern(p)
 
-	if p.currentTokenKind() == lexer.COLON {
-		p.expect(lexer.COLON)
+	if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+		p.expect(lexer.TokenTypeSymbolColon)
 		explicitType = parse_type(p, defalt_bp)
 	}
 
 	var assignmentValue ast.Expr
-	if p.currentTokenKind() != lexer.SEMI_COLON {
-		p.expect(lexer.ASSIGNMENT)
+	if p.currentTokenKind() != lexer.TokenTypeSymbolSemiColon {
+		p.expect(lexer.TokenTypeSymbolAssignment)
 		assignmentValue = parse_expr(p, assignment)
 	} else if explicitType == nil {
 		panic("Missing explicit type for variable declaration.")
 	}
 
-	p.expect(lexer.SEMI_COLON)
+	if _, isIdentifier := pattern.(ast.IdentifierPattern); !isIdentifier && assignmentValue == nil {
+		panic("Cannot declare destructuring pattern without providing a value.")
+	}
+
+	p.expect(lexer.TokenTypeSymbolSemiColon)
 
 	if isConstant && assignmentValue == nil {
 		panic("Cannot define constant variable without providing default value.")
@@ -95,67 +101,146 @@ func parse_var_decl_stmt(p *parser) ast.Stmt {
 
 	return ast.VarDeclarationStmt{
 		Constant:      isConstant,
-		Identifier:    symbolName.Value,
+		Pattern:       pattern,
 		AssignedValue: assignmentValue,
 		ExplicitType:  explicitType,
 	}
 }
 
 func parse_fn_params_and_body(p *parser) ([]ast.Parameter, ast.Type, []ast.Stmt) {
+	functionParams := parse_fn_params(p)
+	var returnType ast.Type
+
+	if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+		p.advance()
+		returnType = parse_type(p, defalt_bp)
+	}
+
+	functionBody := ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+
+	return functionParams, returnType, functionBody
+}
+
+// parse_fn_params 解析括号中的参数列表，例如 (x: number, y: number = 10, ...rest: []number)。
+//
+// 有默认值的参数之后的参数也必须有默认值；剩余参数只能是最后一个参数，且不能有默认值。
+func parse_fn_params(p *parser) []ast.Parameter {
+	return parse_params(p, true)
+}
+
+// parse_params 解析参数列表。requireTypes 为 false 时（箭头函数）参数的类型注解可以省略，省略时 Type 为 nil。
+func parse_params(p *parser, requireTypes bool) []ast.Parameter {
 	functionParams := make([]ast.Parameter, 0)
+	hasDefault := false
+
+	p.expect(lexer.TokenTypeSymbolLParen)
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+		isRest := false
+		if p.currentTokenKind() == lexer.TokenTypeSymbolVarargs {
+			p.advance()
+			isRest = true
+		}
+
+		paramPattern := parse_pattern(p)
+
+		var paramType ast.Type
+		if requireTypes || p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+			p.expect(lexer.TokenTypeSymbolColon)
+			paramType = parse_type(p, defalt_bp)
+		}
+
+		var defaultValue ast.Expr
+		if p.currentTokenKind() == lexer.TokenTypeSymbolAssignment {
+			if isRest {
+				panic("Rest parameter cannot have a default value.")
+			}
+			p.advance()
+			defaultValue = parse_expr(p, assignment)
+			hasDefault = true
+		} else if hasDefault && !isRest {
+			panic("Parameter without a default value cannot follow a parameter with one.")
+		}
 
-	p.expect(lexer.OPEN_PAREN)
-	for p.hasTokens() && p.currentTokenKind() != lexer.CLOSE_PAREN {
-		paramName := p.expect(lexer.IDENTIFIER).Value
-		p.expect(lexer.COLON)
-		paramType := parse_type(p, defalt_bp)
+		if isRest && p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+			panic("Rest parameter must be the last parameter.")
+		}
 
 		functionParams = append(functionParams, ast.Parameter{
-			Name: paramName,
-			Type: paramType,
+			Pattern: paramPattern,
+			Type:    paramType,
+			Default: defaultValue,
+			Rest:    isRest,
 		})
 
-		if !p.currentToken().IsOneOfMany(lexer.CLOSE_PAREN, lexer.EOF) {
-			p.expect(lexer.COMMA)
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRParen, lexer.TokenTypeEOF) {
+			p.expect(lexer.TokenTypeSymbolComma)
 		}
 	}
 
-	p.expect(lexer.CLOSE_PAREN)
-	var returnType ast.Type
+	p.expect(lexer.TokenTypeSymbolRParen)
+	return functionParams
+}
 
-	if p.currentTokenKind() == lexer.COLON {
-		p.advance()
-		returnType = parse_type(p, defalt_bp)
+// parse_type_parameters 解析可选的类型参数列表，例如 <T, U: Comparable>。
+// 当前标记不是 "<" 时返回 nil。
+func parse_type_parameters(p *parser) []ast.TypeParameter {
+	if p.currentTokenKind() != lexer.TokenTypeSymbolLT {
+		return nil
 	}
 
-	functionBody := ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+	typeParams := make([]ast.TypeParameter, 0)
+	p.advance()
 
-	return functionParams, returnType, functionBody
+	for p.hasTokens() && !p.isTypeListClose(lexer.TokenTypeSymbolGT) {
+		typeParam := ast.TypeParameter{
+			Name: p.expect(lexer.TokenTypeValIdentifier).Value,
+		}
+
+		if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+			p.advance()
+			typeParam.Constraint = parse_type(p, defalt_bp)
+		}
+
+		typeParams = append(typeParams, typeParam)
+
+		if !p.isTypeListClose(lexer.TokenTypeSymbolGT) && p.currentTokenKind() != lexer.TokenTypeEOF {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+
+	p.expectTypeListClose(lexer.TokenTypeSymbolGT)
+	return typeParams
 }
 
 func parse_fn_declaration(p *parser) ast.Stmt {
 	p.advance()
-	functionName := p.expect(lexer.IDENTIFIER).Value
+	functionName := p.expect(lexer.TokenTypeValIdentifier).Value
+	typeParams := parse_type_parameters(p)
 	functionParams, returnType, functionBody := parse_fn_params_and_body(p)
 
 	return ast.FunctionDeclarationStmt{
-		Parameters: functionParams,
-		ReturnType: returnType,
-		Body:       functionBody,
-		Name:       functionName,
+		Parameters:     functionParams,
+		TypeParameters: typeParams,
+		ReturnType:     returnType,
+		Body:           functionBody,
+		Name:           functionName,
 	}
 }
 
+// parse_if_stmt 解析 if 语句。else if 和 elseif 等价，都解析为 Alternate 中嵌套的 IfStmt，
+// 例如 if a {} elseif b {} else {}。
 func parse_if_stmt(p *parser) ast.Stmt {
 	p.advance()
 	condition := parse_expr(p, assignment)
 	consequent := parse_block_stmt(p)
 
 	var alternate ast.Stmt
-	if p.currentTokenKind() == lexer.ELSE {
+	if p.currentTokenKind() == lexer.TokenTypeKeywordElseIf {
+		alternate = parse_if_stmt(p)
+	} else if p.currentTokenKind() == lexer.TokenTypeKeywordElse {
 		p.advance()
 
-		if p.currentTokenKind() == lexer.IF {
+		if p.currentTokenKind() == lexer.TokenTypeKeywordIf {
 			alternate = parse_if_stmt(p)
 		} else {
 			alternate = parse_block_stmt(p)
@@ -172,40 +257,48 @@ func parse_if_stmt(p *parser) ast.Stmt {
 func parse_import_stmt(p *parser) ast.Stmt {
 	p.advance()
 	var importFrom string
-	importName := p.expect(lexer.IDENTIFIER).Value
+	importName := p.expect(lexer.TokenTypeValIdentifier).Value
 
-	if p.currentTokenKind() == lexer.FROM {
+	if p.currentTokenKind() == lexer.TokenTypeKeywordFrom {
 		p.advance()
-		importFrom = p.expect(lexer.STRING).Value
+		importFrom = p.expect(lexer.TokenTypeValString).Value
 	} else {
 		importFrom = importName
 	}
 
-	p.expect(lexer.SEMI_COLON)
+	p.expect(lexer.TokenTypeSymbolSemiColon)
 	return ast.ImportStmt{
 		Name: importName,
 		From: importFrom,
 	}
 }
 
+// parse_foreach_stmt 解析 foreach 循环。foreach v in xs 只绑定元素；
+// foreach i, v in xs 同时绑定下标（遍历字典时为键）和元素。元素可以是解构模式，下标只能是变量名。
 func parse_foreach_stmt(p *parser) ast.Stmt {
 	p.advance()
-	valueName := p.expect(lexer.IDENTIFIER).Value
+	var index string
+	value := parse_pattern(p)
+
+	if p.currentTokenKind() == lexer.TokenTypeSymbolComma {
+		p.expect(lexer.TokenTypeSymbolComma)
 
-	var index bool
-	if p.currentTokenKind() == lexer.COMMA {
-		p.expect(lexer.COMMA)
-		p.expect(lexer.IDENTIFIER)
-		index = true
+		indexPattern, ok := value.(ast.IdentifierPattern)
+		if !ok {
+			panic("Foreach index must be a variable name.")
+		}
+
+		index = indexPattern.Name
+		value = parse_pattern(p)
 	}
 
-	p.expect(lexer.IN)
+	p.expect(lexer.TokenTypeKeywordIn)
 	iterable := parse_expr(p, defalt_bp)
 	body := ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
 
 	return ast.ForeachStmt{
-		Value:    valueName,
 		Index:    index,
+		Value:    value,
 		Iterable: iterable,
 		Body:     body,
 	}
@@ -213,11 +306,311 @@ func parse_foreach_stmt(p *parser) ast.Stmt {
 
 func parse_class_declaration_stmt(p *parser) ast.Stmt {
 	p.advance()
-	className := p.expect(lexer.IDENTIFIER).Value
+	className := p.expect(lexer.TokenTypeValIdentifier).Value
+	typeParams := parse_type_parameters(p)
 	classBody := parse_block_stmt(p)
 
 	return ast.ClassDeclarationStmt{
-		Name: className,
-		Body: ast.ExpectStmt[ast.BlockStmt](classBody).Body,
+		Name:           className,
+		TypeParameters: typeParams,
+		Body:           ast.ExpectStmt[ast.BlockStmt](classBody).Body,
+	}
+}
+
+func parse_type_alias_stmt(p *parser) ast.Stmt {
+	p.advance()
+	aliasName := p.expect(lexer.TokenTypeValIdentifier).Value
+	p.expect(lexer.TokenTypeSymbolAssignment)
+	aliasedType := parse_type(p, defalt_bp)
+	p.expect(lexer.TokenTypeSymbolSemiColon)
+
+	return ast.TypeAliasStmt{
+		Name: aliasName,
+		Type: aliasedType,
+	}
+}
+
+// parse_enum_declaration_stmt 解析枚举声明，例如 enum Color { Red, Green = 2, Blue }。
+// 成员之间以逗号分隔，允许末尾多出一个逗号。
+func parse_enum_declaration_stmt(p *parser) ast.Stmt {
+	p.advance()
+	enumName := p.expect(lexer.TokenTypeValIdentifier).Value
+	members := make([]ast.EnumMember, 0)
+
+	p.expect(lexer.TokenTypeSymbolLBrance)
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		member := ast.EnumMember{
+			Name: p.expect(lexer.TokenTypeValIdentifier).Value,
+		}
+
+		if p.currentTokenKind() == lexer.TokenTypeSymbolAssignment {
+			p.advance()
+			member.Value = parse_expr(p, assignment)
+		}
+
+		members = append(members, member)
+
+		if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRBrance, lexer.TokenTypeEOF) {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+	p.expect(lexer.TokenTypeSymbolRBrance)
+
+	return ast.EnumDeclarationStmt{
+		Name:    enumName,
+		Members: members,
+	}
+}
+
+// parse_interface_declaration_stmt 解析接口声明，例如 interface Shape<T> { name: string; area(): number }。
+//
+// 接口体中的每个成员可以是字段 name: Type 或方法签名 name(params): ReturnType，
+// 成员之间可以用分号或逗号分隔，也可以不加分隔符。方法签名省略返回类型时表示没有返回值。
+func parse_interface_declaration_stmt(p *parser) ast.Stmt {
+	p.advance()
+	interfaceName := p.expect(lexer.TokenTypeValIdentifier).Value
+	typeParams := parse_type_parameters(p)
+	fields := make([]ast.InterfaceField, 0)
+	methods := make([]ast.InterfaceMethod, 0)
+
+	p.expect(lexer.TokenTypeSymbolLBrance)
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		memberName := p.expect(lexer.TokenTypeValIdentifier).Value
+
+		if p.currentTokenKind() == lexer.TokenTypeSymbolLParen {
+			method := ast.InterfaceMethod{
+				Name:       memberName,
+				Parameters: parse_fn_params(p),
+			}
+
+			if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+				p.advance()
+				method.ReturnType = parse_type(p, defalt_bp)
+			}
+
+			methods = append(methods, method)
+		} else {
+			p.expect(lexer.TokenTypeSymbolColon)
+			fields = append(fields, ast.InterfaceField{
+				Name: memberName,
+				Type: parse_type(p, defalt_bp),
+			})
+		}
+
+		if p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolSemiColon, lexer.TokenTypeSymbolComma) {
+			p.advance()
+		}
+	}
+	p.expect(lexer.TokenTypeSymbolRBrance)
+
+	return ast.InterfaceDeclarationStmt{
+		Name:           interfaceName,
+		TypeParameters: typeParams,
+		Fields:         fields,
+		Methods:        methods,
+	}
+}
+
+func parse_return_stmt(p *parser) ast.Stmt {
+	p.advance()
+	var value ast.Expr
+
+	if p.currentTokenKind() != lexer.TokenTypeSymbolSemiColon {
+		value = parse_expr(p, defalt_bp)
+	}
+
+	p.expect(lexer.TokenTypeSymbolSemiColon)
+	return ast.ReturnStmt{
+		Value: value,
+	}
+}
+
+func parse_throw_stmt(p *parser) ast.Stmt {
+	p.advance()
+	value := parse_expr(p, defalt_bp)
+	p.expect(lexer.TokenTypeSymbolSemiColon)
+
+	return ast.ThrowStmt{
+		Value: value,
+	}
+}
+
+// parse_try_stmt 解析 try 语句：
+//
+//	try { ... } catch (e: Error) { ... } finally { ... }
+//
+// catch 子句的绑定和类型注解都是可选的（catch { ... }、catch (e) { ... }），
+// catch 和 finally 子句至少要有一个。
+func parse_try_stmt(p *parser) ast.Stmt {
+	p.advance()
+	body := ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+
+	var catchClause *ast.CatchClause
+	if p.currentTokenKind() == lexer.TokenTypeKeywordCatch {
+		p.advance()
+		catchClause = &ast.CatchClause{}
+
+		if p.currentTokenKind() == lexer.TokenTypeSymbolLParen {
+			p.advance()
+			catchClause.Name = p.expect(lexer.TokenTypeValIdentifier).Value
+
+			if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+				p.advance()
+				catchClause.Type = parse_type(p, defalt_bp)
+			}
+
+			p.expect(lexer.TokenTypeSymbolRParen)
+		}
+
+		catchClause.Body = ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+	}
+
+	var finallyBody []ast.Stmt
+	if p.currentTokenKind() == lexer.TokenTypeKeywordFinally {
+		p.advance()
+		finallyBody = ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+	}
+
+	if catchClause == nil && finallyBody == nil {
+		panic("Expected catch or finally after try block\n")
+	}
+
+	return ast.TryStmt{
+		Body:    body,
+		Catch:   catchClause,
+		Finally: finallyBody,
+	}
+}
+
+// parse_spawn_stmt 解析 spawn 语句：spawn worker(ch, 1);。spawn 之后必须是一个函数调用。
+func parse_spawn_stmt(p *parser) ast.Stmt {
+	p.advance()
+	call, ok := parse_expr(p, defalt_bp).(ast.CallExpr)
+	if !ok {
+		panic("Expression in spawn must be a function call.")
+	}
+
+	p.expect(lexer.TokenTypeSymbolSemiColon)
+	return ast.SpawnStmt{
+		Call: call,
+	}
+}
+
+// parse_select_stmt 解析 select 语句：
+//
+//	select {
+//		case let v = <-values { ... }
+//		case <-done { ... }
+//		case out <- 1 { ... }
+//		default { ... }
+//	}
+//
+// 每个 case 是一次通道接收或发送，接收到的值可以用 let 绑定；default 分支最多只能有一个。
+func parse_select_stmt(p *parser) ast.Stmt {
+	p.advance()
+	p.expect(lexer.TokenTypeSymbolLBrance)
+
+	cases := make([]ast.SelectCase, 0)
+	var defaultBody []ast.Stmt
+
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		switch p.currentTokenKind() {
+		case lexer.TokenTypeKeywordCase:
+			p.advance()
+			var binding ast.Pattern
+
+			if p.currentTokenKind() == lexer.TokenTypeKeywordLet {
+				p.advance()
+				binding = parse_pattern(p)
+				p.expect(lexer.TokenTypeSymbolAssignment)
+			}
+
+			operation := parse_expr(p, defalt_bp)
+			switch operation.(type) {
+			case ast.ReceiveExpr:
+			case ast.SendExpr:
+				if binding != nil {
+					panic("Only a receive can be bound in a select case.")
+				}
+			default:
+				panic("Select case must be a channel send or receive.")
+			}
+
+			cases = append(cases, ast.SelectCase{
+				Operation: operation,
+				Binding:   binding,
+				Body:      ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body,
+			})
+		case lexer.TokenTypeKeywordDefault:
+			p.advance()
+			if defaultBody != nil {
+				panic("Multiple defaults in select statement.")
+			}
+			defaultBody = ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+		default:
+			panic(fmt.Sprintf("Expected case or default in select statement but recieved %s instead\n", lexer.TokenKindString(p.currentTokenKind())))
+		}
+	}
+
+	p.expect(lexer.TokenTypeSymbolRBrance)
+	return ast.SelectStmt{
+		Cases:   cases,
+		Default: defaultBody,
+	}
+}
+
+// parse_switch_stmt 解析 switch 语句：
+//
+//	switch value {
+//		case 1, 2 { ... }
+//		case is string { ... }
+//		default { ... }
+//	}
+//
+// 值分支可以列出多个值，任意一个与 value 相等时选中；类型分支 case is T 在 value is T 成立时选中。
+// default 分支最多只能有一个。
+func parse_switch_stmt(p *parser) ast.Stmt {
+	p.advance()
+	value := parse_expr(p, assignment)
+	p.expect(lexer.TokenTypeSymbolLBrance)
+
+	cases := make([]ast.SwitchCase, 0)
+	var defaultBody []ast.Stmt
+
+	for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRBrance {
+		switch p.currentTokenKind() {
+		case lexer.TokenTypeKeywordCase:
+			p.advance()
+			var switchCase ast.SwitchCase
+
+			if p.currentTokenKind() == lexer.TokenTypeKeywordIs {
+				p.advance()
+				switchCase.Type = parse_type(p, defalt_bp)
+			} else {
+				switchCase.Values = append(switchCase.Values, parse_expr(p, assignment))
+				for p.currentTokenKind() == lexer.TokenTypeSymbolComma {
+					p.advance()
+					switchCase.Values = append(switchCase.Values, parse_expr(p, assignment))
+				}
+			}
+
+			switchCase.Body = ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+			cases = append(cases, switchCase)
+		case lexer.TokenTypeKeywordDefault:
+			p.advance()
+			if defaultBody != nil {
+				panic("Multiple defaults in switch statement.")
+			}
+			defaultBody = ast.ExpectStmt[ast.BlockStmt](parse_block_stmt(p)).Body
+		default:
+			panic(fmt.Sprintf("Expected case or default in switch statement but recieved %s instead\n", lexer.TokenKindString(p.currentTokenKind())))
+		}
+	}
+
+	p.expect(lexer.TokenTypeSymbolRBrance)
+	return ast.SwitchStmt{
+		Value:   value,
+		Cases:   cases,
+		Default: defaultBody,
 	}
 }
@@ -23,24 +23,177 @@ func type_led(kind lexer.TokenKind, bp binding_power, led_fn type_led_handler) {
 	type_led_lu[kind] = led_fn
 }
 
+// type_nud 注册类型前缀处理函数。与表达式的 nud 不同，它不会修改 type_bp_lu：
+// 类型后面经常紧跟 "{"（函数体）或 "["，这些标记不能被当作类型的中缀运算符继续解析。
 func type_nud(kind lexer.TokenKind, bp binding_power, nud_fn type_nud_handler) {
-	type_bp_lu[kind] = primary
 	type_nud_lu[kind] = nud_fn
 }
 
 func createTypeTokenLookups() {
 
-	type_nud(lexer.IDENTIFIER, primary, func(p *parser) ast.Type {
+	type_nud(lexer.TokenTypeValIdentifier, primary, func(p *parser) ast.Type {
+		name := p.advance().Value
+
+		// map[string]number
+		if name == "map" && p.currentTokenKind() == lexer.TokenTypeSymbolLBracket {
+			p.advance()
+			keyType := parse_type(p, defalt_bp)
+			p.expect(lexer.TokenTypeSymbolRBracket)
+
+			return ast.MapType{
+				Key:   keyType,
+				Value: parse_type(p, defalt_bp),
+			}
+		}
+
 		return ast.SymbolType{
-			Value: p.advance().Value,
+			Value: name,
+		}
+	})
+
+	// null 是关键字而不是标识符，例如 string | null
+	type_nud(lexer.TokenTypeValNull, primary, func(p *parser) ast.Type {
+		p.advance()
+		return ast.SymbolType{
+			Value: "null",
+		}
+	})
+
+	// fn(number, string): bool，最后一个参数可以是剩余参数：fn(string, ...[]number)
+	type_nud(lexer.TokenTypeKeywordFunc, primary, func(p *parser) ast.Type {
+		p.advance()
+		parameters := make([]ast.Type, 0)
+		var rest ast.Type
+
+		p.expect(lexer.TokenTypeSymbolLParen)
+		for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+			if p.currentTokenKind() == lexer.TokenTypeSymbolVarargs {
+				p.advance()
+				rest = parse_type(p, defalt_bp)
+				if p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+					panic("Rest parameter must be the last parameter.")
+				}
+				break
+			}
+
+			parameters = append(parameters, parse_type(p, defalt_bp))
+
+			if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRParen, lexer.TokenTypeEOF) {
+				p.expect(lexer.TokenTypeSymbolComma)
+			}
+		}
+		p.expect(lexer.TokenTypeSymbolRParen)
+
+		var returnType ast.Type
+		if p.currentTokenKind() == lexer.TokenTypeSymbolColon {
+			p.advance()
+			returnType = parse_type(p, defalt_bp)
+		}
+
+		return ast.FunctionType{
+			Parameters: parameters,
+			Rest:       rest,
+			ReturnType: returnType,
+		}
+	})
+
+	// chan<number>
+	type_nud(lexer.TokenTypeKeywordChan, primary, func(p *parser) ast.Type {
+		p.advance()
+		typeArguments := parse_type_list(p, lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolGT)
+		if len(typeArguments) != 1 {
+			panic(fmt.Sprintf("type: expected 1 type argument for chan but recieved %d instead\n", len(typeArguments)))
+		}
+
+		return ast.ChannelType{
+			Element: typeArguments[0],
+		}
+	})
+
+	// (A, B) 或分组 (A | B)
+	type_nud(lexer.TokenTypeSymbolLParen, primary, func(p *parser) ast.Type {
+		p.advance()
+		members := make([]ast.Type, 0)
+
+		for p.hasTokens() && p.currentTokenKind() != lexer.TokenTypeSymbolRParen {
+			members = append(members, parse_type(p, defalt_bp))
+
+			if len(members) == 1 && p.currentTokenKind() == lexer.TokenTypeSymbolRParen {
+				p.advance()
+				return members[0]
+			}
+
+			if !p.currentToken().IsOneOfMany(lexer.TokenTypeSymbolRParen, lexer.TokenTypeEOF) {
+				p.expect(lexer.TokenTypeSymbolComma)
+			}
+		}
+
+		p.expect(lexer.TokenTypeSymbolRParen)
+		return ast.TupleType{
+			Members: members,
+		}
+	})
+
+	// A | B
+	type_led(lexer.TokenTypeSymbolBitOr, logical, func(p *parser, left ast.Type, bp binding_power) ast.Type {
+		p.advance()
+		right := parse_type(p, logical)
+		members := make([]ast.Type, 0)
+
+		for _, side := range []ast.Type{left, right} {
+			if union, ok := side.(ast.UnionType); ok {
+				members = append(members, union.Members...)
+			} else {
+				members = append(members, side)
+			}
+		}
+
+		return ast.UnionType{
+			Members: members,
+		}
+	})
+
+	// T?
+	type_led(lexer.TokenTypeSymbolQuestion, unary, func(p *parser, left ast.Type, bp binding_power) ast.Type {
+		p.advance()
+
+		return ast.OptionalType{
+			Underlying: left,
+		}
+	})
+
+	// Map<string, number>
+	type_led(lexer.TokenTypeSymbolLT, call, func(p *parser, left ast.Type, bp binding_power) ast.Type {
+		symbol, ok := left.(ast.SymbolType)
+		if !ok {
+			panic("type: only named types can take type arguments\n")
+		}
+
+		return ast.GenericType{
+			Name:      symbol.Value,
+			Arguments: parse_type_list(p, lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolGT),
+		}
+	})
+
+	// {string: number}
+	type_nud(lexer.TokenTypeSymbolLBrance, primary, func(p *parser) ast.Type {
+		p.advance()
+		keyType := parse_type(p, defalt_bp)
+		p.expect(lexer.TokenTypeSymbolColon)
+		valueType := parse_type(p, defalt_bp)
+		p.expect(lexer.TokenTypeSymbolRBrance)
+
+		return ast.MapType{
+			Key:   keyType,
+			Value: valueType,
 		}
 	})
 
 	// []number
-	type_nud(lexer.OPEN_BRACKET, member, func(p *parser) ast.Type {
+	type_nud(lexer.TokenTypeSymbolLBracket, member, func(p *parser) ast.Type {
 		p.advance()
-		p.expect(lexer.CLOSE_BRACKET)
-		insideType := parse_type(p, defalt_bp)
+		p.expect(lexer.TokenTypeSymbolRBracket)
+		insideType := parse_type(p, unary)
 
 		return ast.ListType{
 			Underlying: insideType,
@@ -86,3 +239,107 @@ func parse_type(p *parser, bp binding_power) ast.Type {
 
 	return left
 }
+
+// parse_type_list 解析由 open 和 close 包围、以逗号分隔的类型列表，例如函数类型的参数列表或泛型实参列表。
+// 当 close 为 ">" 时，嵌套泛型末尾的 ">>" 会被拆分为两个 ">"。
+func parse_type_list(p *parser, open lexer.TokenKind, close lexer.TokenKind) []ast.Type {
+	types := make([]ast.Type, 0)
+	p.expect(open)
+
+	for p.hasTokens() && !p.isTypeListClose(close) {
+		types = append(types, parse_type(p, defalt_bp))
+
+		if !p.isTypeListClose(close) && p.currentTokenKind() != lexer.TokenTypeEOF {
+			p.expect(lexer.TokenTypeSymbolComma)
+		}
+	}
+
+	p.expectTypeListClose(close)
+	return types
+}
+
+func (p *parser) isTypeListClose(close lexer.TokenKind) bool {
+	kind := p.currentTokenKind()
+	return kind == close || (close == lexer.TokenTypeSymbolGT && kind == lexer.TokenTypeSymbolRShift)
+}
+
+func (p *parser) expectTypeListClose(close lexer.TokenKind) {
+	if close == lexer.TokenTypeSymbolGT && p.currentTokenKind() == lexer.TokenTypeSymbolRShift {
+		// List<Map<string, number>>: 消耗 ">>" 中的第一个 ">"，把剩下的部分留给外层列表。
+		p.tokens[p.pos].Kind = lexer.TokenTypeSymbolGT
+		p.tokens[p.pos].Value = ">"
+		return
+	}
+
+	p.expect(close)
+}
+
+// typeListTokens 是类型中可能出现的标记，用于判断 "<" 开始的是类型实参列表还是比较。
+var typeListTokens = map[lexer.TokenKind]bool{
+	lexer.TokenTypeValIdentifier: true, lexer.TokenTypeValNull: true, lexer.TokenTypeKeywordFunc: true, lexer.TokenTypeKeywordChan: true,
+	lexer.TokenTypeSymbolLParen: true, lexer.TokenTypeSymbolRParen: true, lexer.TokenTypeSymbolLBracket: true, lexer.TokenTypeSymbolRBracket: true,
+	lexer.TokenTypeSymbolLBrance: true, lexer.TokenTypeSymbolRBrance: true, lexer.TokenTypeSymbolComma: true, lexer.TokenTypeSymbolColon: true,
+	lexer.TokenTypeSymbolBitOr: true, lexer.TokenTypeSymbolQuestion: true, lexer.TokenTypeSymbolVarargs: true,
+}
+
+// parse_type_arguments 尝试把当前的 "<" 开始的部分解析为调用的类型实参列表，例如 id<string>("a") 中的 <string>。
+// 只有当 "<" 与之匹配的 ">" 之间能解析为类型列表，且 ">" 之后紧跟 "(" 时才是类型实参；
+// 否则不消耗任何标记并返回 false，"<" 作为比较运算符解析。因此 a < b > (c) 按调用解析，需要比较时应加上括号。
+func parse_type_arguments(p *parser) (types []ast.Type, ok bool) {
+	end := p.typeArgumentListEnd()
+	if end < 0 {
+		return nil, false
+	}
+
+	// parse_type_list 会把 ">>" 拆分为两个 ">"，失败时需要恢复被修改的标记。
+	start := p.pos
+	saved := append([]lexer.Token{}, p.tokens[start:end+1]...)
+	defer func() {
+		if r := recover(); r != nil || !ok {
+			copy(p.tokens[start:], saved)
+			p.pos = start
+			types, ok = nil, false
+		}
+	}()
+
+	types = parse_type_list(p, lexer.TokenTypeSymbolLT, lexer.TokenTypeSymbolGT)
+	return types, p.pos == end+1
+}
+
+// typeArgumentListEnd 向前查看从当前的 "<" 开始、由类型中可能出现的标记组成且括号配对的部分，
+// 返回与 "<" 匹配的 ">"（或 ">>"）的位置；这一部分之后不是 "(" 或者不存在这样的部分时返回 -1。
+func (p *parser) typeArgumentListEnd() int {
+	depth, nesting := 0, 0
+
+	for i := p.pos; i < len(p.tokens); i++ {
+		switch kind := p.tokens[i].Kind; kind {
+		case lexer.TokenTypeSymbolLT:
+			depth++
+		case lexer.TokenTypeSymbolGT, lexer.TokenTypeSymbolRShift:
+			if depth--; kind == lexer.TokenTypeSymbolRShift {
+				depth--
+			}
+			if depth < 0 || nesting != 0 {
+				return -1
+			}
+			if depth == 0 {
+				if i+1 < len(p.tokens) && p.tokens[i+1].Kind == lexer.TokenTypeSymbolLParen {
+					return i
+				}
+				return -1
+			}
+		case lexer.TokenTypeSymbolLParen, lexer.TokenTypeSymbolLBracket, lexer.TokenTypeSymbolLBrance:
+			nesting++
+		case lexer.TokenTypeSymbolRParen, lexer.TokenTypeSymbolRBracket, lexer.TokenTypeSymbolRBrance:
+			if nesting--; nesting < 0 {
+				return -1
+			}
+		default:
+			if !typeListTokens[kind] {
+				return -1
+			}
+		}
+	}
+
+	return -1
+}
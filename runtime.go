@@ -0,0 +1,282 @@
+// Package dreamlang 是在 Go 程序中嵌入 DreamLang 的入口。
+//
+// 典型的用法:
+//
+//	rt := dreamlang.NewRuntime(dreamlang.Options{})
+//	rt.RegisterFunc("lookup", func(id int) (string, error) { ... })
+//	rt.SetGlobal("limit", 10)
+//	rt.Grant("fs.read:/data", "env")
+//	if _, err := rt.Eval(ctx, src); err != nil { ... }
+//	result, err := rt.Call(ctx, "handle", request)
+//
+// Go 的值与 DreamLang 的值按以下规则互相转换:
+//   - bool、string 对应 bool、string；所有整数和浮点数类型对应 number，转换为整数时值必须是范围内的整数。
+//   - 切片和数组对应列表，map 对应字典；结构体转换为以字段名为键的字典，转换回结构体时也可以使用类的实例。
+//     字段名默认为首字母小写的 Go 字段名，可以用 `dream:"name"` 标签指定。
+//   - 指针对应其指向的值，nil 对应 null。
+//   - 函数对应内置函数，DreamLang 的函数也可以作为回调传给注册的 Go 函数。
+//   - 非 nil 的 error 对应 Error 的实例。
+//   - 转换为 any 时，列表和 Set 为 []any，键都是字符串的字典和 Map 为 map[string]any，其它字典和 Map 为 map[any]any。
+package dreamlang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"dreamlang/ast"
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+	"dreamlang/parser"
+	"dreamlang/stdlib"
+)
+
+// ErrBudgetExceeded 和 ErrStackOverflow 是超过 Options 中的资源限制时返回的错误，可以用 errors.Is 判断；
+// 返回的错误为 *LimitError，其中包含超过限制时的 DreamLang 调用栈。
+var (
+	ErrBudgetExceeded = interpreter.ErrBudgetExceeded
+	ErrStackOverflow  = interpreter.ErrStackOverflow
+)
+
+// LimitError 是超过资源限制时 Eval 和 Call 返回的错误。
+type LimitError = interpreter.LimitError
+
+// Options 配置 Runtime。
+//
+// 字段:
+//   - DisableTypeCheck: 为 true 时 Eval 不进行类型检查，直接执行程序；类型错误会在运行时以错误的形式出现。
+//   - MaxSteps: 每次 Eval 或 Call 最多执行的语句数，为 0 时不限制。
+//   - MaxMemory: 每次 Eval 或 Call 最多分配的内存的估算值（字节），为 0 时为 interpreter.DefaultMaxMemory，为负数时不限制。
+//   - MaxDepth: 函数调用的最大嵌套深度，为 0 时为 interpreter.DefaultMaxDepth。
+//   - Seed: math 模块的随机数种子，设置后每次运行得到相同的随机数序列；为 0 时使用当前时间。
+//   - FS: fs 模块访问的文件系统，为 nil 时为操作系统的文件系统；写入文件要求它实现 stdlib.WritableFS。
+//   - WorkDir: fs 和 path 模块中相对路径的基准目录，默认见 stdlib.Config。
+//   - Clock: time 模块的时钟，为 nil 时使用系统时间；测试中可以使用 stdlib.NewFakeClock 创建的模拟时钟。
+//   - HTTPClient: http 模块发送请求使用的客户端，为 nil 时为 http.DefaultClient。
+//   - Stdout: print、println 和 printf 的输出，为 nil 时为 os.Stdout；例如测试中可以用 bytes.Buffer 捕获程序的输出。
+//   - Stdin: readLine 读取的输入，为 nil 时为 os.Stdin。
+//
+// 运行不受信任的程序时应当同时设置 MaxSteps 和 MaxMemory，并在 ctx 中设置超时。
+type Options struct {
+	DisableTypeCheck bool
+	MaxSteps         int64
+	MaxMemory        int64
+	MaxDepth         int
+	Seed             int64
+	FS               fs.FS
+	WorkDir          string
+	Clock            stdlib.Clock
+	HTTPClient       *http.Client
+	Stdout           io.Writer
+	Stdin            io.Reader
+}
+
+// Runtime 是一个嵌入的 DreamLang 运行环境。依次执行的程序共享同一个全局作用域，
+// 因此一个程序中声明的函数可以在之后通过 Call 调用。
+//
+// Runtime 可以在多个 goroutine 中使用，但同一时刻只会执行一个 Eval 或 Call，其它调用会等待。
+type Runtime struct {
+	mu          sync.Mutex
+	options     Options
+	checker     *checker.Checker
+	interpreter *interpreter.Interpreter
+}
+
+// NewRuntime 创建一个 Runtime，其全局作用域中只有内置的类和函数，程序可以导入标准库模块，但没有任何能力。
+func NewRuntime(opts Options) *Runtime {
+	c := checker.NewChecker()
+	in := interpreter.New()
+	in.SetLimits(interpreter.Limits{MaxSteps: opts.MaxSteps, MaxMemory: opts.MaxMemory, MaxDepth: opts.MaxDepth})
+	if opts.Stdout != nil {
+		in.SetOutput(opts.Stdout)
+	}
+	if opts.Stdin != nil {
+		in.SetInput(opts.Stdin)
+	}
+	stdlib.Install(c, in, stdlib.Modules(stdlib.Config{Seed: opts.Seed, FS: opts.FS, WorkDir: opts.WorkDir, Clock: opts.Clock, HTTPClient: opts.HTTPClient})...)
+
+	return &Runtime{
+		options:     opts,
+		checker:     c,
+		interpreter: in,
+	}
+}
+
+// RegisterFunc 把 Go 函数 fn 注册为全局函数 name。
+//
+// fn 的参数和返回值按包文档中的规则转换。fn 的第一个参数可以是 context.Context，此时传入当前运行的 context；
+// 最后一个返回值可以是 error，返回非 nil 的 error 时在 DreamLang 中抛出错误。除 error 外有多个返回值时，
+// DreamLang 中得到由它们组成的元组。fn 为可变参数函数时，DreamLang 中对应剩余参数。
+//
+// 返回值:
+//   - error: fn 不是函数，或者它的参数或返回值类型无法与 DreamLang 的类型对应时返回错误。
+func (rt *Runtime) RegisterFunc(name string, fn any) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return fmt.Errorf("dreamlang: RegisterFunc %s: expected a function but got %T", name, fn)
+	}
+
+	signature, err := signatureOf(value.Type())
+	if err != nil {
+		return fmt.Errorf("dreamlang: RegisterFunc %s: %w", name, err)
+	}
+
+	rt.checker.Declare(name, signature)
+	rt.interpreter.Define(name, wrapFunc(name, value))
+	return nil
+}
+
+// SetGlobal 把 Go 的值 value 转换为 DreamLang 的值，并定义为全局常量 name。同名的全局变量会被覆盖。
+// value 为函数时与 RegisterFunc 相同。
+func (rt *Runtime) SetGlobal(name string, value any) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	t, converted, err := convertGlobal(name, value)
+	if err != nil {
+		return fmt.Errorf("dreamlang: SetGlobal %s: %w", name, err)
+	}
+
+	rt.checker.Declare(name, t)
+	rt.interpreter.Define(name, converted)
+	return nil
+}
+
+// RegisterModule 注册一个宿主模块，程序可以通过 import name; 导入它，并以 name.member 访问 members 中的成员。
+// 成员的值按 SetGlobal 的规则转换。capability 不为空字符串时，只有授予了该能力（或它的子能力）的程序才能导入模块。
+func (rt *Runtime) RegisterModule(name, capability string, members map[string]any) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	m := stdlib.NewModule(name, capability)
+	for member, value := range members {
+		t, converted, err := convertGlobal(name+"."+member, value)
+		if err != nil {
+			return fmt.Errorf("dreamlang: RegisterModule %s: %w", name, err)
+		}
+		m.Define(member, t, converted)
+	}
+
+	stdlib.Install(rt.checker, rt.interpreter, m)
+	return nil
+}
+
+// Grant 授予程序 capabilities 中的能力，每项能力的形式为 name 或 name:scope，例如 fs.read:/data、net、env 或 time。
+// Runtime 创建时没有任何能力；需要能力的内置函数和模块在能力没有被授予时抛出运行时错误。
+func (rt *Runtime) Grant(capabilities ...string) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	parsed := make([]interpreter.Capability, len(capabilities))
+	for i, capability := range capabilities {
+		c, err := interpreter.ParseCapability(capability)
+		if err != nil {
+			return fmt.Errorf("dreamlang: Grant: %w", err)
+		}
+		parsed[i] = c
+	}
+
+	rt.interpreter.Grant(parsed...)
+	return nil
+}
+
+// convertGlobal 把 Go 的值 value 转换为名为 name 的全局常量或模块成员的类型和值。
+func convertGlobal(name string, value any) (checker.Type, interpreter.Value, error) {
+	reflected := reflect.ValueOf(value)
+	converted, err := toValue(name, reflected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := checker.Type(checker.Null)
+	if reflected.IsValid() {
+		if t, err = typeOf(reflected.Type()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return t, converted, nil
+}
+
+// Eval 解析、检查并执行源代码 src。
+//
+// 参数:
+//   - ctx: 取消 ctx 会停止程序中的全部 goroutine。
+//   - src: DreamLang 源代码。
+//
+// 返回值:
+//   - any: src 的最后一条语句是表达式语句时为该表达式的值（按包文档中转换为 any 的规则），否则为 nil。
+//   - error: 语法错误、类型错误（多个类型错误由 errors.Join 合并）、运行时未被捕获的错误、
+//     超过资源限制时的 *LimitError，或者 ctx 被取消时的 context.Cause(ctx)。
+//     存在语法错误或类型错误时程序不会被执行，其中的声明也不会加入全局作用域。
+func (rt *Runtime) Eval(ctx context.Context, src string) (any, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	program, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rt.options.DisableTypeCheck {
+		if typeErrors := rt.checker.Check(program); len(typeErrors) > 0 {
+			return nil, errors.Join(typeErrors...)
+		}
+	}
+
+	result, err := rt.interpreter.EvalContext(ctx, program)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromValue(result, anyType, nil)
+}
+
+// Call 以 args 调用全局函数 name，并返回转换为 any 的返回值。args 按包文档中的规则转换为 DreamLang 的值。
+// name 是源代码中定义的函数时，转换后的实参按它声明的参数检查：实参过多、缺少没有默认值的参数，
+// 或者实参不属于参数的类型时返回错误，而不执行函数。
+func (rt *Runtime) Call(ctx context.Context, name string, args ...any) (any, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	callee, exists := rt.interpreter.Lookup(name)
+	if !exists {
+		return nil, fmt.Errorf("dreamlang: Call: undefined: %s", name)
+	}
+
+	values := make([]interpreter.Value, len(args))
+	for i, arg := range args {
+		value, err := toValue(fmt.Sprintf("argument %d", i+1), reflect.ValueOf(arg))
+		if err != nil {
+			return nil, fmt.Errorf("dreamlang: Call %s: %w", name, err)
+		}
+		values[i] = value
+	}
+
+	result, err := rt.interpreter.CallContext(ctx, callee, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromValue(result, anyType, nil)
+}
+
+// parse 解析 src，把解析器以 panic 报告的语法错误转换为 error。
+func parse(src string) (program ast.BlockStmt, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("dreamlang: syntax error: %s", strings.TrimSpace(fmt.Sprint(r)))
+		}
+	}()
+
+	return parser.Parse(src), nil
+}
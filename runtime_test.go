@@ -0,0 +1,80 @@
+package dreamlang_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+func TestCallChecksArguments(t *testing.T) {
+	rt := dreamlang.NewRuntime(dreamlang.Options{})
+	_, err := rt.Eval(context.Background(), `
+		fn f(a: number): number { return a; }
+		fn greet(name: string, greeting: string = "hello"): string { return greeting + " " + name; }
+		fn sum(...xs: []number): number { let total = 0; foreach x in xs { total += x; } return total; }
+		fn first<T>(items: []T): T { return items[0]; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := []struct {
+		name string
+		args []any
+		want any
+	}{
+		{"f", []any{2}, 2.0},
+		{"greet", []any{"world"}, "hello world"},
+		{"greet", []any{"world", "hi"}, "hi world"},
+		{"sum", nil, 0.0},
+		{"sum", []any{1, 2, 3}, 6.0},
+		{"first", []any{[]any{"x", 1}}, "x"},
+	}
+	for _, test := range valid {
+		got, err := rt.Call(context.Background(), test.name, test.args...)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Call(%s, %v) = %#v, %v; want %#v", test.name, test.args, got, err, test.want)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		args []any
+		err  string
+	}{
+		{"f", nil, "missing argument a in call to f"},
+		{"f", []any{"str"}, "argument a of f must be number, not string"},
+		{"f", []any{1, 2}, "too many arguments in call to f: got 2, want 1"},
+		{"greet", []any{"world", 1}, "argument greeting of greet must be string, not number"},
+		{"sum", []any{1, "2"}, "rest arguments of sum must be []number"},
+		{"first", []any{"x"}, "argument items of first must be []T, not string"},
+	}
+	for _, test := range invalid {
+		_, err := rt.Call(context.Background(), test.name, test.args...)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Call(%s, %v) error = %v, want %q", test.name, test.args, err, test.err)
+		}
+	}
+}
+
+func TestEvalCyclicValue(t *testing.T) {
+	var stdout strings.Builder
+	rt := dreamlang.NewRuntime(dreamlang.Options{Stdout: &stdout})
+
+	_, err := rt.Eval(context.Background(), `let a: []any = []; a.push(a); println(a); a;`)
+	if err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Fatalf("err = %v, want an error for the cyclic result", err)
+	}
+	if got := stdout.String(); got != "[[...]]\n" {
+		t.Fatalf("stdout = %q, want %q", got, "[[...]]\n")
+	}
+
+	if _, err := rt.Eval(context.Background(), `fn self(): map[string]any { let m: map[string]any = {}; m["m"] = m; return m; }`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.Call(context.Background(), "self"); err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Fatalf("err = %v, want an error for the cyclic result", err)
+	}
+}
@@ -0,0 +1,86 @@
+package stdlib
+
+import (
+	"math"
+
+	"dreamlang/interpreter"
+)
+
+// 内置函数的实参通常已经过类型检查，但宿主程序可以关闭类型检查，因此这里仍然检查实参的类型。
+
+// arg 返回第 i 个实参，没有传入时为 null。
+func arg(args []interpreter.Value, i int) interpreter.Value {
+	if i < len(args) {
+		return args[i]
+	}
+
+	return nil
+}
+
+func stringArg(in *interpreter.Interpreter, args []interpreter.Value, i int, name string) string {
+	s, ok := arg(args, i).(string)
+	if !ok {
+		in.Throwf("%s must be a string, not %s", name, interpreter.TypeName(arg(args, i)))
+	}
+
+	return s
+}
+
+func numberArg(in *interpreter.Interpreter, args []interpreter.Value, i int, name string) float64 {
+	n, ok := arg(args, i).(float64)
+	if !ok {
+		in.Throwf("%s must be a number, not %s", name, interpreter.TypeName(arg(args, i)))
+	}
+
+	return n
+}
+
+// intArg 返回第 i 个实参的整数值，它必须是 int 范围内的整数。
+// float64(math.MaxInt) 向上舍入为 -math.MinInt，它已经超出 int 的范围，因此上界不包含它。
+func intArg(in *interpreter.Interpreter, args []interpreter.Value, i int, name string) int {
+	n := numberArg(in, args, i, name)
+	if n != math.Trunc(n) || n < math.MinInt || n >= -math.MinInt {
+		in.Throwf("%s must be an integer, not %v", name, n)
+	}
+
+	return int(n)
+}
+
+// int64Arg 返回第 i 个实参的 int64 值。与 intArg 不同，2**63 被当作 math.MaxInt64：
+// number 无法精确表示 math.MaxInt64，最接近它的就是 2**63，这使脚本可以写出完整的 int64 范围。
+func int64Arg(in *interpreter.Interpreter, args []interpreter.Value, i int, name string) int64 {
+	n := numberArg(in, args, i, name)
+	if n != math.Trunc(n) || n < math.MinInt64 || n > -math.MinInt64 {
+		in.Throwf("%s must be an integer, not %v", name, n)
+	}
+	if n == -math.MinInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(n)
+}
+
+func listArg(in *interpreter.Interpreter, args []interpreter.Value, i int, name string) *interpreter.Array {
+	list, ok := arg(args, i).(*interpreter.Array)
+	if !ok {
+		in.Throwf("%s must be a list, not %s", name, interpreter.TypeName(arg(args, i)))
+	}
+
+	return list
+}
+
+// newStringList 把 Go 的字符串切片转换为列表。
+func newStringList(in *interpreter.Interpreter, strs []string) *interpreter.Array {
+	elements := make([]interpreter.Value, len(strs))
+	for i, s := range strs {
+		elements[i] = s
+	}
+
+	return in.NewList(elements)
+}
+
+// newString 把新创建的字符串 s 计入内存分配。
+func newString(in *interpreter.Interpreter, s string) string {
+	in.Allocate(len(s))
+	return s
+}
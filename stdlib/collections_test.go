@@ -0,0 +1,103 @@
+package stdlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+func TestListMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"push returns the length", `let xs = [1]; let n = xs.push(2, 3); [n, xs];`, []any{3.0, []any{1.0, 2.0, 3.0}}},
+		{"pop", `let xs = [1, 2]; let x = xs.pop(); [x, xs, xs.length];`, []any{2.0, []any{1.0}, 1.0}},
+		{"slice", `let xs = [1, 2, 3, 4]; [xs.slice(1), xs.slice(1, 3), xs.slice(4), xs];`, []any{[]any{2.0, 3.0, 4.0}, []any{2.0, 3.0}, []any{}, []any{1.0, 2.0, 3.0, 4.0}}},
+		{"map", `[1, 2, 3].map(x -> x * 2);`, []any{2.0, 4.0, 6.0}},
+		{"map with index", `["a", "b"].map((s, i) -> s + i);`, []any{"a0", "b1"}},
+		{"filter", `[1, 2, 3, 4].filter(x -> x % 2 == 0);`, []any{2.0, 4.0}},
+		{"reduce", `[1, 2, 3].reduce((sum, x) -> sum + x, 10);`, 16.0},
+		{"reduce to another type", `[1, 2].reduce((s, x) -> s + x, "");`, "12"},
+		{"sort numbers", `[3, 1, 2].sort();`, []any{1.0, 2.0, 3.0}},
+		{"sort strings", `["b", "c", "a"].sort();`, []any{"a", "b", "c"}},
+		{"sort with a comparator", `[1, 3, 2].sort((a, b) -> b - a);`, []any{3.0, 2.0, 1.0}},
+		{"sort is stable and in place", `let xs = [[2, 0], [1, 1], [2, 2], [1, 3]]; xs.sort((a, b) -> a[0] - b[0]); xs.map(p -> p[1]);`, []any{1.0, 3.0, 0.0, 2.0}},
+		{"find", `[[1, 5].find(x -> x > 2), [1, 2].find(x -> x > 2)];`, []any{5.0, nil}},
+		{"includes", `[[1, 2].includes(2), [1, 2].includes(3), ["a"].includes("a")];`, []any{true, false, true}},
+		{"reverse in place", `let xs = [1, 2, 3]; xs.reverse(); xs;`, []any{3.0, 2.0, 1.0}},
+		{"zip stops at the shorter list", `[1, 2, 3].zip(["a", "b"]);`, []any{[]any{1.0, "a"}, []any{2.0, "b"}}},
+		{"enumerate", `["a", "b"].enumerate();`, []any{[]any{0.0, "a"}, []any{1.0, "b"}}},
+		{"destructure enumerate", `let out: []string = []; foreach [i, s] in ["a", "b"].enumerate() { out.push(s + i); } out;`, []any{"a0", "b1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAndMap(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"set keeps insertion order", `let s = new Set<number>([3, 1, 3]); s.add(2); s.add(1); [s.size, s.values()];`, []any{3.0, []any{3.0, 1.0, 2.0}}},
+		{"set has and delete", `let s = new Set<string>(["a"]); [s.has("a"), s.delete("a"), s.delete("a"), s.has("a"), s.size];`, []any{true, true, false, false, 0.0}},
+		{"set clear", `let s = new Set<number>([1, 2]); s.clear(); s.size;`, 0.0},
+		{"map get and set", `let m = new Map<string, number>(); m.set("a", 1); m.set("b", 2); m.set("a", 3); [m.get("a"), m.get("z"), m.size];`, []any{3.0, nil, 2.0}},
+		{"map keys, values and entries", `let m = new Map<string, number>([["x", 1], ["y", 2]]); [m.keys(), m.values(), m.entries()];`, []any{[]any{"x", "y"}, []any{1.0, 2.0}, []any{[]any{"x", 1.0}, []any{"y", 2.0}}}},
+		{"map has, delete and clear", `let m = new Map<number, bool>([[1, true]]); let a = [m.has(1), m.delete(1), m.has(1)]; m.set(2, false); m.clear(); [a, m.size];`, []any{[]any{true, true, false}, 0.0}},
+		{"map methods are not keys", `let m = new Map<string, number>(); m.set("get", 1); m.get("get");`, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"pop of an empty list", `let xs: []number = []; xs.pop();`, "pop from empty list"},
+		{"slice out of range", `[1, 2].slice(-1);`, "slice bound -1 out of range [0, 2]"},
+		{"push of the wrong type", `let xs = [1]; xs.push("a");`, "expected number but got string"},
+		{"map callback type", `[1].map((s: string) -> s);`, "expected fn(number, number): string but got fn(string): string"},
+		{"comparator must return a number", `[1, 2].sort((a, b) -> a > b);`, "expected fn(number, number): number but got fn(number, number): bool"},
+		{"set element type", `let s = new Set<number>(); s.add("a");`, "expected number but got string"},
+		{"map key type", `let m = new Map<string, number>(); m.set(1, 1);`, "expected string but got number"},
+		{"length is read-only", `let xs = [1]; xs.length = 0;`, "cannot assign to member length of []number"},
+		{"set size is read-only", `let s = new Set<number>(); s.size = 1;`, "cannot assign to member size of Set<number>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{}, nil, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
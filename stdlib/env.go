@@ -0,0 +1,28 @@
+package stdlib
+
+import (
+	"os"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// envModule 创建 env 模块，用于读取宿主进程的环境变量。导入它需要能力 env，读取变量 NAME 需要能力 env:NAME。
+//
+//	get(name: string): string | null  返回环境变量的值，变量不存在时返回 null。
+func envModule() *Module {
+	m := NewModule("env", "env")
+
+	m.function("get", signature(nullable(checker.String), param{"name", checker.String}),
+		func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			name := stringArg(in, args, 0, "name")
+			in.Require("env", name)
+
+			if value, exists := os.LookupEnv(name); exists {
+				return value
+			}
+			return nil
+		})
+
+	return m
+}
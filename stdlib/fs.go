@@ -0,0 +1,298 @@
+package stdlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// WritableFS 是可以写入的文件系统。fs 模块的写入函数要求 Config.FS 实现它，否则抛出错误。
+// 与 fs.FS 相同，name 是不以 / 开头、以 / 分隔的路径，根目录为 "."。
+type WritableFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+}
+
+// osFS 是以操作系统的根目录为根的 WritableFS。
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(osPath(name))
+}
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(osPath(name), data, perm)
+}
+
+func (osFS) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(osPath(name), perm)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(osPath(name))
+}
+
+func osPath(name string) string {
+	if name == "." {
+		return "/"
+	}
+
+	return "/" + name
+}
+
+// files 是 fs 和 path 模块共享的文件系统：程序中的路径以 / 分隔，相对路径相对于 workDir。
+type files struct {
+	fsys    fs.FS
+	workDir string
+}
+
+// newFiles 按 cfg 创建 fs 和 path 模块使用的文件系统。
+func newFiles(cfg Config) *files {
+	f := &files{fsys: cfg.FS, workDir: cfg.WorkDir}
+	if f.fsys == nil {
+		f.fsys = osFS{}
+		if f.workDir == "" {
+			f.workDir, _ = os.Getwd()
+		}
+	}
+	f.workDir = path.Clean("/" + f.workDir)
+
+	return f
+}
+
+// abs 返回 p 对应的以 / 开头的绝对路径，其中的 . 和 .. 已被消去。
+func (f *files) abs(p string) string {
+	if !path.IsAbs(p) {
+		p = path.Join(f.workDir, p)
+	}
+
+	return path.Clean(p)
+}
+
+// maxSymlinks 是解析一个路径时最多跟随的符号链接数，超过时认为符号链接形成了循环。
+const maxSymlinks = 255
+
+// open 检查程序是否可以使用能力 capability 访问路径 p，返回 p 的绝对路径和它在 fs.FS 中的名称。
+//
+// 操作系统的文件系统中，路径中的符号链接可能指向被授予的目录之外，因此还会解析 p 中的全部符号链接，
+// 检查解析后的真实路径同样被授予，并访问真实路径。
+func (f *files) open(in *interpreter.Interpreter, capability, p string) (abs, name string) {
+	return f.openPath(in, capability, p, true)
+}
+
+// openLink 与 open 相同，但 p 的最后一个元素是符号链接时不跟随它，用于删除符号链接本身。
+func (f *files) openLink(in *interpreter.Interpreter, capability, p string) (abs, name string) {
+	return f.openPath(in, capability, p, false)
+}
+
+func (f *files) openPath(in *interpreter.Interpreter, capability, p string, followLast bool) (abs, name string) {
+	abs = f.abs(p)
+	in.Require(capability, abs)
+
+	real := abs
+	if _, ok := f.fsys.(osFS); ok {
+		var err error
+		if followLast {
+			real, err = resolveSymlinks(abs, 0)
+		} else if real, err = resolveSymlinks(path.Dir(abs), 0); err == nil {
+			real = path.Join(real, path.Base(abs))
+		}
+		if err != nil {
+			in.Throwf("%s: %s", abs, err)
+		}
+		if real != abs {
+			in.Require(capability, real)
+		}
+	}
+
+	if real == "/" {
+		return abs, "."
+	}
+	return abs, real[1:]
+}
+
+// resolveSymlinks 返回操作系统的文件系统中绝对路径 abs 的真实路径，其中的符号链接都已被解析。
+// 末尾不存在的部分保持不变，但指向不存在的文件的符号链接仍会被解析，因为写入它会创建链接的目标。
+// hops 为已经跟随的符号链接数。
+func resolveSymlinks(abs string, hops int) (string, error) {
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return filepath.ToSlash(resolved), nil
+	}
+	if abs == "/" {
+		return abs, nil
+	}
+
+	dir, err := resolveSymlinks(path.Dir(abs), hops)
+	if err != nil {
+		return "", err
+	}
+	current := path.Join(dir, path.Base(abs))
+
+	target, err := os.Readlink(current)
+	if err != nil {
+		return current, nil
+	}
+	if hops++; hops > maxSymlinks {
+		return "", errors.New("too many levels of symbolic links")
+	}
+	if !path.IsAbs(target) {
+		target = path.Join(dir, target)
+	}
+
+	return resolveSymlinks(path.Clean(target), hops)
+}
+
+// writable 返回可以写入的文件系统，文件系统只读时抛出错误。
+func (f *files) writable(in *interpreter.Interpreter, function string) WritableFS {
+	w, ok := f.fsys.(WritableFS)
+	if !ok {
+		in.Throwf("%s: file system is read-only", function)
+	}
+
+	return w
+}
+
+// fail 在 err 不为 nil 时抛出错误，错误信息中的路径为程序中的绝对路径 abs，而不是 fs.FS 中的名称。
+func fail(in *interpreter.Interpreter, function, abs string, err error) {
+	if err == nil {
+		return
+	}
+
+	var pathError *fs.PathError
+	if errors.As(err, &pathError) {
+		err = pathError.Err
+	}
+	in.Throwf("%s %s: %s", function, abs, err)
+}
+
+// fsModule 创建 fs 模块。导入它需要能力 fs，读取路径 p 需要能力 fs.read:p，写入需要能力 fs.write:p，
+// 例如 fs.read:/data 允许读取 /data 下的全部文件。路径以 / 分隔，相对路径相对于 Config.WorkDir。
+// 访问操作系统的文件系统时，p 中的符号链接解析之后的真实路径也必须被授予。
+//
+//	readFile(path: string): string
+//	writeFile(path: string, data: string): void      创建或覆盖文件
+//	readDir(path: string): []string                  目录中的文件名，按名称排序
+//	stat(path: string): map[string]any | null        {name, size, isDir, mode, modTime}，modTime 为 Unix 时间（秒）；路径不存在时返回 null
+//	mkdirAll(path: string): void
+//	remove(path: string): void                       删除文件或空目录
+//	walk(path: string, f: fn(string, bool): void): void  按字典序遍历 path 下的全部文件和目录（包括 path 本身），以绝对路径和是否为目录调用 f
+func fsModule(f *files) *Module {
+	m := NewModule("fs", "fs")
+	p := param{"path", checker.String}
+
+	m.function("readFile", signature(checker.String, p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.read", stringArg(in, args, 0, "path"))
+		data, err := fs.ReadFile(f.fsys, name)
+		fail(in, "readFile", abs, err)
+		return newString(in, string(data))
+	})
+
+	m.function("writeFile", signature(checker.Void, p, param{"data", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.write", stringArg(in, args, 0, "path"))
+		data := stringArg(in, args, 1, "data")
+		fail(in, "writeFile", abs, f.writable(in, "writeFile").WriteFile(name, []byte(data), 0o644))
+		return nil
+	})
+
+	m.function("readDir", signature(&checker.ListType{Element: checker.String}, p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.read", stringArg(in, args, 0, "path"))
+		entries, err := fs.ReadDir(f.fsys, name)
+		fail(in, "readDir", abs, err)
+
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		return newStringList(in, names)
+	})
+
+	info := &checker.MapType{Key: checker.String, Value: checker.Any}
+	m.function("stat", signature(nullable(info), p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.read", stringArg(in, args, 0, "path"))
+		stat, err := fs.Stat(f.fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		fail(in, "stat", abs, err)
+
+		result := interpreter.NewMap()
+		result.Set("name", path.Base(abs))
+		result.Set("size", float64(stat.Size()))
+		result.Set("isDir", stat.IsDir())
+		result.Set("mode", float64(stat.Mode().Perm()))
+		result.Set("modTime", float64(stat.ModTime().UnixNano())/1e9)
+		return result
+	})
+
+	m.function("mkdirAll", signature(checker.Void, p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.write", stringArg(in, args, 0, "path"))
+		fail(in, "mkdirAll", abs, f.writable(in, "mkdirAll").MkdirAll(name, 0o755))
+		return nil
+	})
+
+	m.function("remove", signature(checker.Void, p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.openLink(in, "fs.write", stringArg(in, args, 0, "path"))
+		fail(in, "remove", abs, f.writable(in, "remove").Remove(name))
+		return nil
+	})
+
+	visit := &checker.FunctionType{Parameters: []checker.Type{checker.String, checker.Bool}, ReturnType: checker.Void}
+	m.function("walk", signature(checker.Void, p, param{"f", visit}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		abs, name := f.open(in, "fs.read", stringArg(in, args, 0, "path"))
+		visit := arg(args, 1)
+
+		err := fs.WalkDir(f.fsys, name, func(current string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel := current
+			if name != "." {
+				rel = current[len(name):]
+			}
+			in.Call(visit, path.Join(abs, rel), entry.IsDir())
+			return nil
+		})
+		fail(in, "walk", abs, err)
+		return nil
+	})
+
+	return m
+}
+
+// pathModule 创建 path 模块，用于处理以 / 分隔的路径。除 abs 外的函数只处理字符串，不访问文件系统。
+//
+//	join(...parts: string): string  连接 parts 并消去其中的 . 和 ..
+//	base(path: string): string      最后一个元素
+//	dir(path: string): string       除最后一个元素以外的部分
+//	ext(path: string): string       扩展名，包括开头的 .
+//	abs(path: string): string       绝对路径，相对路径相对于 Config.WorkDir
+func pathModule(f *files) *Module {
+	m := NewModule("path", "")
+	p := param{"path", checker.String}
+
+	join := signature(checker.String)
+	join.Rest = &checker.ListType{Element: checker.String}
+	m.function("join", join, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		parts := make([]string, len(args))
+		for i := range args {
+			parts[i] = stringArg(in, args, i, "parts")
+		}
+		return newString(in, path.Join(parts...))
+	})
+
+	unary := map[string]func(string) string{"base": path.Base, "dir": path.Dir, "ext": path.Ext, "abs": f.abs}
+	for name, fn := range unary {
+		m.function(name, signature(checker.String, p), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			return newString(in, fn(stringArg(in, args, 0, "path")))
+		})
+	}
+
+	return m
+}
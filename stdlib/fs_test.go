@@ -0,0 +1,209 @@
+package stdlib_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"dreamlang"
+)
+
+// testFS 是 fs 模块测试使用的只读文件系统，程序中的 /data/a.txt 对应其中的 data/a.txt。
+var testFS = fstest.MapFS{
+	"data/a.txt":     {Data: []byte("hello")},
+	"data/b/c.txt":   {Data: []byte("nested")},
+	"secret/key.txt": {Data: []byte("secret")},
+}
+
+// eval 以 opts 创建 Runtime，授予 grants，然后执行 src 并返回最后一个表达式的值。
+func eval(t *testing.T, opts dreamlang.Options, grants []string, src string) (any, error) {
+	t.Helper()
+
+	rt := dreamlang.NewRuntime(opts)
+	if err := rt.Grant(grants...); err != nil {
+		t.Fatal(err)
+	}
+
+	return rt.Eval(context.Background(), src)
+}
+
+func TestFS(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{
+			name: "readFile",
+			src:  `import fs from "fs"; fs.readFile("/data/a.txt");`,
+			want: "hello",
+		},
+		{
+			name: "readFile relative to the working directory",
+			src:  `import fs from "fs"; fs.readFile("b/c.txt");`,
+			want: "nested",
+		},
+		{
+			name: "readDir",
+			src:  `import fs from "fs"; fs.readDir("/data");`,
+			want: []any{"a.txt", "b"},
+		},
+		{
+			name: "stat of a file",
+			src:  `import fs from "fs"; let info = fs.stat("/data/a.txt"); if info == null { throw "missing"; } [info["name"], info["size"], info["isDir"]];`,
+			want: []any{"a.txt", 5.0, false},
+		},
+		{
+			name: "stat of a missing file",
+			src:  `import fs from "fs"; fs.stat("/data/missing.txt");`,
+			want: nil,
+		},
+		{
+			name: "walk",
+			src: `import fs from "fs";
+				let visited: []string = [];
+				fs.walk("/data", fn(path: string, isDir: bool) { if isDir { visited.push(path + "/"); } else { visited.push(path); } });
+				visited;`,
+			want: []any{"/data/", "/data/a.txt", "/data/b/", "/data/b/c.txt"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{FS: testFS, WorkDir: "/data"}, []string{"fs.read:/data"}, test.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFSErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		grants []string
+		src    string
+		err    string
+	}{
+		{
+			name:   "read outside the granted directory",
+			grants: []string{"fs.read:/data"},
+			src:    `import fs from "fs"; fs.readFile("/secret/key.txt");`,
+			err:    "permission denied: fs.read:/secret/key.txt",
+		},
+		{
+			name:   "dot-dot does not escape the grant",
+			grants: []string{"fs.read:/data"},
+			src:    `import fs from "fs"; fs.readFile("/data/../secret/key.txt");`,
+			err:    "permission denied: fs.read:/secret/key.txt",
+		},
+		{
+			name:   "write to a read-only file system",
+			grants: []string{"fs.write:/data"},
+			src:    `import fs from "fs"; fs.writeFile("/data/new.txt", "x");`,
+			err:    "writeFile: file system is read-only",
+		},
+		{
+			name:   "write without a grant",
+			grants: []string{"fs.read:/data"},
+			src:    `import fs from "fs"; fs.writeFile("/data/new.txt", "x");`,
+			err:    "permission denied: fs.write:/data/new.txt",
+		},
+		{
+			name:   "missing file",
+			grants: []string{"fs.read:/data"},
+			src:    `import fs from "fs"; fs.readFile("/data/missing.txt");`,
+			err:    "readFile /data/missing.txt: file does not exist",
+		},
+		{
+			name: "import without a grant",
+			src:  `import fs from "fs";`,
+			err:  "permission denied: import fs requires capability fs",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{FS: testFS}, test.grants, test.src)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("err = %v, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestFSSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+	secret := filepath.Join(root, "secret")
+	for _, dir := range []string{data, secret} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(secret, "key.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"link":     filepath.Join(secret, "key.txt"),
+		"dir":      secret,
+		"dangling": filepath.Join(secret, "new.txt"),
+		"inside":   "a.txt",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(data, name)); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+
+	// 根目录本身可能位于符号链接之下，例如 macOS 的 /tmp，因此授予解析之后的真实路径。
+	realData, err := filepath.EvalSymlinks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grants := []string{"fs.read:" + filepath.ToSlash(realData), "fs.write:" + filepath.ToSlash(realData)}
+
+	denied := map[string]string{
+		"file symlink":      `fs.readFile("link");`,
+		"directory symlink": `fs.readFile("dir/key.txt");`,
+		"stat":              `fs.stat("link");`,
+		"dangling write":    `fs.writeFile("dangling", "x");`,
+	}
+	for name, call := range denied {
+		t.Run(name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{WorkDir: realData}, grants, `import fs from "fs"; `+call)
+			if err == nil || !strings.Contains(err.Error(), "permission denied") {
+				t.Fatalf("err = %v, want permission denied", err)
+			}
+		})
+	}
+	if _, err := os.Lstat(filepath.Join(secret, "new.txt")); !os.IsNotExist(err) {
+		t.Fatalf("write through a dangling symlink created its target: %v", err)
+	}
+
+	t.Run("symlink inside the grant", func(t *testing.T) {
+		got, err := eval(t, dreamlang.Options{WorkDir: realData}, grants, `import fs from "fs"; fs.readFile("inside");`)
+		if err != nil || got != "hello" {
+			t.Fatalf("got %v, %v; want hello", got, err)
+		}
+	})
+
+	t.Run("remove deletes the symlink itself", func(t *testing.T) {
+		if _, err := eval(t, dreamlang.Options{WorkDir: realData}, grants, `import fs from "fs"; fs.remove("link");`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(secret, "key.txt")); err != nil {
+			t.Fatalf("target of the removed symlink: %v", err)
+		}
+	})
+}
@@ -0,0 +1,388 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// defaultHTTPTimeout 是 http 模块的客户端请求的默认超时时间。
+const defaultHTTPTimeout = 30 * time.Second
+
+// Response 和 Request 是 http 模块中响应和请求的类。程序不能直接创建它们，也不能在类型注解中写出它们的名称，
+// 但可以通过类型推断使用它们的字段。
+var (
+	responseType = &checker.ClassType{
+		Name: "Response",
+		Fields: map[string]checker.Type{
+			"status":  checker.Number,
+			"headers": &checker.MapType{Key: checker.String, Value: checker.String},
+			"body":    checker.String,
+		},
+		Methods: map[string]*checker.FunctionType{},
+	}
+	requestType = &checker.ClassType{
+		Name: "Request",
+		Fields: map[string]checker.Type{
+			"method":  checker.String,
+			"path":    checker.String,
+			"query":   &checker.MapType{Key: checker.String, Value: checker.String},
+			"headers": &checker.MapType{Key: checker.String, Value: checker.String},
+			"body":    checker.String,
+		},
+		Methods: map[string]*checker.FunctionType{},
+	}
+
+	responseClass = &interpreter.Class{Name: "Response", Fields: fields("status", "headers", "body")}
+	requestClass  = &interpreter.Class{Name: "Request", Fields: fields("method", "path", "query", "headers", "body")}
+)
+
+func fields(names ...string) []interpreter.Field {
+	fields := make([]interpreter.Field, len(names))
+	for i, name := range names {
+		fields[i] = interpreter.Field{Name: name}
+	}
+
+	return fields
+}
+
+// httpModule 创建 http 模块。导入它需要能力 net；客户端请求 URL 需要能力 net.connect:host:port（省略端口时为协议的默认端口），
+// 例如 net.connect:api.example.com 允许访问 api.example.com 的任意端口，重定向的目标同样需要能力；
+// 服务器监听地址 addr 需要能力 net.listen:addr，例如 net.listen:127.0.0.1。
+//
+//	get(url: string, options?: map[string]any): Response
+//	post(url: string, body: string, options?: map[string]any): Response
+//	request(method: string, url: string, options?: map[string]any): Response
+//	serve(addr: string, handler: fn(Request): any, ready?: fn(string): void): void
+//
+// options 可以包含 headers（map[string]string）、body（string，仅 request）和 timeout（毫秒，默认 30 秒）。
+// Response 的字段为 status、headers 和 body；请求失败（例如无法连接或超时）时抛出错误，状态码不是 2xx 时不会抛出错误。
+//
+// serve 在 addr 上启动 HTTP 服务器并一直运行，直到程序被取消；通常用 spawn 在新的 goroutine 中运行它。
+// 请求在调用 serve 的 goroutine 中依次处理，handler 收到的 Request 的字段为 method、path、query、headers 和 body。
+// handler 返回字符串时以状态码 200 返回该字符串；返回字典（或有这些字段的实例）时取其中的 status（默认 200）、
+// headers 和 body；返回 null 时返回状态码 204。handler 抛出的错误以状态码 500 返回，服务器继续运行。
+// 监听开始后以实际监听的地址调用 ready，例如 addr 为 127.0.0.1:0 时可以由此得到端口。
+func httpModule(client *http.Client) *Module {
+	m := NewModule("http", "net")
+	response := &checker.InstanceType{Class: responseType}
+	options := param{"options", &checker.MapType{Key: checker.String, Value: checker.Any}}
+	u := param{"url", checker.String}
+
+	get := signature(response, u, options)
+	get.Optional = 1
+	m.function("get", get, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return doRequest(in, client, "GET", stringArg(in, args, 0, "url"), nil, arg(args, 1))
+	})
+
+	post := signature(response, u, param{"body", checker.String}, options)
+	post.Optional = 1
+	m.function("post", post, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		body := stringArg(in, args, 1, "body")
+		return doRequest(in, client, "POST", stringArg(in, args, 0, "url"), &body, arg(args, 2))
+	})
+
+	request := signature(response, param{"method", checker.String}, u, options)
+	request.Optional = 1
+	m.function("request", request, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return doRequest(in, client, strings.ToUpper(stringArg(in, args, 0, "method")), stringArg(in, args, 1, "url"), nil, arg(args, 2))
+	})
+
+	handler := &checker.FunctionType{Parameters: []checker.Type{&checker.InstanceType{Class: requestType}}, ReturnType: checker.Any}
+	ready := &checker.FunctionType{Parameters: []checker.Type{checker.String}, ReturnType: checker.Void}
+	serve := signature(checker.Void, param{"addr", checker.String}, param{"handler", handler}, param{"ready", ready})
+	serve.Optional = 1
+	m.function("serve", serve, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		serveHTTP(in, stringArg(in, args, 0, "addr"), arg(args, 1), arg(args, 2))
+		return nil
+	})
+
+	return m
+}
+
+// doRequest 发送 HTTP 请求并返回 Response 的实例。body 不为 nil 时覆盖 options 中的 body。
+func doRequest(in *interpreter.Interpreter, base *http.Client, method, rawURL string, body *string, options interpreter.Value) interpreter.Value {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		in.Throwf("http: invalid URL %q", rawURL)
+	}
+	in.Require("net.connect", hostPort(target))
+
+	opts, ok := options.(*interpreter.Map)
+	if !ok && options != nil {
+		in.Throwf("http: options must be a map, not %s", interpreter.TypeName(options))
+	}
+	if opts == nil {
+		opts = interpreter.NewMap()
+	}
+
+	if body == nil {
+		if value, exists := opts.Get("body"); exists && value != nil {
+			s, ok := value.(string)
+			if !ok {
+				in.Throwf("http: body must be a string, not %s", interpreter.TypeName(value))
+			}
+			body = &s
+		}
+	}
+
+	timeout := defaultHTTPTimeout
+	if value, exists := opts.Get("timeout"); exists && value != nil {
+		ms, ok := value.(float64)
+		if !ok || ms <= 0 {
+			in.Throwf("http: timeout must be a positive number of milliseconds")
+		}
+		timeout = time.Duration(ms * float64(time.Millisecond))
+	}
+
+	ctx, cancel := context.WithTimeout(in.Context(), timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = strings.NewReader(*body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		in.Throwf("http: %s", err)
+	}
+	if value, exists := opts.Get("headers"); exists && value != nil {
+		for key, value := range stringMap(in, value, "headers") {
+			req.Header.Set(key, value)
+		}
+	}
+
+	// 重定向的目标同样需要能力。检查只读取宿主程序授予的能力，可以在释放解释器锁时进行。
+	client := *base
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if !in.Allowed("net.connect", hostPort(next.URL)) {
+			return fmt.Errorf("permission denied: net.connect:%s", hostPort(next.URL))
+		}
+		if base.CheckRedirect != nil {
+			return base.CheckRedirect(next, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	var resp *http.Response
+	in.Block(func() {
+		resp, err = client.Do(req)
+	})
+	var data []byte
+	if err == nil {
+		defer resp.Body.Close()
+		data, err = readBody(in, resp.Body)
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			in.Throwf("http: %s %s: timeout after %s", method, rawURL, timeout)
+		}
+		in.Throwf("http: %s", err)
+	}
+
+	return &interpreter.Instance{Class: responseClass, Fields: map[string]interpreter.Value{
+		"status":  float64(resp.StatusCode),
+		"headers": headerMap(in, resp.Header),
+		"body":    string(data),
+	}}
+}
+
+// readBody 在释放解释器锁期间读取 body 并把它计入内存分配。最多只读取剩余的内存预算加一个字节，
+// 因此很大的 body 不会被完整读入内存，超过预算时 Allocate 以 LimitError 停止程序。
+func readBody(in *interpreter.Interpreter, body io.Reader) ([]byte, error) {
+	limit := in.MemoryAvailable()
+	if limit < math.MaxInt64 {
+		limit++
+	}
+
+	var data []byte
+	var err error
+	in.Block(func() {
+		data, err = io.ReadAll(io.LimitReader(body, limit))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	in.Allocate(len(data))
+	return data, nil
+}
+
+// hostPort 返回 URL u 的 host:port，省略端口时为协议的默认端口。
+func hostPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+// headerMap 把 HTTP 头转换为字典，同名的多个值以 ", " 连接。
+func headerMap(in *interpreter.Interpreter, header http.Header) *interpreter.Map {
+	m := interpreter.NewMap()
+	for key, values := range header {
+		value := strings.Join(values, ", ")
+		in.Allocate(len(key) + len(value))
+		m.Set(key, value)
+	}
+
+	return m
+}
+
+// stringMap 把值都为字符串的字典 v 转换为 Go 的 map，what 为错误信息中 v 的名称。
+func stringMap(in *interpreter.Interpreter, v interpreter.Value, what string) map[string]string {
+	m, ok := v.(*interpreter.Map)
+	if !ok {
+		in.Throwf("http: %s must be a map, not %s", what, interpreter.TypeName(v))
+	}
+
+	result := make(map[string]string, len(m.Keys))
+	for _, key := range m.Keys {
+		k, keyOK := key.(string)
+		value, valueOK := m.Values[key].(string)
+		if !keyOK || !valueOK {
+			in.Throwf("http: %s must map strings to strings", what)
+		}
+		result[k] = value
+	}
+
+	return result
+}
+
+// exchange 是服务器收到的一个请求，处理结果通过 reply 返回给处理该请求的 Go goroutine。
+// 请求的 body 由执行 DreamLang 的 goroutine 读取，使读取的长度受内存限制的约束。
+type exchange struct {
+	request *http.Request
+	reply   chan reply
+}
+
+type reply struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// serveHTTP 实现 http.serve：在 addr 上监听，并在当前 goroutine 中依次处理收到的请求，直到程序被取消。
+func serveHTTP(in *interpreter.Interpreter, addr string, handler, ready interpreter.Value) {
+	in.Require("net.listen", addr)
+
+	ctx := in.Context()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		in.Throwf("http: %s", err)
+	}
+
+	exchanges := make(chan *exchange)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ex := &exchange{request: r, reply: make(chan reply, 1)}
+		select {
+		case exchanges <- ex:
+		case <-ctx.Done():
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		case <-r.Context().Done():
+			return
+		}
+
+		select {
+		case rep := <-ex.reply:
+			for key, value := range rep.headers {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(rep.status)
+			io.WriteString(w, rep.body)
+		case <-ctx.Done():
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if ready != nil {
+		in.Call(ready, listener.Addr().String())
+	}
+
+	for {
+		var ex *exchange
+		in.Block(func() {
+			select {
+			case ex = <-exchanges:
+			case <-ctx.Done():
+			}
+		})
+		ex.reply <- handleRequest(in, handler, ex)
+	}
+}
+
+// handleRequest 以 ex 中的请求调用 handler，并把它的返回值转换为响应。
+func handleRequest(in *interpreter.Interpreter, handler interpreter.Value, ex *exchange) (rep reply) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(*interpreter.ErrorValue)
+			if !ok {
+				panic(r)
+			}
+			rep = reply{status: http.StatusInternalServerError, body: err.Message}
+		}
+	}()
+
+	body, err := readBody(in, ex.request.Body)
+	if err != nil {
+		return reply{status: http.StatusBadRequest, body: err.Error()}
+	}
+
+	query := interpreter.NewMap()
+	for key, values := range ex.request.URL.Query() {
+		query.Set(key, values[0])
+	}
+	request := &interpreter.Instance{Class: requestClass, Fields: map[string]interpreter.Value{
+		"method":  ex.request.Method,
+		"path":    ex.request.URL.Path,
+		"query":   query,
+		"headers": headerMap(in, ex.request.Header),
+		"body":    string(body),
+	}}
+
+	switch result := in.Call(handler, request).(type) {
+	case nil:
+		return reply{status: http.StatusNoContent}
+	case string:
+		return reply{status: http.StatusOK, headers: map[string]string{"Content-Type": "text/plain; charset=utf-8"}, body: result}
+	case *interpreter.Map, *interpreter.Instance:
+		rep := reply{status: http.StatusOK}
+		if status := in.Member(result, "status"); status != nil {
+			code, ok := status.(float64)
+			if !ok || code < 100 || code > 999 || code != float64(int(code)) {
+				in.Throwf("http: invalid status %s", interpreter.Stringify(status))
+			}
+			rep.status = int(code)
+		}
+		if headers := in.Member(result, "headers"); headers != nil {
+			rep.headers = stringMap(in, headers, "headers")
+		}
+		if body := in.Member(result, "body"); body != nil {
+			rep.body = interpreter.Stringify(body)
+		}
+		return rep
+	default:
+		in.Throwf("http: handler must return a string, a map or null, not %s", interpreter.TypeName(result))
+	}
+
+	return reply{}
+}
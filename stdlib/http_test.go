@@ -0,0 +1,160 @@
+package stdlib_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+// evalWithURL 与 eval 相同，但在执行之前把 url 定义为全局常量 url。
+func evalWithURL(t *testing.T, opts dreamlang.Options, grants []string, url, src string) (any, error) {
+	t.Helper()
+
+	rt := dreamlang.NewRuntime(opts)
+	if err := rt.Grant(grants...); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.SetGlobal("url", url); err != nil {
+		t.Fatal(err)
+	}
+
+	return rt.Eval(context.Background(), src)
+}
+
+func TestHTTPHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reply", "got "+r.Header.Get("X-Request"))
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	got, err := evalWithURL(t, dreamlang.Options{}, []string{"net.connect:127.0.0.1"}, server.URL+"/tea", `
+		import http from "http";
+		let r = http.get(url, {"headers": {"X-Request": "hello"}});
+		[r.status, r.headers["X-Reply"], r.body];`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{418.0, "got hello", "GET /tea"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	_, err := evalWithURL(t, dreamlang.Options{}, []string{"net.connect:127.0.0.1"}, server.URL, `
+		import http from "http";
+		http.get(url, {"timeout": 20});`)
+	if err == nil || !strings.Contains(err.Error(), "timeout after 20ms") {
+		t.Fatalf("err = %v, want a timeout", err)
+	}
+}
+
+func TestHTTPRedirectToDeniedHost(t *testing.T) {
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the denied host")
+	}))
+	defer denied.Close()
+	// localhost 与 127.0.0.1 是不同的主机名，因此只授予 127.0.0.1 时重定向的目标没有能力。
+	target := strings.Replace(denied.URL, "127.0.0.1", "localhost", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target, http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := evalWithURL(t, dreamlang.Options{}, []string{"net.connect:127.0.0.1"}, server.URL, `
+		import http from "http";
+		http.get(url);`)
+	if err == nil || !strings.Contains(err.Error(), "permission denied: net.connect:localhost:") {
+		t.Fatalf("err = %v, want permission denied for the redirect", err)
+	}
+}
+
+func TestHTTPConnectDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the server")
+	}))
+	defer server.Close()
+
+	_, err := evalWithURL(t, dreamlang.Options{}, []string{"net.connect:api.example.com"}, server.URL, `
+		import http from "http";
+		http.get(url);`)
+	if err == nil || !strings.Contains(err.Error(), "permission denied: net.connect:127.0.0.1:") {
+		t.Fatalf("err = %v, want permission denied", err)
+	}
+}
+
+func TestHTTPServe(t *testing.T) {
+	got, err := evalWithURL(t, dreamlang.Options{}, []string{"net.listen:127.0.0.1:0", "net.connect:127.0.0.1"}, "", `
+		import http from "http";
+		let addresses = chan<string>(1);
+		spawn http.serve("127.0.0.1:0", req -> {
+			if req.path == "/fail" {
+				throw "handler failed";
+			}
+			return {"status": 201, "headers": {"X-Path": req.path}, "body": req.method + " " + req.query["name"] + " " + req.body};
+		}, addr -> { addresses <- addr; });
+
+		let base = "http://" + <-addresses;
+		let created = http.post(base + "/items?name=box", "payload");
+		let failed = http.get(base + "/fail");
+		[created.status, created.headers["X-Path"], created.body, failed.status, failed.body];`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{201.0, "/items", "POST box payload", 500.0, "handler failed"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestHTTPResponseBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 8<<20))
+	}))
+	defer server.Close()
+
+	_, err := evalWithURL(t, dreamlang.Options{MaxMemory: 1 << 20}, []string{"net.connect:127.0.0.1"}, server.URL, `
+		import http from "http";
+		http.get(url);`)
+	if !errors.Is(err, dreamlang.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestHTTPRequestBodyLimit(t *testing.T) {
+	rt := dreamlang.NewRuntime(dreamlang.Options{MaxMemory: 1 << 20})
+	if err := rt.Grant("net.listen:127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	addresses := make(chan string, 1)
+	if err := rt.RegisterFunc("ready", func(addr string) { addresses <- addr }); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		resp, err := http.Post("http://"+<-addresses, "text/plain", bytes.NewReader(bytes.Repeat([]byte("x"), 8<<20)))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	_, err := rt.Eval(context.Background(), `
+		import http from "http";
+		http.serve("127.0.0.1:0", req -> req.body, ready);`)
+	if !errors.Is(err, dreamlang.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+}
@@ -0,0 +1,230 @@
+package stdlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// jsonModule 创建 json 模块。
+//
+//	parse(text: string): any                                 对象解码为字典（保持键的顺序），数组解码为列表
+//	stringify(value: any, indent?: number | string): string  indent 为缩进的空格数或缩进字符串，省略时不换行
+//
+// 语法错误抛出的错误信息中包含出错的行和列（从 1 开始），e.value 为 {message, line, column}。
+// 带类型注解的变量声明以 parse 的调用为初始值时，结果按声明的类型解码并验证，例如 let u: User = json.parse(text)。
+func jsonModule() *Module {
+	m := NewModule("json", "")
+
+	m.Define("parse", signature(checker.Any, param{"text", checker.String}), &interpreter.Builtin{
+		Name:   "json.parse",
+		Decode: true,
+		Fn: func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			return parseJSON(in, stringArg(in, args, 0, "text"))
+		},
+	})
+
+	stringify := signature(checker.String, param{"value", checker.Any}, param{"indent", checker.NewUnion(checker.Number, checker.String)})
+	stringify.Optional = 1
+	m.function("stringify", stringify, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		var indent string
+		switch value := arg(args, 1).(type) {
+		case nil:
+		case string:
+			indent = value
+		default:
+			width := intArg(in, args, 1, "indent")
+			if width < 0 || width > 10 {
+				in.Throwf("indent must be between 0 and 10, not %d", width)
+			}
+			indent = strings.Repeat(" ", width)
+		}
+
+		var buf bytes.Buffer
+		encodeJSON(in, &buf, arg(args, 0), make(map[any]bool))
+		if indent != "" {
+			var indented bytes.Buffer
+			json.Indent(&indented, buf.Bytes(), "", indent)
+			buf = indented
+		}
+		return newString(in, buf.String())
+	})
+
+	return m
+}
+
+// parseJSON 解析 JSON 文本 text。
+func parseJSON(in *interpreter.Interpreter, text string) interpreter.Value {
+	// 解码结果占用的内存按输入长度的几倍估算。
+	in.Allocate(len(text) * 4)
+
+	dec := json.NewDecoder(strings.NewReader(text))
+	value, err := decodeJSON(dec)
+	if err == nil {
+		offset := dec.InputOffset()
+		if _, trailing := dec.Token(); trailing != io.EOF {
+			start := int(offset) + len(text[offset:]) - len(strings.TrimLeft(text[offset:], " \t\r\n"))
+			throwJSONError(in, text, start, "unexpected data after top-level value")
+		}
+	}
+
+	var syntaxError *json.SyntaxError
+	switch {
+	case err == nil:
+		return value
+	case errors.As(err, &syntaxError):
+		throwJSONError(in, text, max(int(syntaxError.Offset)-1, 0), syntaxError.Error())
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		throwJSONError(in, text, len(text), "unexpected end of JSON input")
+	default:
+		throwJSONError(in, text, int(dec.InputOffset()), err.Error())
+	}
+
+	return nil
+}
+
+// decodeJSON 从 dec 中读取一个 JSON 值。与 json.Unmarshal 不同，对象的键保持在文本中的顺序。
+func decodeJSON(dec *json.Decoder) (interpreter.Value, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	if delim == '[' {
+		elements := []interpreter.Value{}
+		for dec.More() {
+			element, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		_, err := dec.Token()
+		return &interpreter.Array{Elements: elements}, err
+	}
+
+	object := interpreter.NewMap()
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeJSON(dec)
+		if err != nil {
+			return nil, err
+		}
+		object.Set(key, value)
+	}
+	_, err = dec.Token()
+	return object, err
+}
+
+// throwJSONError 抛出位于 text 的第 offset 个字节处的语法错误。
+func throwJSONError(in *interpreter.Interpreter, text string, offset int, message string) {
+	offset = min(offset, len(text))
+	line := strings.Count(text[:offset], "\n") + 1
+	column := utf8.RuneCountInString(text[strings.LastIndexByte(text[:offset], '\n')+1:offset]) + 1
+
+	details := interpreter.NewMap()
+	details.Set("message", message)
+	details.Set("line", float64(line))
+	details.Set("column", float64(column))
+
+	in.ThrowError(fmt.Sprintf("json: line %d, column %d: %s", line, column, message), details)
+}
+
+// encodeJSON 把 value 编码为紧凑的 JSON 写入 buf。visiting 记录正在编码的列表、字典和实例，用于发现循环引用。
+//
+// 字典、Map 和实例编码为对象：字典和 Map 的键必须是字符串、数字或 bool，实例按字段的声明顺序编码；
+// 列表和 Set 编码为数组。函数、通道等其它值以及 NaN 和无穷大不能编码。
+func encodeJSON(in *interpreter.Interpreter, buf *bytes.Buffer, value interpreter.Value, visiting map[any]bool) {
+	switch value.(type) {
+	case *interpreter.Array, *interpreter.Map, *interpreter.Instance, *interpreter.Set, *interpreter.HashMap:
+		if visiting[value] {
+			in.Throwf("json: cannot encode cyclic value")
+		}
+		visiting[value] = true
+		defer delete(visiting, value)
+	}
+
+	switch value := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(interpreter.Stringify(value))
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			in.Throwf("json: cannot encode %s", interpreter.Stringify(value))
+		}
+		encoded, _ := json.Marshal(value)
+		buf.Write(encoded)
+	case string:
+		writeJSONString(buf, value)
+	case *interpreter.Array:
+		encodeJSONArray(in, buf, value.Elements, visiting)
+	case *interpreter.Set:
+		encodeJSONArray(in, buf, value.Entries.Keys, visiting)
+	case *interpreter.Map:
+		encodeJSONObject(in, buf, value, visiting)
+	case *interpreter.HashMap:
+		encodeJSONObject(in, buf, value.Entries, visiting)
+	case *interpreter.Instance:
+		fields := interpreter.NewMap()
+		for _, field := range value.Class.Fields {
+			fields.Set(field.Name, value.Fields[field.Name])
+		}
+		encodeJSONObject(in, buf, fields, visiting)
+	default:
+		in.Throwf("json: cannot encode %s", interpreter.TypeName(value))
+	}
+}
+
+func encodeJSONArray(in *interpreter.Interpreter, buf *bytes.Buffer, elements []interpreter.Value, visiting map[any]bool) {
+	buf.WriteByte('[')
+	for i, element := range elements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodeJSON(in, buf, element, visiting)
+	}
+	buf.WriteByte(']')
+}
+
+func encodeJSONObject(in *interpreter.Interpreter, buf *bytes.Buffer, m *interpreter.Map, visiting map[any]bool) {
+	buf.WriteByte('{')
+	for i, key := range m.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		switch key.(type) {
+		case string, float64, bool:
+		default:
+			in.Throwf("json: cannot encode object key of type %s", interpreter.TypeName(key))
+		}
+		writeJSONString(buf, interpreter.Stringify(key))
+		buf.WriteByte(':')
+		encodeJSON(in, buf, m.Values[key], visiting)
+	}
+	buf.WriteByte('}')
+}
+
+// writeJSONString 把 s 编码为 JSON 字符串写入 buf。与 json.Marshal 不同，<、> 和 & 不会被转义。
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,120 @@
+package stdlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+// decodeDecls 是解码测试共用的声明。
+const decodeDecls = `
+	import json from "json";
+	type Ids = []number;
+	interface Point { x: number; y: number; label: string | null }
+	interface Box<T> { value: T }
+	class User { let name: string; let ids: Ids; let role: string = "guest"; }
+	enum Color { Red = "red", Green = "green" }
+`
+
+func TestJSONDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{
+			name: "type alias",
+			src:  `let xs: Ids = json.parse("[1, 2]"); xs;`,
+			want: []any{1.0, 2.0},
+		},
+		{
+			name: "interface",
+			src:  `let p: Point = json.parse("{\"x\": 1, \"y\": 2, \"extra\": true}"); p;`,
+			want: map[string]any{"x": 1.0, "y": 2.0, "extra": true, "label": nil},
+		},
+		{
+			name: "generic interface",
+			src:  `let b: Box<Ids> = json.parse("{\"value\": [3]}"); b;`,
+			want: map[string]any{"value": []any{3.0}},
+		},
+		{
+			name: "class with an aliased field",
+			src:  `let u: User = json.parse("{\"name\": \"ann\", \"ids\": [7]}"); [u.name, u.ids, u.role];`,
+			want: []any{"ann", []any{7.0}, "guest"},
+		},
+		{
+			name: "enum",
+			src:  `let c: Color = json.parse("\"green\""); c;`,
+			want: "green",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, decodeDecls+test.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJSONDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "type alias",
+			src:  `let xs: Ids = json.parse("[\"x\"]");`,
+			err:  "$[0]: expected number but got string",
+		},
+		{
+			name: "interface with a missing field",
+			src:  `let p: Point = json.parse("{\"x\": 1}");`,
+			err:  "$.y: missing field",
+		},
+		{
+			name: "interface with a field of the wrong type",
+			src:  `let p: Point = json.parse("{\"x\": 1, \"y\": \"2\"}");`,
+			err:  "$.y: expected number but got string",
+		},
+		{
+			name: "generic interface",
+			src:  `let b: Box<Ids> = json.parse("{\"value\": [true]}");`,
+			err:  "$.value[0]: expected number but got bool",
+		},
+		{
+			name: "class with an aliased field",
+			src:  `let u: User = json.parse("{\"name\": \"ann\", \"ids\": [\"7\"]}");`,
+			err:  "$.ids[0]: expected number but got string",
+		},
+		{
+			name: "enum",
+			src:  `let c: Color = json.parse("\"blue\"");`,
+			err:  `$: "blue" is not a member of Color`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{}, nil, decodeDecls+test.src)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("err = %v, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestJSONDecodeUnknownType(t *testing.T) {
+	_, err := eval(t, dreamlang.Options{DisableTypeCheck: true}, nil, `import json from "json"; let x: Missing = json.parse("1");`)
+	if err == nil || !strings.Contains(err.Error(), "$: unknown type Missing") {
+		t.Fatalf("err = %v, want unknown type", err)
+	}
+}
@@ -0,0 +1,131 @@
+package stdlib
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// mathModule 创建 math 模块。随机数由以 seed 初始化的 PCG 生成器产生，相同的种子总是得到相同的序列，
+// seed 为 0 时使用当前时间作为种子。
+//
+//	PI、E、Inf、NaN: number
+//	sin、cos、tan、asin、acos、atan、exp、log、log2、log10、sqrt、abs、floor、ceil、round、trunc(x: number): number
+//	atan2(y: number, x: number): number
+//	pow(x: number, y: number): number
+//	min、max(x: number, ...rest: number): number
+//	clamp(x: number, lo: number, hi: number): number
+//	divmod(a: number, b: number): (number, number)  整数的向下取整除法，余数与 b 同号
+//	isNaN、isInf(x: number): bool
+//	seed(seed: number): void                        以 seed 重新初始化随机数生成器
+//	random(): number                                [0, 1) 中的随机数
+//	randomInt(lo: number, hi: number): number       [lo, hi] 中的随机整数，lo 大于 hi 时抛出错误；
+//	                                                hi 为 2 ** 63 时表示 int64 的最大值，因此 randomInt(-(2 ** 63), 2 ** 63) 覆盖整个 int64 范围
+func mathModule(seed int64) *Module {
+	m := NewModule("math", "")
+	x := param{"x", checker.Number}
+
+	m.Define("PI", checker.Number, math.Pi)
+	m.Define("E", checker.Number, math.E)
+	m.Define("Inf", checker.Number, math.Inf(1))
+	m.Define("NaN", checker.Number, math.NaN())
+
+	unary := map[string]func(float64) float64{
+		"sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+		"asin": math.Asin, "acos": math.Acos, "atan": math.Atan,
+		"exp": math.Exp, "log": math.Log, "log2": math.Log2, "log10": math.Log10,
+		"sqrt": math.Sqrt, "abs": math.Abs,
+		"floor": math.Floor, "ceil": math.Ceil, "round": math.Round, "trunc": math.Trunc,
+	}
+	for name, fn := range unary {
+		m.function(name, signature(checker.Number, x), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			return fn(numberArg(in, args, 0, "x"))
+		})
+	}
+
+	m.function("atan2", signature(checker.Number, param{"y", checker.Number}, x), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return math.Atan2(numberArg(in, args, 0, "y"), numberArg(in, args, 1, "x"))
+	})
+
+	m.function("pow", signature(checker.Number, x, param{"y", checker.Number}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return math.Pow(numberArg(in, args, 0, "x"), numberArg(in, args, 1, "y"))
+	})
+
+	extremes := map[string]func(a, b float64) float64{"min": math.Min, "max": math.Max}
+	for name, fn := range extremes {
+		t := signature(checker.Number, x)
+		t.Rest = &checker.ListType{Element: checker.Number}
+		m.function(name, t, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			result := numberArg(in, args, 0, "x")
+			for i := 1; i < len(args); i++ {
+				result = fn(result, numberArg(in, args, i, "rest"))
+			}
+			return result
+		})
+	}
+
+	m.function("clamp", signature(checker.Number, x, param{"lo", checker.Number}, param{"hi", checker.Number}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		value, lo, hi := numberArg(in, args, 0, "x"), numberArg(in, args, 1, "lo"), numberArg(in, args, 2, "hi")
+		if lo > hi {
+			in.Throwf("clamp: lo %v is greater than hi %v", lo, hi)
+		}
+		return math.Max(lo, math.Min(hi, value))
+	})
+
+	divmod := signature(&checker.TupleType{Members: []checker.Type{checker.Number, checker.Number}}, param{"a", checker.Number}, param{"b", checker.Number})
+	m.function("divmod", divmod, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		a, b := intArg(in, args, 0, "a"), intArg(in, args, 1, "b")
+		if b == 0 {
+			in.Throwf("integer divide by zero")
+		}
+		q, r := a/b, a%b
+		if r != 0 && (r < 0) != (b < 0) {
+			q, r = q-1, r+b
+		}
+		return in.NewList([]interpreter.Value{float64(q), float64(r)})
+	})
+
+	m.function("isNaN", signature(checker.Bool, x), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return math.IsNaN(numberArg(in, args, 0, "x"))
+	})
+
+	m.function("isInf", signature(checker.Bool, x), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return math.IsInf(numberArg(in, args, 0, "x"), 0)
+	})
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(newSource(seed))
+
+	m.function("seed", signature(checker.Void, param{"seed", checker.Number}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		rng = rand.New(newSource(int64(intArg(in, args, 0, "seed"))))
+		return nil
+	})
+
+	m.function("random", signature(checker.Number), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return rng.Float64()
+	})
+
+	m.function("randomInt", signature(checker.Number, param{"lo", checker.Number}, param{"hi", checker.Number}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		lo, hi := int64Arg(in, args, 0, "lo"), int64Arg(in, args, 1, "hi")
+		if lo > hi {
+			in.Throwf("randomInt: lo %d is greater than hi %d", lo, hi)
+		}
+		span := uint64(hi) - uint64(lo)
+		if span == math.MaxUint64 {
+			return float64(int64(rng.Uint64()))
+		}
+		return float64(lo + int64(rng.Uint64N(span+1)))
+	})
+
+	return m
+}
+
+// newSource 创建以 seed 初始化的 PCG 生成器。PCG 的算法是固定的，因此序列不会随 Go 的版本变化。
+func newSource(seed int64) rand.Source {
+	return rand.NewPCG(uint64(seed), uint64(seed)>>32|uint64(seed)<<32)
+}
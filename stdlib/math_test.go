@@ -0,0 +1,104 @@
+package stdlib_test
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"constants", `[math.PI, math.E, math.Inf > 1e308, math.isNaN(math.NaN)];`, []any{math.Pi, math.E, true, true}},
+		{"rounding", `[math.floor(-1.5), math.ceil(-1.5), math.round(2.5), math.trunc(-2.7)];`, []any{-2.0, -1.0, 3.0, -2.0}},
+		{"trig and logs", `[math.sin(0), math.cos(0), math.atan2(1, 1) * 4, math.log(math.E), math.log2(8), math.log10(1000), math.exp(0)];`, []any{0.0, 1.0, math.Pi, 1.0, 3.0, 3.0, 1.0}},
+		{"pow and sqrt", `[math.pow(2, 10), math.sqrt(81), math.abs(-3)];`, []any{1024.0, 9.0, 3.0}},
+		{"min and max", `[math.min(3), math.min(3, 1, 2), math.max(3, 1, 5, 2)];`, []any{3.0, 1.0, 5.0}},
+		{"clamp", `[math.clamp(5, 0, 3), math.clamp(-5, 0, 3), math.clamp(2, 0, 3)];`, []any{3.0, 0.0, 2.0}},
+		{"divmod floors", `[math.divmod(7, 2), math.divmod(-7, 2), math.divmod(7, -2), math.divmod(-7, -2)];`, []any{[]any{3.0, 1.0}, []any{-4.0, 1.0}, []any{-4.0, -1.0}, []any{3.0, -1.0}}},
+		{"isInf", `[math.isInf(math.Inf), math.isInf(-math.Inf), math.isInf(1)];`, []any{true, true, false}},
+		{"random is in [0, 1)", `let ok = true; foreach i in 0..1000 { let r = math.random(); if r < 0 || r >= 1 { ok = false; } } ok;`, true},
+		{"randomInt is in [lo, hi]", `let seen: map[number]bool = {}; foreach i in 0..1000 { seen[math.randomInt(-2, 2)] = true; } seen;`, map[any]any{-2.0: true, -1.0: true, 0.0: true, 1.0: true, 2.0: true}},
+		{"randomInt with lo equal to hi", `math.randomInt(5, 5);`, 5.0},
+		{"randomInt over the full int64 span", `let ok = true; foreach i in 0..1000 { let n = math.randomInt(-(2 ** 63), 2 ** 63); if n < -(2 ** 63) || n > 2 ** 63 || n != math.trunc(n) { ok = false; } } ok;`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, `import math; `+tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMathSeed(t *testing.T) {
+	const sequence = `[math.random(), math.randomInt(0, 1000000), math.randomInt(-(2 ** 63), 2 ** 63)];`
+	run := func(opts dreamlang.Options, src string) any {
+		t.Helper()
+		got, err := eval(t, opts, nil, `import math; `+src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	t.Run("seed restarts the sequence", func(t *testing.T) {
+		got := run(dreamlang.Options{}, `math.seed(42); let a = `+sequence+` math.seed(42); let b = `+sequence+` [a, b];`).([]any)
+		if !reflect.DeepEqual(got[0], got[1]) {
+			t.Fatalf("math.seed(42) produced %v and %v", got[0], got[1])
+		}
+	})
+
+	t.Run("same seed in different runtimes", func(t *testing.T) {
+		a, b := run(dreamlang.Options{Seed: 7}, sequence), run(dreamlang.Options{Seed: 7}, sequence)
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("Seed 7 produced %v and %v", a, b)
+		}
+		if c := run(dreamlang.Options{}, `math.seed(7); `+sequence); !reflect.DeepEqual(a, c) {
+			t.Fatalf("Options.Seed 7 produced %v, math.seed(7) produced %v", a, c)
+		}
+	})
+
+	t.Run("different seeds", func(t *testing.T) {
+		if a, b := run(dreamlang.Options{Seed: 1}, sequence), run(dreamlang.Options{Seed: 2}, sequence); reflect.DeepEqual(a, b) {
+			t.Fatalf("seeds 1 and 2 both produced %v", a)
+		}
+	})
+}
+
+func TestMathErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"randomInt with lo greater than hi", `math.randomInt(3, 2);`, "randomInt: lo 3 is greater than hi 2"},
+		{"randomInt with a fractional bound", `math.randomInt(0, 1.5);`, "hi must be an integer, not 1.5"},
+		{"randomInt above the int64 range", `math.randomInt(0, 2 ** 64);`, "hi must be an integer"},
+		{"clamp with lo greater than hi", `math.clamp(1, 3, 2);`, "clamp: lo 3 is greater than hi 2"},
+		{"divmod by zero", `math.divmod(1, 0);`, "integer divide by zero"},
+		{"divmod of a fraction", `math.divmod(1.5, 1);`, "a must be an integer, not 1.5"},
+		{"divmod outside the int range", `math.divmod(2 ** 63, 1);`, "a must be an integer"},
+		{"arguments are type checked", `math.sqrt("4");`, "expected number but got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{}, nil, `import math; `+tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package stdlib
+
+import (
+	"regexp"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// regexModule 创建 regex 模块。正则表达式使用 Go 的 RE2 语法，匹配时间与输入的长度成线性关系。
+// 除 compile 和 escape 外，函数的第一个参数 re 可以是 Regex，也可以是正则表达式字符串，
+// 它们与 Regex 的同名方法相同（见 checker.RegexClass）。正则表达式也可以写成字面量 /pattern/flags。
+//
+//	compile(pattern: string, flags?: string): Regex   pattern 不合法时抛出错误，e.value 为 {message, column}
+//	escape(s: string): string                        转义 s 中的元字符，使其按字面匹配
+//	match(re: string | Regex, s: string): bool
+//	find(re: string | Regex, s: string): RegexMatch | null
+//	findAll(re: string | Regex, s: string, n?: number): []RegexMatch
+//	replace(re: string | Regex, s: string, replacement: string | fn(RegexMatch): string): string
+//	split(re: string | Regex, s: string, n?: number): []string
+func regexModule() *Module {
+	m := NewModule("regex", "")
+	regex := &checker.InstanceType{Class: checker.RegexClass}
+	re := param{"re", checker.NewUnion(checker.String, regex)}
+
+	compile := signature(regex, param{"pattern", checker.String}, param{"flags", checker.String})
+	compile.Optional = 1
+	m.function("compile", compile, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		var flags string
+		if arg(args, 1) != nil {
+			flags = stringArg(in, args, 1, "flags")
+		}
+		return in.CompileRegex(stringArg(in, args, 0, "pattern"), flags)
+	})
+
+	m.function("escape", signature(checker.String, param{"s", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newString(in, regexp.QuoteMeta(stringArg(in, args, 0, "s")))
+	})
+
+	// 其余函数转发给 Regex 的同名方法。
+	for _, name := range []string{"match", "find", "findAll", "replace", "split"} {
+		method := checker.RegexClass.Methods[name]
+		t := &checker.FunctionType{
+			Parameters:     append([]checker.Type{re.t}, method.Parameters...),
+			ParameterNames: append([]string{re.name}, method.ParameterNames...),
+			Optional:       method.Optional,
+			ReturnType:     method.ReturnType,
+		}
+		m.function(name, t, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			return in.Call(in.Member(regexArg(in, args, 0), name), args[1:]...)
+		})
+	}
+
+	return m
+}
+
+// regexArg 返回第 i 个实参表示的正则表达式，实参为字符串时编译它。
+func regexArg(in *interpreter.Interpreter, args []interpreter.Value, i int) *interpreter.Regex {
+	if re, ok := arg(args, i).(*interpreter.Regex); ok {
+		return re
+	}
+
+	return in.CompileRegex(stringArg(in, args, i, "re"), "")
+}
@@ -0,0 +1,133 @@
+package stdlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{
+			name: "match with a string pattern",
+			src:  `import regex from "regex"; [regex.match("b+", "abbc"), regex.match("x", "abc")];`,
+			want: []any{true, false},
+		},
+		{
+			name: "match and test methods agree",
+			src:  `let re = /^a.c$/i; [re.match("ABC"), re.test("ABC"), re.match("abcd")];`,
+			want: []any{true, true, false},
+		},
+		{
+			name: "compile with flags",
+			src:  `import regex from "regex"; let re = regex.compile("^b", "m"); [re.source, re.flags, re.match("a\nb")];`,
+			want: []any{"^b", "m", true},
+		},
+		{
+			name: "find",
+			src:  `import regex from "regex"; let m = regex.find("(\\d+)-(\\d+)", "ab 12-34"); if m == null { throw "no match"; } [m.text, m.index, m.groups];`,
+			want: []any{"12-34", 3.0, []any{"12", "34"}},
+		},
+		{
+			name: "find without a match",
+			src:  `import regex from "regex"; regex.find("x", "abc");`,
+			want: nil,
+		},
+		{
+			name: "index counts characters",
+			src:  `import regex from "regex"; let m = regex.find("b", "äöb"); if m == null { throw "no match"; } m.index;`,
+			want: 2.0,
+		},
+		{
+			name: "named and optional groups",
+			src:  `import regex from "regex"; let m = regex.find("(?P<key>\\w+)=(?P<value>\\w+)?", "k="); if m == null { throw "no match"; } [m.named["key"], m.named["value"], m.groups];`,
+			want: []any{"k", nil, []any{"k", nil}},
+		},
+		{
+			name: "findAll",
+			src:  `import regex from "regex"; [regex.findAll("\\d", "a1b2c3").map(m -> m.text), regex.findAll("\\d", "a1b2c3", 2).length];`,
+			want: []any{[]any{"1", "2", "3"}, 2.0},
+		},
+		{
+			name: "replace with a template",
+			src:  `import regex from "regex"; regex.replace("(?P<first>\\w+) (\\w+)", "hello world", "$2 ${first}");`,
+			want: "world hello",
+		},
+		{
+			name: "replace with a callback",
+			src:  `import regex from "regex"; regex.replace(/\d+/, "a1b22", fn(m: RegexMatch): string { return "<" + m.text + ">"; });`,
+			want: "a<1>b<22>",
+		},
+		{
+			name: "split",
+			src:  `import regex from "regex"; [regex.split(",\\s*", "a, b,c"), regex.split(",", "a,b,c", 2)];`,
+			want: []any{[]any{"a", "b", "c"}, []any{"a", "b,c"}},
+		},
+		{
+			name: "escape",
+			src:  `import regex from "regex"; let s = regex.escape("a.b*"); [s, regex.match(s, "a.b*"), regex.match(s, "axb")];`,
+			want: []any{`a\.b\*`, true, false},
+		},
+		{
+			name: "literal is not division",
+			src:  `let a = 6; let b = 3; [a / b, /a/.match("a")];`,
+			want: []any{2.0, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "invalid pattern reports the column",
+			src:  `import regex from "regex"; regex.compile("ab(c");`,
+			err:  "invalid regular expression at column",
+		},
+		{
+			name: "error value has message and column",
+			src:  `import regex from "regex"; try { regex.compile("ab\\q"); } catch (e) { throw "column " + e.value["column"]; }`,
+			err:  "column 3",
+		},
+		{
+			name: "match argument is type checked",
+			src:  `/a/.match(1);`,
+			err:  "expected string but got number",
+		},
+		{
+			name: "invalid literal is a syntax error",
+			src:  `/a(/.match("a");`,
+			err:  "regular expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{}, nil, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package stdlib 实现 DreamLang 的标准库模块。程序通过 import 语句导入它们，例如 import env;。
+//
+// 访问宿主环境的模块需要宿主程序授予相应的能力（见 interpreter.Capability）:
+//   - env: 读取环境变量，范围为变量名，例如 env:HOME。
+//   - fs.read、fs.write: 读取和写入文件，范围为绝对路径，例如 fs.read:/data。
+//   - net.connect、net.listen: 发送 HTTP 请求和监听端口，范围分别为 host:port 和监听地址，例如 net.connect:api.example.com。
+//   - time: 读取时钟和等待，即 time 模块的 now、since 和 sleep；没有范围。
+package stdlib
+
+import (
+	"io/fs"
+	"net/http"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// Module 是一个标准库模块，同时包含类型检查使用的类型和运行时的值。
+type Module struct {
+	Type  *checker.ModuleType
+	Value *interpreter.Module
+}
+
+// Config 配置标准库模块的行为。
+//
+// 字段:
+//   - Seed: math 模块的随机数生成器的初始种子，相同的种子得到相同的随机数序列；为 0 时使用当前时间。
+//   - FS: fs 模块访问的文件系统，程序中的路径 /a/b 对应其中的 a/b；为 nil 时为操作系统的文件系统。
+//     写入文件要求它实现 WritableFS，例如测试中可以使用只读的 fstest.MapFS。
+//   - WorkDir: fs 和 path 模块中相对路径的基准目录；为空字符串时，FS 为 nil 则为进程的当前目录，否则为 /。
+//   - Clock: time 模块的时钟，为 nil 时使用系统时间。
+//   - HTTPClient: http 模块发送请求使用的客户端，为 nil 时为 http.DefaultClient；例如测试中可以使用 httptest.Server 的客户端。
+type Config struct {
+	Seed       int64
+	FS         fs.FS
+	WorkDir    string
+	Clock      Clock
+	HTTPClient *http.Client
+}
+
+// Modules 返回按 cfg 配置的全部标准库模块。
+func Modules(cfg Config) []*Module {
+	files := newFiles(cfg)
+	clock := cfg.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return []*Module{
+		envModule(),
+		stringsModule(),
+		mathModule(cfg.Seed),
+		jsonModule(),
+		fsModule(files),
+		pathModule(files),
+		timeModule(clock),
+		regexModule(),
+		httpModule(client),
+	}
+}
+
+// Install 把 modules 注册到类型检查器 c 和解释器 in 中，之后的程序可以导入它们。
+func Install(c *checker.Checker, in *interpreter.Interpreter, modules ...*Module) {
+	for _, m := range modules {
+		c.DeclareModule(m.Type)
+		in.RegisterModule(m.Value)
+	}
+}
+
+// NewModule 创建一个没有成员的模块，导入它需要能力 capability（为空字符串时不需要任何能力）。
+func NewModule(name, capability string) *Module {
+	return &Module{
+		Type:  &checker.ModuleType{Name: name, Members: make(map[string]checker.Type)},
+		Value: &interpreter.Module{Name: name, Capability: capability, Members: make(map[string]interpreter.Value)},
+	}
+}
+
+// Define 在模块中定义类型为 t 的成员 name。
+func (m *Module) Define(name string, t checker.Type, value interpreter.Value) {
+	m.Type.Members[name] = t
+	m.Value.Members[name] = value
+}
+
+// function 在模块中定义签名为 t 的内置函数 name，它在调用栈中显示为 module.name。
+func (m *Module) function(name string, t *checker.FunctionType, fn func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value) {
+	m.Define(name, t, &interpreter.Builtin{Name: m.Value.Name + "." + name, Fn: fn})
+}
+
+// param 是函数签名中的一个参数。
+type param struct {
+	name string
+	t    checker.Type
+}
+
+// signature 创建参数为 params、返回类型为 returnType 的函数类型。
+func signature(returnType checker.Type, params ...param) *checker.FunctionType {
+	t := &checker.FunctionType{ReturnType: returnType}
+	for _, p := range params {
+		t.Parameters = append(t.Parameters, p.t)
+		t.ParameterNames = append(t.ParameterNames, p.name)
+	}
+
+	return t
+}
+
+// nullable 返回 t | null。
+func nullable(t checker.Type) checker.Type {
+	return checker.NewUnion(t, checker.Null)
+}
@@ -0,0 +1,149 @@
+package stdlib
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// stringsModule 创建 strings 模块。与下标和 foreach 一致，长度和位置都以 Unicode 码点（rune）而不是字节计算。
+//
+//	len(s: string): number                                   码点的个数
+//	split(s: string, sep: string): []string                  sep 为空字符串时拆分为单个码点
+//	join(parts: []string, sep: string): string
+//	trim(s: string, cutset?: string): string                 省略 cutset 时去除首尾的 Unicode 空白
+//	replace(s: string, old: string, new: string, n?: number) 省略 n 时替换全部
+//	contains(s: string, substr: string): bool
+//	hasPrefix(s: string, prefix: string): bool
+//	hasSuffix(s: string, suffix: string): bool
+//	index(s: string, substr: string): number                 第一次出现的码点位置，不存在时为 -1
+//	upper(s: string): string
+//	lower(s: string): string
+//	repeat(s: string, count: number): string
+//	format(format: string, ...args: any): string             printf 风格的格式化，见 interpreter.Sprintf
+//	runes(s: string): []string                               依次由每个码点组成的字符串
+//	isLetter、isDigit、isSpace、isUpper、isLower(s: string): bool  s 非空且每个码点都属于对应的 Unicode 类别
+func stringsModule() *Module {
+	m := NewModule("strings", "")
+	s := param{"s", checker.String}
+	stringList := &checker.ListType{Element: checker.String}
+
+	m.function("len", signature(checker.Number, s), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return float64(utf8.RuneCountInString(stringArg(in, args, 0, "s")))
+	})
+
+	m.function("split", signature(stringList, s, param{"sep", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newStringList(in, strings.Split(stringArg(in, args, 0, "s"), stringArg(in, args, 1, "sep")))
+	})
+
+	m.function("join", signature(checker.String, param{"parts", stringList}, param{"sep", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		list := listArg(in, args, 0, "parts")
+		parts := make([]string, len(list.Elements))
+		for i, part := range list.Elements {
+			str, ok := part.(string)
+			if !ok {
+				in.Throwf("parts[%d] must be a string, not %s", i, interpreter.TypeName(part))
+			}
+			parts[i] = str
+		}
+		return newString(in, strings.Join(parts, stringArg(in, args, 1, "sep")))
+	})
+
+	trim := signature(checker.String, s, param{"cutset", checker.String})
+	trim.Optional = 1
+	m.function("trim", trim, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		str := stringArg(in, args, 0, "s")
+		if arg(args, 1) == nil {
+			return strings.TrimSpace(str)
+		}
+		return strings.Trim(str, stringArg(in, args, 1, "cutset"))
+	})
+
+	replace := signature(checker.String, s, param{"old", checker.String}, param{"new", checker.String}, param{"n", checker.Number})
+	replace.Optional = 1
+	m.function("replace", replace, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		n := -1
+		if arg(args, 3) != nil {
+			n = intArg(in, args, 3, "n")
+		}
+		return newString(in, strings.Replace(stringArg(in, args, 0, "s"), stringArg(in, args, 1, "old"), stringArg(in, args, 2, "new"), n))
+	})
+
+	predicates := map[string]func(s, substr string) bool{
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+	}
+	for name, predicate := range predicates {
+		m.function(name, signature(checker.Bool, s, param{"substr", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			return predicate(stringArg(in, args, 0, "s"), stringArg(in, args, 1, "substr"))
+		})
+	}
+
+	m.function("index", signature(checker.Number, s, param{"substr", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		str := stringArg(in, args, 0, "s")
+		i := strings.Index(str, stringArg(in, args, 1, "substr"))
+		if i < 0 {
+			return float64(-1)
+		}
+		return float64(utf8.RuneCountInString(str[:i]))
+	})
+
+	m.function("upper", signature(checker.String, s), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newString(in, strings.ToUpper(stringArg(in, args, 0, "s")))
+	})
+
+	m.function("lower", signature(checker.String, s), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newString(in, strings.ToLower(stringArg(in, args, 0, "s")))
+	})
+
+	m.function("repeat", signature(checker.String, s, param{"count", checker.Number}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		str := stringArg(in, args, 0, "s")
+		count := intArg(in, args, 1, "count")
+		if count < 0 {
+			in.Throwf("count must not be negative, not %d", count)
+		}
+		// 在创建字符串之前记录分配，使超过内存限制的 repeat 立即失败。
+		if size := float64(len(str)) * float64(count); size > math.MaxInt32 {
+			in.Allocate(math.MaxInt32)
+			in.Throwf("repeat result of %.0f bytes is too large", size)
+		}
+		in.Allocate(len(str) * count)
+		return strings.Repeat(str, count)
+	})
+
+	format := signature(checker.String, param{"format", checker.String})
+	format.Rest = &checker.ListType{Element: checker.Any}
+	m.function("format", format, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newString(in, in.Sprintf(stringArg(in, args, 0, "format"), args[1:]))
+	})
+
+	m.function("runes", signature(stringList, s), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		str := stringArg(in, args, 0, "s")
+		runes := make([]string, 0, utf8.RuneCountInString(str))
+		for _, r := range str {
+			runes = append(runes, string(r))
+		}
+		return newStringList(in, runes)
+	})
+
+	classes := map[string]func(rune) bool{
+		"isLetter": unicode.IsLetter,
+		"isDigit":  unicode.IsDigit,
+		"isSpace":  unicode.IsSpace,
+		"isUpper":  unicode.IsUpper,
+		"isLower":  unicode.IsLower,
+	}
+	for name, class := range classes {
+		m.function(name, signature(checker.Bool, s), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+			str := stringArg(in, args, 0, "s")
+			return str != "" && strings.IndexFunc(str, func(r rune) bool { return !class(r) }) < 0
+		})
+	}
+
+	return m
+}
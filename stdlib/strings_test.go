@@ -0,0 +1,74 @@
+package stdlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamlang"
+)
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"len counts runes", `strings.len("héllo世界");`, 7.0},
+		{"split", `strings.split("a,b,,c", ",");`, []any{"a", "b", "", "c"}},
+		{"split into runes", `strings.split("中文", "");`, []any{"中", "文"}},
+		{"join", `strings.join(["a", "b", "c"], "-");`, "a-b-c"},
+		{"trim unicode space", "strings.trim(\"\\u{3000} x \\t\");", "x"},
+		{"trim cutset", `strings.trim("xxhixx", "x");`, "hi"},
+		{"replace all", `strings.replace("aaa", "a", "b");`, "bbb"},
+		{"replace n", `strings.replace("aaa", "a", "b", 2);`, "bba"},
+		{"contains", `[strings.contains("seafood", "foo"), strings.contains("seafood", "bar")];`, []any{true, false}},
+		{"prefix and suffix", `[strings.hasPrefix("golang", "go"), strings.hasSuffix("golang", "ng"), strings.hasPrefix("go", "golang")];`, []any{true, true, false}},
+		{"index counts runes", `[strings.index("世界 hello", "hello"), strings.index("abc", "z")];`, []any{3.0, -1.0}},
+		{"upper and lower", `[strings.upper("héllo"), strings.lower("ÀÉÎ")];`, []any{"HÉLLO", "àéî"}},
+		{"repeat", `strings.repeat("ab", 3);`, "ababab"},
+		{"repeat zero times", `strings.repeat("ab", 0);`, ""},
+		{"format", `strings.format("%s has %d items costing %.2f %v", "cart", 3, 9.5, [1]);`, "cart has 3 items costing 9.50 [1]"},
+		{"format width and quotes", `strings.format("[%5s|%-3d|%q]", "ab", 7, "x");`, `[   ab|7  |"x"]`},
+		{"runes", `strings.runes("a中😀");`, []any{"a", "中", "😀"}},
+		{"foreach over a string yields runes", `let out: []string = []; foreach r in "中a" { out.push(r); } out;`, []any{"中", "a"}},
+		{"character classes", `[strings.isLetter("中文"), strings.isDigit("123"), strings.isSpace(" \t"), strings.isUpper("AB"), strings.isLower("aB"), strings.isLetter("")];`, []any{true, true, true, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(t, dreamlang.Options{}, nil, `import strings; `+tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"negative repeat count", `strings.repeat("a", -1);`, "count must not be negative"},
+		{"fractional repeat count", `strings.repeat("a", 1.5);`, "count must be an integer"},
+		{"repeat over the memory limit", `strings.repeat("abc", 1000000000);`, "memory limit"},
+		{"format with too few arguments", `strings.format("%s %s", "a");`, "missing argument for %s"},
+		{"format with the wrong type", `strings.format("%d", "a");`, "%d expects an integer, not string"},
+		{"join of non-strings", `let parts: []any = ["a", 1]; strings.join(parts, ",");`, "parts[1] must be a string, not number"},
+		{"arguments are type checked", `strings.len(1);`, "expected string but got number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{MaxMemory: 1 << 20}, nil, `import strings; `+tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package stdlib
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+	_ "time/tzdata" // 时区数据库，使 time 模块在没有安装 tzdata 的系统上也能使用时区
+
+	"dreamlang/checker"
+	"dreamlang/interpreter"
+)
+
+// Clock 是 time 模块的时钟。宿主程序可以提供模拟的时钟，例如 FakeClock，使依赖时间的程序在测试中得到确定的结果。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+	// Sleep 等待 d，ctx 被取消时立即返回 ctx 的错误。
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// systemClock 是使用系统时间的 Clock。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FakeClock 是只由宿主程序推进的模拟时钟。Sleep 不会真正等待，而是立即把时钟推进 d。
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock 创建当前时间为 now 的 FakeClock。
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Advance(d)
+	return nil
+}
+
+// Advance 把时钟推进 d。
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// timeModule 创建 time 模块。时间表示为自 Unix 纪元（UTC 1970-01-01 00:00:00）以来的毫秒数，
+// 时长表示为毫秒数，因此可以直接用算术运算计算，例如 time.now() + 2 * time.hour。
+//
+// 导入模块不需要能力，但读取时钟的 now、since 和等待的 sleep 需要能力 time，没有授予时抛出错误；
+// 其余函数只做计算，不需要能力。
+//
+// 格式化和解析使用 Go 的布局，即参考时间 Mon Jan 2 15:04:05 MST 2006 的写法。zone 为 IANA 时区名（例如 Asia/Shanghai）、
+// UTC 或 Local，省略时为 UTC。
+//
+//	millisecond、second、minute、hour、day: number
+//	RFC3339、RFC1123、DateTime、DateOnly、TimeOnly: string   常用的布局
+//	now(): number
+//	since(t: number): number                                   从 t 到现在的时长
+//	date(year: number, month: number, day: number, hour?: number, minute?: number, second?: number, zone?: string): number
+//	fields(t: number, zone?: string): map[string]number        {year, month, day, hour, minute, second, millisecond, weekday, yearDay, offset}，
+//	                                                           weekday 中 0 为星期日，offset 为与 UTC 相差的秒数
+//	format(t: number, layout: string, zone?: string): string
+//	parse(layout: string, value: string, zone?: string): number  value 中没有时区信息时按 zone 解释
+//	duration(s: string): number                                解析 1h30m、250ms 等形式的时长
+//	formatDuration(d: number): string
+//	sleep(d: number): void                                     等待期间其它 goroutine 继续执行，程序被取消时立即停止
+func timeModule(clock Clock) *Module {
+	m := NewModule("time", "")
+	t := param{"t", checker.Number}
+	zone := param{"zone", checker.String}
+
+	for name, d := range map[string]time.Duration{"millisecond": time.Millisecond, "second": time.Second, "minute": time.Minute, "hour": time.Hour, "day": 24 * time.Hour} {
+		m.Define(name, checker.Number, fromDuration(d))
+	}
+
+	for name, layout := range map[string]string{"RFC3339": time.RFC3339, "RFC1123": time.RFC1123, "DateTime": time.DateTime, "DateOnly": time.DateOnly, "TimeOnly": time.TimeOnly} {
+		m.Define(name, checker.String, layout)
+	}
+
+	m.function("now", signature(checker.Number), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		in.Require("time", "")
+		return fromTime(clock.Now())
+	})
+
+	m.function("since", signature(checker.Number, t), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		in.Require("time", "")
+		return fromTime(clock.Now()) - numberArg(in, args, 0, "t")
+	})
+
+	date := signature(checker.Number,
+		param{"year", checker.Number}, param{"month", checker.Number}, param{"day", checker.Number},
+		param{"hour", checker.Number}, param{"minute", checker.Number}, param{"second", checker.Number}, zone)
+	date.Optional = 4
+	m.function("date", date, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		parts := make([]int, 6)
+		for i, name := range date.ParameterNames[:6] {
+			if i < 3 || arg(args, i) != nil {
+				parts[i] = intArg(in, args, i, name)
+			}
+		}
+		loc := location(in, args, 6)
+		return fromTime(time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, loc))
+	})
+
+	fields := signature(&checker.MapType{Key: checker.String, Value: checker.Number}, t, zone)
+	fields.Optional = 1
+	m.function("fields", fields, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		value := toTime(numberArg(in, args, 0, "t")).In(location(in, args, 1))
+		_, offset := value.Zone()
+
+		result := interpreter.NewMap()
+		for _, field := range []struct {
+			name  string
+			value int
+		}{
+			{"year", value.Year()}, {"month", int(value.Month())}, {"day", value.Day()},
+			{"hour", value.Hour()}, {"minute", value.Minute()}, {"second", value.Second()},
+			{"millisecond", value.Nanosecond() / int(time.Millisecond)},
+			{"weekday", int(value.Weekday())}, {"yearDay", value.YearDay()}, {"offset", offset},
+		} {
+			result.Set(field.name, float64(field.value))
+		}
+		return result
+	})
+
+	layout := param{"layout", checker.String}
+	format := signature(checker.String, t, layout, zone)
+	format.Optional = 1
+	m.function("format", format, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		value := toTime(numberArg(in, args, 0, "t")).In(location(in, args, 2))
+		return newString(in, value.Format(stringArg(in, args, 1, "layout")))
+	})
+
+	parse := signature(checker.Number, layout, param{"value", checker.String}, zone)
+	parse.Optional = 1
+	m.function("parse", parse, func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		value, err := time.ParseInLocation(stringArg(in, args, 0, "layout"), stringArg(in, args, 1, "value"), location(in, args, 2))
+		if err != nil {
+			in.Throwf("time.parse: %s", err)
+		}
+		return fromTime(value)
+	})
+
+	d := param{"d", checker.Number}
+	m.function("duration", signature(checker.Number, param{"s", checker.String}), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		value, err := time.ParseDuration(stringArg(in, args, 0, "s"))
+		if err != nil {
+			in.Throwf("time.duration: %s", err)
+		}
+		return fromDuration(value)
+	})
+
+	m.function("formatDuration", signature(checker.String, d), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		return newString(in, toDuration(in, args, 0).String())
+	})
+
+	m.function("sleep", signature(checker.Void, d), func(in *interpreter.Interpreter, args []interpreter.Value) interpreter.Value {
+		in.Require("time", "")
+		duration := toDuration(in, args, 0)
+		if duration <= 0 {
+			return nil
+		}
+
+		ctx := in.Context()
+		in.Block(func() {
+			clock.Sleep(ctx, duration)
+		})
+		return nil
+	})
+
+	return m
+}
+
+// location 返回第 i 个实参表示的时区，没有传入时为 UTC。
+func location(in *interpreter.Interpreter, args []interpreter.Value, i int) *time.Location {
+	if arg(args, i) == nil {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(stringArg(in, args, i, "zone"))
+	if err != nil {
+		in.Throwf("unknown time zone %s", args[i])
+	}
+	return loc
+}
+
+// fromTime 把 t 转换为自 Unix 纪元以来的毫秒数，保留毫秒以下的部分。
+func fromTime(t time.Time) float64 {
+	return float64(t.UnixMilli()) + float64(t.Nanosecond()%int(time.Millisecond))/float64(time.Millisecond)
+}
+
+// toTime 把自 Unix 纪元以来的毫秒数 ms 转换为 time.Time。
+func toTime(ms float64) time.Time {
+	whole := math.Floor(ms)
+	return time.UnixMilli(int64(whole)).Add(time.Duration((ms - whole) * float64(time.Millisecond))).UTC()
+}
+
+func fromDuration(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// toDuration 把第 i 个实参表示的毫秒数转换为 time.Duration，超出范围时抛出错误。
+func toDuration(in *interpreter.Interpreter, args []interpreter.Value, i int) time.Duration {
+	ms := numberArg(in, args, i, "d")
+	ns := ms * float64(time.Millisecond)
+	if math.IsNaN(ns) || ns >= math.MaxInt64 || ns < math.MinInt64 {
+		in.Throwf("duration %v ms out of range", ms)
+	}
+
+	return time.Duration(ns)
+}
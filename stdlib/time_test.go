@@ -0,0 +1,45 @@
+package stdlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"dreamlang"
+	"dreamlang/stdlib"
+)
+
+func TestTimeClock(t *testing.T) {
+	clock := stdlib.NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	got, err := eval(t, dreamlang.Options{Clock: clock}, []string{"time"}, `
+		import time from "time";
+		let start = time.now();
+		time.sleep(2 * time.second);
+		[time.format(start, time.DateTime), time.since(start)];`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{"2024-01-02 03:04:05", 2000.0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestTimeCapability(t *testing.T) {
+	for _, call := range []string{"time.now();", "time.since(0);", "time.sleep(1);"} {
+		t.Run(call, func(t *testing.T) {
+			_, err := eval(t, dreamlang.Options{Clock: stdlib.NewFakeClock(time.Unix(0, 0))}, nil, `import time from "time"; `+call)
+			if err == nil || !strings.Contains(err.Error(), "permission denied: time") {
+				t.Fatalf("err = %v, want permission denied", err)
+			}
+		})
+	}
+
+	t.Run("calculations need no capability", func(t *testing.T) {
+		got, err := eval(t, dreamlang.Options{}, nil, `import time from "time"; time.format(time.date(2024, 1, 2), time.DateOnly);`)
+		if err != nil || got != "2024-01-02" {
+			t.Fatalf("got %v, %v; want 2024-01-02", got, err)
+		}
+	})
+}